@@ -0,0 +1,237 @@
+package gosecret
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+// String returns a human-readable name for a ProviderID.
+func (p ProviderID) String() (s string) {
+
+	switch p {
+	case ProviderUnknown:
+		s = "unknown"
+	case ProviderGnomeKeyring:
+		s = "gnome-keyring"
+	case ProviderKeePassXC:
+		s = "KeePassXC"
+	case ProviderKWallet:
+		s = "KWallet"
+	default:
+		s = fmt.Sprintf("ProviderID(%d)", int(p))
+	}
+
+	return
+}
+
+/*
+	DetectCapabilities introspects the SecretService (via org.freedesktop.DBus.Introspectable) on the Service
+	path, its Collections and the first Item found, and identifies the process owning the SecretService bus name.
+	The result is stored in Service.Capabilities and Service.Legacy is set from it.
+
+	NewService calls this automatically unless WithLegacy or WithoutCapabilityDetection is used.
+
+	err is only non-nil if the Service itself could not be introspected; caps is still returned
+	(with Partial set) in that case.
+*/
+func (s *Service) DetectCapabilities() (caps *Capabilities, err error) {
+
+	caps, err = s.DetectCapabilitiesContext(context.Background())
+
+	return
+}
+
+/*
+	GetCapabilities returns a copy of Service.Capabilities.
+	ok is false if capabilities have not been detected (see Service.DetectCapabilities).
+*/
+func (s *Service) GetCapabilities() (caps Capabilities, ok bool) {
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.Capabilities == nil {
+		return
+	}
+
+	caps = *s.Capabilities
+	ok = true
+
+	return
+}
+
+// DetectCapabilitiesContext is like Service.DetectCapabilities but uses ctx for the Dbus call(s).
+func (s *Service) DetectCapabilitiesContext(ctx context.Context) (caps *Capabilities, err error) {
+
+	var node *introspect.Node
+	var found bool
+	var c Capabilities = Capabilities{
+		// Assume current spec until proven otherwise.
+		ItemType: true,
+	}
+
+	c.ProcessName = s.providerProcess(ctx)
+	c.Provider = providerFromProcess(c.ProcessName)
+
+	defer func() {
+		c.Legacy = !c.ItemType
+		s.lock.Lock()
+		s.Capabilities = &c
+		s.Legacy = c.Legacy
+		s.lock.Unlock()
+		caps = &c
+	}()
+
+	if node, err = introspectContext(ctx, s.Dbus); err != nil {
+		c.Partial = true
+		return
+	}
+
+	for _, iface := range node.Interfaces {
+		switch iface.Name {
+		case DbusInterfaceGnomeKeyring:
+			c.Provider = ProviderGnomeKeyring
+			c.MasterPassword = hasMethod(iface, "UnlockWithMasterPassword") &&
+				hasMethod(iface, "CreateWithMasterPassword") && hasMethod(iface, "ChangeWithMasterPassword")
+		case DbusInterfaceService:
+			c.Aliases = hasMethod(iface, "ReadAlias") && hasMethod(iface, "SetAlias")
+		}
+	}
+
+	if c.ItemType, found = s.detectItemType(ctx); !found {
+		c.ItemType = true
+		c.Partial = true
+	}
+
+	return
+}
+
+/*
+	detectItemType introspects the first Item found in the Service's Collections and reports whether it has
+	the Type property. found is false if no Item could be introspected.
+*/
+func (s *Service) detectItemType(ctx context.Context) (itemType, found bool) {
+
+	var err error
+	var paths []dbus.ObjectPath
+	var collNode *introspect.Node
+	var itemNode *introspect.Node
+	var itemPath dbus.ObjectPath
+
+	if paths, err = pathsFromPathContext(ctx, s.Dbus, DbusServiceCollections); err != nil {
+		return
+	}
+
+	for _, p := range paths {
+		if collNode, err = introspectContext(ctx, s.Conn.Object(s.Dbus.Destination(), p)); err != nil {
+			continue
+		}
+		for _, child := range collNode.Children {
+			itemPath = dbus.ObjectPath(string(p) + "/" + child.Name)
+			if itemNode, err = introspectContext(ctx, s.Conn.Object(s.Dbus.Destination(), itemPath)); err != nil {
+				continue
+			}
+			for _, iface := range itemNode.Interfaces {
+				if iface.Name != DbusInterfaceItem {
+					continue
+				}
+				found = true
+				for _, prop := range iface.Properties {
+					if prop.Name == "Type" {
+						itemType = true
+						break
+					}
+				}
+				return
+			}
+		}
+	}
+
+	return
+}
+
+/*
+	providerProcess returns the name of the process owning the Service's bus name, if it can be determined.
+	This relies on procfs, so it is empty on non-Linux platforms.
+*/
+func (s *Service) providerProcess(ctx context.Context) (name string) {
+
+	var err error
+	var owner string
+	var pid uint32
+	var b []byte
+	var bus dbus.BusObject = s.Conn.Object(DbusBusName, dbus.ObjectPath(DbusBusPath))
+
+	if owner, err = nameOwner(ctx, s.Conn, s.Dbus.Destination()); err != nil {
+		return
+	}
+	if err = callContext(ctx, bus, DbusGetConnectionUnixProcessID, owner).Store(&pid); err != nil {
+		return
+	}
+	if b, err = os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid)); err != nil {
+		return
+	}
+
+	name = strings.TrimSpace(string(b))
+
+	return
+}
+
+/*
+	providerFromProcess maps a process name (as found in /proc/<pid>/comm, which is truncated
+	to 15 characters) to a ProviderID.
+*/
+func providerFromProcess(name string) (provider ProviderID) {
+
+	name = strings.ToLower(name)
+
+	switch {
+	case strings.HasPrefix(name, "gnome-keyring"):
+		provider = ProviderGnomeKeyring
+	case strings.HasPrefix(name, "keepassxc"):
+		provider = ProviderKeePassXC
+	case strings.HasPrefix(name, "ksecretd"), strings.HasPrefix(name, "kwalletd"):
+		provider = ProviderKWallet
+	default:
+		provider = ProviderUnknown
+	}
+
+	return
+}
+
+// introspectContext fetches and parses the introspection data of obj.
+func introspectContext(ctx context.Context, obj dbus.BusObject) (node *introspect.Node, err error) {
+
+	var data string
+	var n introspect.Node
+
+	if err = callContext(ctx, obj, DbusIntrospect).Store(&data); err != nil {
+		return
+	}
+	if err = xml.Unmarshal([]byte(data), &n); err != nil {
+		return
+	}
+
+	node = &n
+
+	return
+}
+
+// hasMethod returns true if iface has a method named name.
+func hasMethod(iface introspect.Interface, name string) (ok bool) {
+
+	for _, m := range iface.Methods {
+		if m.Name == name {
+			ok = true
+			return
+		}
+	}
+
+	return
+}
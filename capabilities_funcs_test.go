@@ -0,0 +1,99 @@
+package gosecret
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/server"
+)
+
+/*
+	TestService_DetectCapabilities tests the following internal functions/methods via nested calls:
+
+		NewService
+			WithDestination
+			WithoutCapabilityDetection
+		Service.DetectCapabilities
+			introspectContext
+			Service.detectItemType
+			Service.providerProcess
+				providerFromProcess
+
+	It requires the private test bus (see TestMain), as it starts a second (legacy draft spec) provider on it.
+*/
+func TestService_DetectCapabilities(t *testing.T) {
+
+	var err error
+	var svc *Service
+	var conn *dbus.Conn
+	var srv *server.Server
+	var caps *Capabilities
+	var storage *server.MemoryStorage = server.NewMemoryStorage()
+	var now time.Time = time.Now()
+	var legacyName string = "org.example.gosecret.Legacy"
+
+	if os.Getenv(envLiveTests) != "" {
+		t.Skip("capability detection against a legacy provider requires the private test bus")
+	}
+
+	// The default (current spec) provider. It has no Items yet, so detection is partial.
+	if svc, err = NewService(); err != nil {
+		t.Fatalf("could not get new Service via NewService: %v", err.Error())
+	}
+	if svc.Capabilities == nil {
+		t.Fatalf("NewService did not detect Capabilities")
+	}
+	if svc.Legacy || svc.Capabilities.Legacy || !svc.Capabilities.ItemType || !svc.Capabilities.Aliases {
+		t.Errorf("unexpected Capabilities for current spec provider: %#v", svc.Capabilities)
+	}
+	if svc.Capabilities.Provider != ProviderUnknown {
+		t.Errorf("test provider identified as %v", svc.Capabilities.Provider)
+	}
+	t.Logf("Capabilities: %#v", svc.Capabilities)
+	if err = svc.Close(); err != nil {
+		t.Errorf("could not close Service: %v", err.Error())
+	}
+
+	// A legacy provider, with an Item to introspect.
+	if err = storage.PutCollection(&server.CollectionRecord{
+		ID: defaultCollection, Label: defaultCollectionLabel, Created: now, Modified: now,
+	}); err != nil {
+		t.Fatalf("could not create collection: %v", err.Error())
+	}
+	if err = storage.PutItem(defaultCollection, &server.ItemRecord{
+		ID: "1", Label: testItemLabel, Attributes: map[string]string{"foo": "bar"}, Secret: []byte(testSecretContent),
+		ContentType: "text/plain", Created: now, Modified: now,
+	}); err != nil {
+		t.Fatalf("could not create item: %v", err.Error())
+	}
+	if conn, err = dbus.ConnectSessionBus(); err != nil {
+		t.Fatalf("could not connect to session bus: %v", err.Error())
+	}
+	defer conn.Close()
+	if srv, err = server.New(conn, storage); err != nil {
+		t.Fatalf("could not start legacy provider: %v", err.Error())
+	}
+	defer srv.Close()
+	srv.Legacy = true
+	if err = srv.RequestName(legacyName); err != nil {
+		t.Fatalf("could not request name for legacy provider: %v", err.Error())
+	}
+
+	if svc, err = NewService(WithDestination(legacyName), WithoutCapabilityDetection()); err != nil {
+		t.Fatalf("could not get new Service via NewService: %v", err.Error())
+	}
+	if svc.Capabilities != nil || svc.Legacy {
+		t.Errorf("WithoutCapabilityDetection still detected Capabilities")
+	}
+	if caps, err = svc.DetectCapabilities(); err != nil {
+		t.Errorf("could not detect Capabilities: %v", err.Error())
+	}
+	if caps != svc.Capabilities || !caps.Legacy || caps.ItemType || caps.Partial || !svc.Legacy {
+		t.Errorf("unexpected Capabilities for legacy provider: %#v", caps)
+	}
+	if err = svc.Close(); err != nil {
+		t.Errorf("could not close Service: %v", err.Error())
+	}
+}
@@ -1,6 +1,8 @@
 package gosecret
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/godbus/dbus/v5"
@@ -10,11 +12,26 @@ import (
 /*
 	NewCollection returns a pointer to a Collection based on a Service and a Dbus path.
 	You will almost always want to use Service.GetCollection instead.
+
+	If FlagCollectionLoadItems is specified in flags, the Collection's Items (metadata only; see NewItem)
+	are loaded into Collection.LoadedItems. If loading an Item fails, err MAY be a *multierr.MultiError;
+	coll will still be returned in that case.
 */
-func NewCollection(service *Service, path dbus.ObjectPath) (coll *Collection, err error) {
+func NewCollection(service *Service, path dbus.ObjectPath, flags ...CollectionInitFlag) (coll *Collection, err error) {
+
+	coll, err = NewCollectionContext(context.Background(), service, path, flags...)
+
+	return
+}
+
+// NewCollectionContext is like NewCollection but uses ctx for the Dbus call(s).
+func NewCollectionContext(
+	ctx context.Context, service *Service, path dbus.ObjectPath, flags ...CollectionInitFlag,
+) (coll *Collection, err error) {
 
 	if service == nil {
 		err = ErrNoDbusConn
+		return
 	}
 
 	if _, err = validConnPath(service.Conn, path); err != nil {
@@ -24,25 +41,19 @@ func NewCollection(service *Service, path dbus.ObjectPath) (coll *Collection, er
 	coll = &Collection{
 		DbusObject: &DbusObject{
 			Conn: service.Conn,
-			Dbus: service.Conn.Object(DbusService, path),
+			Dbus: service.Conn.Object(service.Dbus.Destination(), path),
 		},
 		service: service,
 		// LastModified: time.Now(),
 	}
 
 	// Populate the struct fields...
-	// TODO: use channel for errors; condense into a MultiError and switch to goroutines.
-	if _, err = coll.Locked(); err != nil {
-		return
-	}
-	if _, err = coll.Label(); err != nil {
+	if err = coll.RefreshContext(ctx); err != nil {
 		return
 	}
-	if _, err = coll.Created(); err != nil {
-		return
-	}
-	if _, _, err = coll.Modified(); err != nil {
-		return
+
+	if hasCollectionFlag(flags, FlagCollectionLoadItems) {
+		coll.LoadedItems, err = coll.ItemsContext(ctx)
 	}
 
 	return
@@ -63,16 +74,31 @@ func NewCollection(service *Service, path dbus.ObjectPath) (coll *Collection, er
 	These are libsecret schemas as defined at
 	https://gitlab.gnome.org/GNOME/libsecret/-/blob/master/libsecret/secret-schemas.c (and bundled in with libsecret).
 	Support for adding custom schemas MAY come in the future but is unsupported currently.
+
+	secret's Value is encrypted automatically if its Session (or the Service's Session, if it has none) is encrypted.
 */
 func (c *Collection) CreateItem(label string, attrs map[string]string, secret *Secret, replace bool, itemType ...string) (item *Item, err error) {
 
+	item, err = c.CreateItemContext(context.Background(), label, attrs, secret, replace, itemType...)
+
+	return
+}
+
+/*
+	CreateItemContext is like Collection.CreateItem but uses ctx for the Dbus call(s).
+	If a Prompt is required, it is passed (with ctx) to the Service.Prompter.
+*/
+func (c *Collection) CreateItemContext(
+	ctx context.Context, label string, attrs map[string]string, secret *Secret, replace bool, itemType ...string,
+) (item *Item, err error) {
+
 	var call *dbus.Call
-	var prompt *Prompt
 	var path dbus.ObjectPath
 	var promptPath dbus.ObjectPath
 	var variant *dbus.Variant
 	var props map[string]dbus.Variant = make(map[string]dbus.Variant)
 	var typeString string
+	var wire *Secret
 
 	if itemType != nil && len(itemType) > 0 {
 		typeString = itemType[0]
@@ -81,15 +107,19 @@ func (c *Collection) CreateItem(label string, attrs map[string]string, secret *S
 	}
 
 	props[DbusItemLabel] = dbus.MakeVariant(label)
-	if !c.service.Legacy {
+	if !c.service.IsLegacy() {
 		props[DbusItemType] = dbus.MakeVariant(typeString)
 	}
 	props[DbusItemAttributes] = dbus.MakeVariant(attrs)
 	props[DbusItemCreated] = dbus.MakeVariant(uint64(time.Now().Unix()))
 	// props[DbusItemModified] = dbus.MakeVariant(uint64(time.Now().Unix()))
 
-	if call = c.Dbus.Call(
-		DbusCollectionCreateItem, 0, props, secret, replace,
+	if wire, err = secret.wireSession(c.service.CurrentSession()).encodeSecret(secret); err != nil {
+		return
+	}
+
+	if call = callContext(
+		ctx, c.Dbus, DbusCollectionCreateItem, props, wire, replace,
 	); call.Err != nil {
 		err = call.Err
 		return
@@ -99,16 +129,105 @@ func (c *Collection) CreateItem(label string, attrs map[string]string, secret *S
 	}
 
 	if isPrompt(promptPath) {
-		prompt = NewPrompt(c.Conn, promptPath)
-
-		if variant, err = prompt.Prompt(); err != nil {
+		if variant, err = c.service.handlePrompt(ctx, promptPath); err != nil {
 			return
 		}
 
 		path = variant.Value().(dbus.ObjectPath)
 	}
 
-	item, err = NewItem(c, path)
+	item, err = NewItemContext(ctx, c, path)
+
+	return
+}
+
+/*
+	Upsert creates or updates the Item in the Collection whose attributes are exactly attrs.
+
+	If such an Item exists, its Secret is replaced with secret (via Item.SetSecret) and it is relabeled to label
+	(and its type changed to itemType, if given) in place; otherwise a new Item is created as with Collection.CreateItem.
+	Unlike CreateItem's replace, this does not depend on how the SecretService implements replacing.
+	If more than one Item matches exactly, the first (by Dbus path; see Collection.Items) is updated.
+	Items that can not be loaded are skipped.
+
+	Upserts of the same Collection through the same Service are serialized, so concurrent calls with the same attrs
+	never both create an Item. The SecretService API offers no way to make the search and creation atomic, however,
+	so a different Service (or program) creating a matching Item at the same time may still result in duplicates.
+
+	created is true if a new Item was created.
+*/
+func (c *Collection) Upsert(
+	label string, attrs map[string]string, secret *Secret, itemType ...string,
+) (item *Item, created bool, err error) {
+
+	item, created, err = c.UpsertContext(context.Background(), label, attrs, secret, itemType...)
+
+	return
+}
+
+// UpsertContext is like Collection.Upsert but uses ctx for the Dbus call(s).
+func (c *Collection) UpsertContext(
+	ctx context.Context, label string, attrs map[string]string, secret *Secret, itemType ...string,
+) (item *Item, created bool, err error) {
+
+	var paths []dbus.ObjectPath
+	var candidates []*Item
+	var info ItemInfo
+	var lock *sync.Mutex
+
+	if attrs == nil || len(attrs) == 0 {
+		err = ErrMissingAttrs
+		return
+	}
+
+	lock = c.service.upsertLock(c.path())
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err = callContext(
+		ctx, c.Dbus, DbusCollectionSearchItems, attrs,
+	).Store(&paths); err != nil {
+		return
+	}
+
+	// Candidates that fail to load (err is then a *multierr.MultiError) are skipped; the search itself succeeded.
+	if candidates, _ = c.itemsFromPaths(ctx, paths); ctx.Err() != nil {
+		err = ctx.Err()
+		return
+	}
+
+	// SearchItems matches on a subset of the attributes; find an exact match.
+	for _, candidate := range candidates {
+		if attrsEqual(candidate.Info().Attributes, attrs) {
+			item = candidate
+			break
+		}
+	}
+
+	if item == nil {
+		if item, err = c.CreateItemContext(ctx, label, attrs, secret, false, itemType...); err != nil {
+			return
+		}
+		created = true
+		return
+	}
+
+	if err = item.SetSecretContext(ctx, secret); err != nil {
+		return
+	}
+
+	info = item.Info()
+
+	if info.Label != label {
+		if err = item.RelabelContext(ctx, label); err != nil {
+			return
+		}
+	}
+	if itemType != nil && len(itemType) > 0 && info.Type != itemType[0] {
+		if err = item.ChangeItemTypeContext(ctx, itemType[0]); err != nil {
+			return
+		}
+	}
 
 	return
 }
@@ -122,12 +241,22 @@ func (c *Collection) CreateItem(label string, attrs map[string]string, secret *S
 */
 func (c *Collection) Delete() (err error) {
 
+	err = c.DeleteContext(context.Background())
+
+	return
+}
+
+/*
+	DeleteContext is like Collection.Delete but uses ctx for the Dbus call(s).
+	If a Prompt is required, it is passed (with ctx) to the Service.Prompter.
+*/
+func (c *Collection) DeleteContext(ctx context.Context) (err error) {
+
 	var call *dbus.Call
 	var promptPath dbus.ObjectPath
-	var prompt *Prompt
 
-	if call = c.Dbus.Call(
-		DbusCollectionDelete, 0,
+	if call = callContext(
+		ctx, c.Dbus, DbusCollectionDelete,
 	); call.Err != nil {
 		err = call.Err
 		return
@@ -138,8 +267,7 @@ func (c *Collection) Delete() (err error) {
 
 	if isPrompt(promptPath) {
 
-		prompt = NewPrompt(c.Conn, promptPath)
-		if _, err = prompt.Prompt(); err != nil {
+		if _, err = c.service.handlePrompt(ctx, promptPath); err != nil {
 			return
 		}
 	}
@@ -147,38 +275,49 @@ func (c *Collection) Delete() (err error) {
 	return
 }
 
+// Info returns a copy of the Collection's cached fields. It does not perform any Dbus calls; see Collection.Refresh.
+func (c *Collection) Info() (info CollectionInfo) {
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	info = CollectionInfo{
+		Path:     c.Dbus.Path(),
+		Label:    c.LabelName,
+		Alias:    c.Alias,
+		Locked:   c.IsLocked,
+		Created:  c.CreatedAt,
+		Modified: c.LastModified,
+	}
+
+	return
+}
+
 /*
-	Items returns a slice of Item pointers in the Collection.
+	Items returns a slice of Item pointers in the Collection, ordered by Dbus path.
+	Numeric path elements are compared numerically (so .../login/2 comes before .../login/10),
+	which for SecretService implementations that number Items sequentially (e.g. gnome-keyring) is creation order.
+	They are constructed concurrently (see Service.Parallelism).
+	Secrets are not fetched unless FlagItemLoadSecret is specified in flags (see NewItem).
 	err MAY be a *multierr.MultiError.
 */
-func (c *Collection) Items() (items []*Item, err error) {
+func (c *Collection) Items(flags ...ItemInitFlag) (items []*Item, err error) {
 
-	var paths []dbus.ObjectPath
-	var item *Item
-	var variant dbus.Variant
-	var errs *multierr.MultiError = multierr.NewMultiError()
+	items, err = c.ItemsContext(context.Background(), flags...)
 
-	if variant, err = c.Dbus.GetProperty(DbusCollectionItems); err != nil {
-		return
-	}
+	return
+}
 
-	paths = variant.Value().([]dbus.ObjectPath)
+// ItemsContext is like Collection.Items but uses ctx for the Dbus call(s).
+func (c *Collection) ItemsContext(ctx context.Context, flags ...ItemInitFlag) (items []*Item, err error) {
 
-	items = make([]*Item, 0)
+	var paths []dbus.ObjectPath
 
-	for _, path := range paths {
-		item = nil
-		if item, err = NewItem(c, path); err != nil {
-			errs.AddError(err)
-			err = nil
-			continue
-		}
-		items = append(items, item)
+	if paths, err = pathsFromPathContext(ctx, c.Dbus, DbusCollectionItems); err != nil {
+		return
 	}
 
-	if !errs.IsEmpty() {
-		err = errs
-	}
+	items, err = c.itemsFromPaths(ctx, paths, flags...)
 
 	return
 }
@@ -186,15 +325,25 @@ func (c *Collection) Items() (items []*Item, err error) {
 // Label returns the Collection label (name).
 func (c *Collection) Label() (label string, err error) {
 
+	label, err = c.LabelContext(context.Background())
+
+	return
+}
+
+// LabelContext is like Collection.Label but uses ctx for the Dbus call.
+func (c *Collection) LabelContext(ctx context.Context) (label string, err error) {
+
 	var variant dbus.Variant
 
-	if variant, err = c.Dbus.GetProperty(DbusCollectionLabel); err != nil {
+	if variant, err = getPropertyContext(ctx, c.Dbus, DbusCollectionLabel); err != nil {
 		return
 	}
 
 	label = variant.Value().(string)
 
+	c.lock.Lock()
 	c.LabelName = label
+	c.lock.Unlock()
 
 	return
 }
@@ -202,19 +351,33 @@ func (c *Collection) Label() (label string, err error) {
 // Lock will lock an unlocked Collection. It will no-op if the Collection is currently locked.
 func (c *Collection) Lock() (err error) {
 
-	if _, err = c.Locked(); err != nil {
+	err = c.LockContext(context.Background())
+
+	return
+}
+
+// LockContext is like Collection.Lock but uses ctx for the Dbus call(s).
+func (c *Collection) LockContext(ctx context.Context) (err error) {
+
+	var isLocked bool
+	var result *LockResult
+
+	if isLocked, err = c.LockedContext(ctx); err != nil {
 		return
 	}
-	if c.IsLocked {
+	if isLocked {
 		return
 	}
 
-	if err = c.service.Lock(c); err != nil {
+	if result, err = c.service.LockContext(ctx, c); err != nil {
+		err = singleLockErr(result, err)
 		return
 	}
+	c.lock.Lock()
 	c.IsLocked = true
+	c.lock.Unlock()
 
-	if _, _, err = c.Modified(); err != nil {
+	if _, _, err = c.ModifiedContext(ctx); err != nil {
 		return
 	}
 
@@ -224,15 +387,25 @@ func (c *Collection) Lock() (err error) {
 // Locked indicates if a Collection is locked (true) or unlocked (false).
 func (c *Collection) Locked() (isLocked bool, err error) {
 
+	isLocked, err = c.LockedContext(context.Background())
+
+	return
+}
+
+// LockedContext is like Collection.Locked but uses ctx for the Dbus call.
+func (c *Collection) LockedContext(ctx context.Context) (isLocked bool, err error) {
+
 	var variant dbus.Variant
 
-	if variant, err = c.Dbus.GetProperty(DbusCollectionLocked); err != nil {
+	if variant, err = getPropertyContext(ctx, c.Dbus, DbusCollectionLocked); err != nil {
 		isLocked = true
 		return
 	}
 
 	isLocked = variant.Value().(bool)
+	c.lock.Lock()
 	c.IsLocked = isLocked
+	c.lock.Unlock()
 
 	return
 }
@@ -240,14 +413,24 @@ func (c *Collection) Locked() (isLocked bool, err error) {
 // Relabel modifies the Collection's label in Dbus.
 func (c *Collection) Relabel(newLabel string) (err error) {
 
+	err = c.RelabelContext(context.Background(), newLabel)
+
+	return
+}
+
+// RelabelContext is like Collection.Relabel but uses ctx for the Dbus call(s).
+func (c *Collection) RelabelContext(ctx context.Context, newLabel string) (err error) {
+
 	var variant dbus.Variant = dbus.MakeVariant(newLabel)
 
-	if err = c.Dbus.SetProperty(DbusCollectionLabel, variant); err != nil {
+	if err = setPropertyContext(ctx, c.Dbus, DbusCollectionLabel, variant); err != nil {
 		return
 	}
+	c.lock.Lock()
 	c.LabelName = newLabel
+	c.lock.Unlock()
 
-	if _, _, err = c.Modified(); err != nil {
+	if _, _, err = c.ModifiedContext(ctx); err != nil {
 		return
 	}
 
@@ -260,22 +443,33 @@ func (c *Collection) Relabel(newLabel string) (err error) {
 
 	I promise it's not useful for any other implementation/storage of SecretService whatsoever.
 
+	Secrets are not fetched unless FlagItemLoadSecret is specified in flags (see NewItem).
 	err MAY be a *multierr.MultiError.
 
 	Deprecated: Use Service.SearchItems instead.
 */
-func (c *Collection) SearchItems(profile string) (items []*Item, err error) {
+func (c *Collection) SearchItems(profile string, flags ...ItemInitFlag) (items []*Item, err error) {
+
+	items, err = c.SearchItemsContext(context.Background(), profile, flags...)
+
+	return
+}
+
+/*
+	SearchItemsContext is like Collection.SearchItems but uses ctx for the Dbus call(s).
+
+	Deprecated: Use Service.SearchItemsContext instead.
+*/
+func (c *Collection) SearchItemsContext(ctx context.Context, profile string, flags ...ItemInitFlag) (items []*Item, err error) {
 
 	var call *dbus.Call
 	var paths []dbus.ObjectPath
-	var errs *multierr.MultiError = multierr.NewMultiError()
 	var attrs map[string]string = make(map[string]string, 0)
-	var item *Item
 
 	attrs["profile"] = profile
 
-	if call = c.Dbus.Call(
-		DbusCollectionSearchItems, 0, attrs,
+	if call = callContext(
+		ctx, c.Dbus, DbusCollectionSearchItems, attrs,
 	); call.Err != nil {
 		err = call.Err
 		return
@@ -284,21 +478,7 @@ func (c *Collection) SearchItems(profile string) (items []*Item, err error) {
 		return
 	}
 
-	items = make([]*Item, 0)
-
-	for _, path := range paths {
-		item = nil
-		if item, err = NewItem(c, path); err != nil {
-			errs.AddError(err)
-			err = nil
-			continue
-		}
-		items = append(items, item)
-	}
-
-	if !errs.IsEmpty() {
-		err = errs
-	}
+	items, err = c.itemsFromPaths(ctx, paths, flags...)
 
 	return
 }
@@ -306,18 +486,28 @@ func (c *Collection) SearchItems(profile string) (items []*Item, err error) {
 // SetAlias is a thin wrapper/shorthand for Service.SetAlias (but specific to this Collection).
 func (c *Collection) SetAlias(alias string) (err error) {
 
+	err = c.SetAliasContext(context.Background(), alias)
+
+	return
+}
+
+// SetAliasContext is like Collection.SetAlias but uses ctx for the Dbus call(s).
+func (c *Collection) SetAliasContext(ctx context.Context, alias string) (err error) {
+
 	var call *dbus.Call
 
-	if call = c.service.Dbus.Call(
-		DbusServiceSetAlias, 0, alias, c.Dbus.Path(),
+	if call = callContext(
+		ctx, c.service.Dbus, DbusServiceSetAlias, alias, c.Dbus.Path(),
 	); call.Err != nil {
 		err = call.Err
 		return
 	}
 
+	c.lock.Lock()
 	c.Alias = alias
+	c.lock.Unlock()
 
-	if _, _, err = c.Modified(); err != nil {
+	if _, _, err = c.ModifiedContext(ctx); err != nil {
 		return
 	}
 
@@ -327,19 +517,36 @@ func (c *Collection) SetAlias(alias string) (err error) {
 // Unlock will unlock a locked Collection. It will no-op if the Collection is currently unlocked.
 func (c *Collection) Unlock() (err error) {
 
-	if _, err = c.Locked(); err != nil {
+	err = c.UnlockContext(context.Background())
+
+	return
+}
+
+/*
+	UnlockContext is like Collection.Unlock but uses ctx for the Dbus call(s).
+	If a Prompt is required, it is passed (with ctx) to the Service.Prompter.
+*/
+func (c *Collection) UnlockContext(ctx context.Context) (err error) {
+
+	var isLocked bool
+	var result *LockResult
+
+	if isLocked, err = c.LockedContext(ctx); err != nil {
 		return
 	}
-	if !c.IsLocked {
+	if !isLocked {
 		return
 	}
 
-	if err = c.service.Unlock(c); err != nil {
+	if result, err = c.service.UnlockContext(ctx, c); err != nil {
+		err = singleLockErr(result, err)
 		return
 	}
+	c.lock.Lock()
 	c.IsLocked = false
+	c.lock.Unlock()
 
-	if _, _, err = c.Modified(); err != nil {
+	if _, _, err = c.ModifiedContext(ctx); err != nil {
 		return
 	}
 
@@ -349,17 +556,27 @@ func (c *Collection) Unlock() (err error) {
 // Created returns the time.Time of when a Collection was created.
 func (c *Collection) Created() (created time.Time, err error) {
 
+	created, err = c.CreatedContext(context.Background())
+
+	return
+}
+
+// CreatedContext is like Collection.Created but uses ctx for the Dbus call.
+func (c *Collection) CreatedContext(ctx context.Context) (created time.Time, err error) {
+
 	var variant dbus.Variant
 	var timeInt uint64
 
-	if variant, err = c.Dbus.GetProperty(DbusCollectionCreated); err != nil {
+	if variant, err = getPropertyContext(ctx, c.Dbus, DbusCollectionCreated); err != nil {
 		return
 	}
 
 	timeInt = variant.Value().(uint64)
 
 	created = time.Unix(int64(timeInt), 0)
+	c.lock.Lock()
 	c.CreatedAt = created
+	c.lock.Unlock()
 
 	return
 }
@@ -374,16 +591,158 @@ func (c *Collection) Created() (created time.Time, err error) {
 */
 func (c *Collection) Modified() (modified time.Time, isChanged bool, err error) {
 
+	modified, isChanged, err = c.ModifiedContext(context.Background())
+
+	return
+}
+
+// ModifiedContext is like Collection.Modified but uses ctx for the Dbus call.
+func (c *Collection) ModifiedContext(ctx context.Context) (modified time.Time, isChanged bool, err error) {
+
 	var variant dbus.Variant
 	var timeInt uint64
 
-	if variant, err = c.Dbus.GetProperty(DbusCollectionModified); err != nil {
+	if variant, err = getPropertyContext(ctx, c.Dbus, DbusCollectionModified); err != nil {
 		return
 	}
 
 	timeInt = variant.Value().(uint64)
 
 	modified = time.Unix(int64(timeInt), 0)
+	c.lock.Lock()
+	isChanged = c.setModified(modified)
+	c.lock.Unlock()
+
+	return
+}
+
+/*
+	Refresh updates all of the Collection's cached fields (Collection.IsLocked, Collection.LabelName,
+	Collection.CreatedAt and Collection.LastModified) from Dbus. It does not reload Collection.LoadedItems.
+
+	All properties are fetched in a single org.freedesktop.DBus.Properties.GetAll call; if the SecretService
+	does not support that (UnknownMethod/NotSupported), each property is fetched individually instead.
+	Any other error is returned as-is.
+*/
+func (c *Collection) Refresh() (err error) {
+
+	err = c.RefreshContext(context.Background())
+
+	return
+}
+
+// RefreshContext is like Collection.Refresh but uses ctx for the Dbus call(s).
+func (c *Collection) RefreshContext(ctx context.Context) (err error) {
+
+	var props map[string]dbus.Variant
+	var gen uint64 = c.service.generation()
+
+	if err = c.service.withRetry(ctx, func() (err error) {
+		if props, err = getAllPropertiesContext(ctx, c.Dbus, DbusInterfaceCollection); err != nil {
+			if !errIsNotSupported(err) {
+				return
+			}
+			err = c.refreshEach(ctx)
+			return
+		}
+		err = c.applyProperties(props)
+		return
+	}); err != nil {
+		return
+	}
+
+	c.lock.Lock()
+	c.generation = gen
+	c.lock.Unlock()
+
+	return
+}
+
+// refreshEach is the fallback for Collection.RefreshContext if GetAll is not supported; it fetches each property separately.
+func (c *Collection) refreshEach(ctx context.Context) (err error) {
+
+	if _, err = c.LockedContext(ctx); err != nil {
+		return
+	}
+	if _, err = c.LabelContext(ctx); err != nil {
+		return
+	}
+	if _, err = c.CreatedContext(ctx); err != nil {
+		return
+	}
+	if _, _, err = c.ModifiedContext(ctx); err != nil {
+		return
+	}
+
+	return
+}
+
+/*
+	applyProperties populates the Collection's cached fields from the result of a GetAll call.
+	If any property is of the wrong type, err is ErrInvalidProperty and none of them are applied.
+*/
+func (c *Collection) applyProperties(props map[string]dbus.Variant) (err error) {
+
+	var ok bool
+	var v dbus.Variant
+	var locked bool
+	var label string
+	var created uint64
+	var modified uint64
+	var hasLocked bool
+	var hasLabel bool
+	var hasCreated bool
+	var hasModified bool
+
+	if v, hasLocked = props["Locked"]; hasLocked {
+		if locked, ok = v.Value().(bool); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+	if v, hasLabel = props["Label"]; hasLabel {
+		if label, ok = v.Value().(string); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+	if v, hasCreated = props["Created"]; hasCreated {
+		if created, ok = v.Value().(uint64); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+	if v, hasModified = props["Modified"]; hasModified {
+		if modified, ok = v.Value().(uint64); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if hasLocked {
+		c.IsLocked = locked
+	}
+	if hasLabel {
+		c.LabelName = label
+	}
+	if hasCreated {
+		c.CreatedAt = time.Unix(int64(created), 0)
+	}
+	if hasModified {
+		c.setModified(time.Unix(int64(modified), 0))
+	}
+
+	return
+}
+
+/*
+	setModified records modified as Collection.LastModified and returns true if it is newer than the previous value.
+	The caller must hold c.lock.
+*/
+func (c *Collection) setModified(modified time.Time) (isChanged bool) {
 
 	if !c.lastModifiedSet {
 		// It's "nil", so set it to modified. We can't check for a zero-value in case Dbus has it as a zero-value.
@@ -397,6 +756,104 @@ func (c *Collection) Modified() (modified time.Time, isChanged bool, err error)
 	return
 }
 
+/*
+	Watch subscribes to the SecretService's change signals for the Item objects in this Collection
+	and delivers them on events until ctx is cancelled (or the Dbus connection is closed), after which events is closed.
+
+	See Service.Watch for details.
+*/
+func (c *Collection) Watch(ctx context.Context) (events <-chan *WatchEvent, err error) {
+
+	var matches [][]dbus.MatchOption = [][]dbus.MatchOption{
+		{
+			dbus.WithMatchSender(c.Dbus.Destination()),
+			dbus.WithMatchObjectPath(c.path()),
+			dbus.WithMatchInterface(DbusInterfaceCollection),
+		},
+	}
+
+	events, err = watchSignals(
+		ctx, c.Conn, matches,
+		func(sig *dbus.Signal) (evt *WatchEvent) {
+
+			var ok bool
+			var evtType EventType
+			var objPath dbus.ObjectPath
+
+			if sig.Path != c.path() {
+				return
+			}
+			if evtType, ok = eventTypeFromSignal(sig.Name); !ok {
+				return
+			}
+			if objPath, ok = signalPath(sig); !ok {
+				return
+			}
+
+			evt = c.itemEvent(ctx, evtType, objPath)
+
+			return
+		},
+	)
+
+	return
+}
+
+// itemEvent returns a *WatchEvent for an Item event in this Collection, resolving the Item if it was not deleted.
+func (c *Collection) itemEvent(ctx context.Context, evtType EventType, itemPath dbus.ObjectPath) (evt *WatchEvent) {
+
+	evt = &WatchEvent{
+		Type:       evtType,
+		Path:       itemPath,
+		Collection: c,
+	}
+
+	if evtType == EventItemDeleted {
+		return
+	}
+
+	if evt.Item, evt.Err = NewItemContext(ctx, c, itemPath); evt.Err != nil {
+		evt.Item = nil
+	}
+
+	return
+}
+
+/*
+	itemsFromPaths constructs (concurrently; see Service.Parallelism) the Item objects at paths in this Collection.
+	items is ordered by Dbus path; err MAY be a *multierr.MultiError.
+*/
+func (c *Collection) itemsFromPaths(ctx context.Context, paths []dbus.ObjectPath, flags ...ItemInitFlag) (items []*Item, err error) {
+
+	var built []*Item
+	var errList []error
+	var errs *multierr.MultiError = multierr.NewMultiError()
+
+	paths = sortPaths(paths)
+	built = make([]*Item, len(paths))
+
+	errList = parallelEach(ctx, len(paths), c.service.parallelism(), func(idx int) (err error) {
+		built[idx], err = NewItemContext(ctx, c, paths[idx], flags...)
+		return
+	})
+
+	items = make([]*Item, 0, len(paths))
+
+	for idx, item := range built {
+		if errList[idx] != nil {
+			errs.AddError(errList[idx])
+			continue
+		}
+		items = append(items, item)
+	}
+
+	if !errs.IsEmpty() {
+		err = errs
+	}
+
+	return
+}
+
 // path is a *very* thin wrapper around Collection.Dbus.Path(). It is needed for LockableObject interface membership.
 func (c *Collection) path() (dbusPath dbus.ObjectPath) {
 
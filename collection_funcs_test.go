@@ -1,6 +1,8 @@
 package gosecret
 
 import (
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/godbus/dbus/v5"
@@ -129,6 +131,86 @@ func TestCollection_Items(t *testing.T) {
 	}
 }
 
+/*
+	TestCollection_LoadFlags tests the following internal functions/methods via nested calls:
+
+		(all calls in TestCollection_Items)
+		NewCollection
+			FlagCollectionLoadItems
+		NewItem
+			FlagItemNone
+			FlagItemLoadSecret
+
+*/
+func TestCollection_LoadFlags(t *testing.T) {
+
+	var svc *Service
+	var collection *Collection
+	var items []*Item
+	var item *Item
+	var found bool
+	var searchResultsUnlocked []*Item
+	var secret *Secret
+	var err error
+
+	if svc, err = NewService(); err != nil {
+		t.Fatalf("NewService failed: %v", err.Error())
+	}
+	defer svc.Close()
+
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	secret = NewSecret(svc.Session, []byte{}, []byte(testSecretContent), "text/plain")
+
+	if item, err = collection.CreateItem(testItemLabel, itemAttrs, secret, false); err != nil {
+		t.Fatalf("could not create item '%v' in collection '%v': %v", testItemLabel, defaultCollection, err.Error())
+	}
+	defer item.Delete()
+
+	// Metadata only.
+	if collection, err = NewCollection(svc, collection.Dbus.Path(), FlagCollectionLoadItems); err != nil {
+		t.Fatalf("NewCollection with FlagCollectionLoadItems failed: %v", err.Error())
+	}
+	if len(collection.LoadedItems) == 0 {
+		t.Errorf("FlagCollectionLoadItems did not load any items")
+	}
+	for _, i := range collection.LoadedItems {
+		if i.Secret != nil {
+			t.Errorf("item '%v' loaded its secret without FlagItemLoadSecret", string(i.Dbus.Path()))
+		}
+		if i.LabelName == "" {
+			t.Errorf("item '%v' did not load its label", string(i.Dbus.Path()))
+		}
+	}
+	if searchResultsUnlocked, _, err = svc.SearchItems(itemAttrs); err != nil {
+		t.Errorf("Service.SearchItems failed: %v", err.Error())
+	}
+	for _, i := range searchResultsUnlocked {
+		if i.Secret != nil {
+			t.Errorf("item '%v' loaded its secret without FlagItemLoadSecret", string(i.Dbus.Path()))
+		}
+	}
+
+	// With secrets.
+	if items, err = collection.Items(FlagItemLoadSecret); err != nil {
+		t.Errorf("Collection.Items with FlagItemLoadSecret failed: %v", err.Error())
+	}
+	for _, i := range items {
+		if i.Dbus.Path() != item.Dbus.Path() {
+			continue
+		}
+		found = true
+		if i.Secret == nil || string(i.Secret.Value) != testSecretContent {
+			t.Errorf("FlagItemLoadSecret did not load the secret of item '%v'", string(i.Dbus.Path()))
+		}
+	}
+	if !found {
+		t.Errorf("Collection.Items did not return item '%v'", string(item.Dbus.Path()))
+	}
+}
+
 /*
 	TestCollection_Label tests the following internal functions/methods via nested calls:
 
@@ -286,3 +368,145 @@ func TestCollection_Relabel(t *testing.T) {
 		t.Errorf("could not close Service.Session: %v", err.Error())
 	}
 }
+
+/*
+	TestCollection_Upsert tests the following internal functions/methods via nested calls:
+
+		(all calls in TestNewCollection)
+		WithSessionAlgorithm
+		Service.GetCollection
+		Collection.Upsert
+			Service.upsertLock
+			Collection.itemsFromPaths
+		Collection.CreateItem
+		Item.SetSecret
+		Item.GetSecret
+		Item.Relabel
+		Item.Delete
+
+*/
+func TestCollection_Upsert(t *testing.T) {
+
+	var svc *Service
+	var collection *Collection
+	var item *Item
+	var updated *Item
+	var other *Item
+	var created bool
+	var secret *Secret
+	var info ItemInfo
+	var err error
+	var wg sync.WaitGroup
+	var results chan bool
+	var numCreated int
+	var matches []*Item
+	var upsertWorkers int = 8
+	var attrs map[string]string = map[string]string{
+		"GOSECRET": "yes",
+		"upsert":   collectionName.String(),
+	}
+
+	if svc, err = NewService(WithSessionAlgorithm(SessionAlgoDH)); err != nil {
+		t.Fatalf("NewService failed: %v", err.Error())
+	}
+	defer svc.Close()
+
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	// An item whose attributes are a superset must not be matched.
+	secret = NewSecret(svc.Session, []byte{}, []byte("other"), "text/plain")
+	if other, err = collection.CreateItem(
+		testItemLabel+" (other)", map[string]string{"GOSECRET": "yes", "upsert": collectionName.String(), "x": "y"},
+		secret, false,
+	); err != nil {
+		t.Fatalf("could not create item in collection '%v': %v", defaultCollection, err.Error())
+	}
+	defer other.Delete()
+
+	// Create.
+	secret = NewSecret(svc.Session, []byte{}, []byte(testSecretContent), "text/plain")
+	if item, created, err = collection.Upsert(testItemLabel, attrs, secret); err != nil {
+		t.Fatalf("Collection.Upsert (create) failed: %v", err.Error())
+	}
+	defer item.Delete()
+	if !created {
+		t.Errorf("Collection.Upsert did not create a new item")
+	}
+	if item.Dbus.Path() == other.Dbus.Path() {
+		t.Errorf("Collection.Upsert matched an item with additional attributes")
+	}
+
+	// Update in place.
+	secret = NewSecret(svc.Session, []byte{}, []byte("updated"), "text/plain")
+	if updated, created, err = collection.Upsert(testItemLabel+" (updated)", attrs, secret); err != nil {
+		t.Fatalf("Collection.Upsert (update) failed: %v", err.Error())
+	}
+	if created {
+		t.Errorf("Collection.Upsert created a new item instead of updating '%v'", string(item.Dbus.Path()))
+	}
+	if updated.Dbus.Path() != item.Dbus.Path() {
+		t.Errorf("Collection.Upsert updated '%v'; expected '%v'", string(updated.Dbus.Path()), string(item.Dbus.Path()))
+	}
+
+	info = updated.Info()
+	if info.Label != testItemLabel+" (updated)" {
+		t.Errorf("Collection.Upsert did not relabel the item: '%v'", info.Label)
+	}
+	if secret, err = updated.GetSecret(svc.Session); err != nil {
+		t.Fatalf("Item.GetSecret failed: %v", err.Error())
+	}
+	if string(secret.Value) != "updated" {
+		t.Errorf("Collection.Upsert did not update the secret over a '%v' session: '%v'", SessionAlgoDH, string(secret.Value))
+	}
+
+	if _, _, err = collection.Upsert(testItemLabel, nil, secret); !errors.Is(err, ErrMissingAttrs) {
+		t.Errorf("Collection.Upsert without attributes returned %v; expected %v", err, ErrMissingAttrs)
+	}
+
+	// Concurrent upserts (even via separate Collection objects) of the same attributes create a single item.
+	attrs = map[string]string{"GOSECRET": "yes", "upsert-concurrent": collectionName.String()}
+	results = make(chan bool, upsertWorkers)
+	for idx := 0; idx < upsertWorkers; idx++ {
+		wg.Add(1)
+		go func() {
+			var err error
+			var c *Collection
+			var created bool
+			defer wg.Done()
+			if c, err = svc.GetCollection(defaultCollection); err != nil {
+				t.Errorf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+				return
+			}
+			if _, created, err = c.Upsert(
+				testItemLabel, attrs, NewSecret(svc.CurrentSession(), []byte{}, []byte("concurrent"), "text/plain"),
+			); err != nil {
+				t.Errorf("concurrent Collection.Upsert failed: %v", err.Error())
+				return
+			}
+			results <- created
+		}()
+	}
+	wg.Wait()
+	close(results)
+	for created = range results {
+		if created {
+			numCreated++
+		}
+	}
+	if numCreated != 1 {
+		t.Errorf("%d concurrent Collection.Upsert calls created %d items; expected 1", upsertWorkers, numCreated)
+	}
+	if matches, _, err = svc.SearchItems(attrs); err != nil {
+		t.Fatalf("Service.SearchItems failed: %v", err.Error())
+	}
+	if len(matches) != 1 {
+		t.Errorf("found %d items after concurrent Collection.Upsert calls; expected 1", len(matches))
+	}
+	for _, match := range matches {
+		if err = match.Delete(); err != nil {
+			t.Errorf("could not delete item '%v': %v", string(match.Dbus.Path()), err.Error())
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package gosecret
 
 import (
+	"time"
+
 	"github.com/godbus/dbus/v5"
 )
 
@@ -14,13 +16,30 @@ const (
 	ExplicitAttrEmptyValue string = "%EXPLICIT_GOSECRET_BLANK_VALUE_8A4E3D7D-F30E-4754-8C56-9C172D1400F6%"
 )
 
+// Password (libsecret-style) API.
+const (
+	// SchemaNameAttr is the attribute holding an Item's Schema.Name, as set by libsecret.
+	SchemaNameAttr string = "xdg:schema"
+	// CollectionDefault is the alias of the default Collection (used by Service.PasswordStore if none is given).
+	CollectionDefault string = "default"
+	// CollectionSession is the alias of the in-memory Collection that is discarded at logout.
+	CollectionSession string = "session"
+	// ContentTypeText is the content type of passwords stored via Service.PasswordStore.
+	ContentTypeText string = "text/plain"
+	// ContentTypeBinary is the content type of passwords stored via Service.PasswordStoreBinary.
+	ContentTypeBinary string = "application/octet-stream"
+)
+
 // Libsecret/SecretService Dbus interfaces.
 const (
 	// DbusService is the Dbus service bus identifier.
 	DbusService string = "org.freedesktop.secrets"
 	// DbusServiceBase is the base identifier used by interfaces.
 	DbusServiceBase string = "org.freedesktop.Secret"
-	// DbusPrompterInterface is an interface for issuing a Prompt. Yes, it should be doubled up like that.
+	/*
+		DbusPrompterInterface is an interface for issuing a Prompt. Yes, it should be doubled up like that.
+		(It is the Prompt method of the DbusInterfacePrompt interface.)
+	*/
 	DbusPrompterInterface string = DbusServiceBase + ".Prompt.Prompt"
 	/*
 		DbusDefaultItemType is the default type to use for Item.Type/Collection.CreateItem.
@@ -28,6 +47,54 @@ const (
 	DbusDefaultItemType string = DbusServiceBase + ".Generic"
 )
 
+/*
+	Session algorithms.
+	See https://specifications.freedesktop.org/secret-service/latest/ch07.html
+*/
+const (
+	// SessionAlgoPlain is the "plain" algorithm; Secret values are transferred across Dbus unencrypted.
+	SessionAlgoPlain string = "plain"
+	/*
+		SessionAlgoDH is the Diffie-Hellman key exchange algorithm.
+		A 1024-bit MODP group (RFC 2409, section 6.2) is used to negotiate a shared secret,
+		which is passed through HKDF-SHA256 to derive an AES-128 key.
+		Secret values are then encrypted with AES-128-CBC (PKCS#7 padding),
+		with a per-Secret IV passed in Secret.Parameters.
+	*/
+	SessionAlgoDH string = "dh-ietf1024-sha256-aes128-cbc-pkcs7"
+)
+
+// Dbus standard interfaces.
+const (
+	// DbusInterfaceIntrospectable is the standard Dbus interface for object introspection.
+	DbusInterfaceIntrospectable string = "org.freedesktop.DBus.Introspectable"
+	// DbusIntrospect returns an object's introspection data (XML).
+	DbusIntrospect string = DbusInterfaceIntrospectable + ".Introspect"
+	// DbusBusName is the bus name of the message bus itself.
+	DbusBusName string = "org.freedesktop.DBus"
+	// DbusBusPath is the path of the message bus itself.
+	DbusBusPath string = "/org/freedesktop/DBus"
+	// DbusGetNameOwner returns the unique connection name owning a well-known bus name.
+	DbusGetNameOwner string = DbusBusName + ".GetNameOwner"
+	// DbusGetConnectionUnixProcessID returns the PID of the process owning a connection.
+	DbusGetConnectionUnixProcessID string = DbusBusName + ".GetConnectionUnixProcessID"
+	// DbusStartServiceByName asks the bus to start (activate) the service providing a bus name.
+	DbusStartServiceByName string = DbusBusName + ".StartServiceByName"
+	// DbusNameHasOwner returns true if a bus name currently has an owner.
+	DbusNameHasOwner string = DbusBusName + ".NameHasOwner"
+	// DbusNameOwnerChanged is the signal emitted by the bus when a bus name changes owner (e.g. the SecretService restarts).
+	DbusNameOwnerChanged string = "NameOwnerChanged"
+
+	// DbusInterfaceProperties is the standard Dbus interface for fetching/setting object properties.
+	DbusInterfaceProperties string = "org.freedesktop.DBus.Properties"
+	// DbusPropertiesGet is used to fetch a single property.
+	DbusPropertiesGet string = DbusInterfaceProperties + ".Get"
+	// DbusPropertiesGetAll is used to fetch all of an interface's properties at once.
+	DbusPropertiesGetAll string = DbusInterfaceProperties + ".GetAll"
+	// DbusPropertiesSet is used to set a single property.
+	DbusPropertiesSet string = DbusInterfaceProperties + ".Set"
+)
+
 // Libsecret/SecretService special values.
 var (
 	// DbusRemoveAliasPath is used to remove an alias from a Collection and/or Item.
@@ -82,6 +149,17 @@ const (
 
 	// DbusServiceCollections is used to get a Dbus array of Collection items (Service.Collections).
 	DbusServiceCollections string = DbusInterfaceService + ".Collections"
+
+	// Signals
+
+	// DbusServiceCollectionCreated is emitted when a Collection is created (see Service.Watch).
+	DbusServiceCollectionCreated string = DbusInterfaceService + ".CollectionCreated"
+
+	// DbusServiceCollectionDeleted is emitted when a Collection is deleted (see Service.Watch).
+	DbusServiceCollectionDeleted string = DbusInterfaceService + ".CollectionDeleted"
+
+	// DbusServiceCollectionChanged is emitted when a Collection is changed (see Service.Watch).
+	DbusServiceCollectionChanged string = DbusInterfaceService + ".CollectionChanged"
 )
 
 // Session interface.
@@ -98,6 +176,31 @@ const (
 	DbusSessionClose string = DbusInterfaceSession + ".Close"
 )
 
+// Prompt interface.
+const (
+	/*
+		DbusInterfacePrompt is the Dbus interface for working with a Prompt.
+		Found at /org/freedesktop/secrets/prompt/<prompt ID>/(DbusInterfacePrompt)
+	*/
+	DbusInterfacePrompt string = DbusServiceBase + ".Prompt"
+
+	// Methods
+
+	// DbusPromptDismiss is used for Prompt.Dismiss.
+	DbusPromptDismiss string = DbusInterfacePrompt + ".Dismiss"
+
+	// Signals
+
+	// DbusPromptCompletedMember is the member name of the signal emitted when a Prompt is completed or dismissed.
+	DbusPromptCompletedMember string = "Completed"
+
+	/*
+		DbusPromptCompleted is the full name of the signal emitted when a Prompt is completed or dismissed
+		(as found in dbus.Signal.Name). Its body is a boolean (true if dismissed) and a variant (the result).
+	*/
+	DbusPromptCompleted string = DbusInterfacePrompt + "." + DbusPromptCompletedMember
+)
+
 // Collection interface.
 const (
 	/*
@@ -134,7 +237,16 @@ const (
 	// DbusCollectionModified is the time a Collection was last modified (in a UNIX Epoch uint64) for Collection.Modified.
 	DbusCollectionModified string = DbusInterfaceCollection + ".Modified"
 
-	// TODO: Signals?
+	// Signals
+
+	// DbusCollectionItemCreated is emitted when an Item is created in a Collection (see Service.Watch and Collection.Watch).
+	DbusCollectionItemCreated string = DbusInterfaceCollection + ".ItemCreated"
+
+	// DbusCollectionItemDeleted is emitted when an Item is deleted from a Collection (see Service.Watch and Collection.Watch).
+	DbusCollectionItemDeleted string = DbusInterfaceCollection + ".ItemDeleted"
+
+	// DbusCollectionItemChanged is emitted when an Item in a Collection is changed (see Service.Watch and Collection.Watch).
+	DbusCollectionItemChanged string = DbusInterfaceCollection + ".ItemChanged"
 )
 
 // Item interface.
@@ -180,6 +292,24 @@ const (
 	DbusItemModified string = DbusInterfaceItem + ".Modified"
 )
 
+// gnome-keyring-specific (and explicitly unsupported) interface and methods.
+const (
+	/*
+		DbusInterfaceGnomeKeyring is the gnome-keyring-specific (and explicitly unsupported) interface
+		exported on the Service path by gnome-keyring. Its presence identifies gnome-keyring (see Capabilities).
+	*/
+	DbusInterfaceGnomeKeyring string = "org.gnome.keyring.InternalUnsupportedGuiltRiddenInterface"
+
+	// DbusGnomeKeyringUnlockWithMasterPassword unlocks a Collection with its password, without a Prompt.
+	DbusGnomeKeyringUnlockWithMasterPassword string = DbusInterfaceGnomeKeyring + ".UnlockWithMasterPassword"
+
+	// DbusGnomeKeyringCreateWithMasterPassword creates a Collection protected by a password, without a Prompt.
+	DbusGnomeKeyringCreateWithMasterPassword string = DbusInterfaceGnomeKeyring + ".CreateWithMasterPassword"
+
+	// DbusGnomeKeyringChangeWithMasterPassword changes a Collection's password, without a Prompt.
+	DbusGnomeKeyringChangeWithMasterPassword string = DbusInterfaceGnomeKeyring + ".ChangeWithMasterPassword"
+)
+
 // Dbus paths.
 const (
 	// DbusPath is the path for DbusService.
@@ -192,47 +322,74 @@ const (
 	DbusNewSessionPath string = DbusPath + "/session/"
 )
 
+/*
+	DefaultParallelism is the default for Service.Parallelism; the maximum number of objects
+	(e.g. Item objects in Collection.Items) constructed concurrently.
+*/
+const DefaultParallelism int = 8
+
+/*
+	RedactedSecret is shown in place of a SecretValue when it is formatted (e.g. via fmt or log/slog)
+	or marshaled to JSON, unless revealed via MarshalOptions.
+*/
+const RedactedSecret string = "[REDACTED]"
+
+/*
+	ValueEncodingBase64 is the "value_encoding" of a Secret revealed via MarshalOptions whose Value is not valid UTF-8;
+	its "value" is then the standard base64 encoding of the Value rather than the Value itself.
+*/
+const ValueEncodingBase64 string = "base64"
+
 // FLAGS
-// These are not currently used, but may be in the future.
+// Not all of these are currently used, but may be in the future.
 
 // SERVICE
 
-// ServiceInitFlag is a flag for Service.OpenSession.
+// ServiceInitFlag is a flag for NewService (see WithFlags).
 type ServiceInitFlag int
 
 const (
 	FlagServiceNone ServiceInitFlag = iota
+	// FlagServiceOpenSession opens the default Session. This is the default; see WithoutDefaultSession.
 	FlagServiceOpenSession
+	// FlagServiceLoadCollections loads all Collection objects into Service.LoadedCollections.
 	FlagServiceLoadCollections
 )
 
-// ServiceSearchFlag is a flag for Service.SearchItems.
+// ServiceSearchFlag is a flag for Service.PasswordSearch (mirroring libsecret's SecretSearchFlags).
 type ServiceSearchFlag int
 
 const (
+	// FlagServiceSearchNone returns at most one (unlocked if possible) Item, without its Secret.
 	FlagServiceSearchNone ServiceSearchFlag = iota
+	// FlagServiceSearchAll returns all matching Items rather than just one.
 	FlagServiceSearchAll
+	// FlagServiceSearchUnlock unlocks (which may Prompt) any locked Items returned.
 	FlagServiceSearchUnlock
+	// FlagServiceSearchLoadSecrets fetches the Secret of the (unlocked) Items returned.
 	FlagServiceSearchLoadSecrets
 )
 
 // COLLECTION
 
-// CollectionInitFlag is a flag for Collection.SearchItems and Collection.Items.
+// CollectionInitFlag is a flag for NewCollection.
 type CollectionInitFlag int
 
 const (
 	FlagCollectionNone CollectionInitFlag = iota
+	// FlagCollectionLoadItems loads the Collection's Item objects (metadata only) into Collection.LoadedItems.
 	FlagCollectionLoadItems
 )
 
 // ITEM
 
-// ItemInitFlag are flags for Collection.SearchItems and Collection.Items.
+// ItemInitFlag are flags for NewItem, Collection.SearchItems, Collection.Items and Service.SearchItems.
 type ItemInitFlag int
 
 const (
+	// FlagItemNone only loads Item metadata; Item.Secret is nil until Item.GetSecret is called. This is the default.
 	FlagItemNone ItemInitFlag = iota
+	// FlagItemLoadSecret also fetches the Item's Secret (via the Service's Session) into Item.Secret.
 	FlagItemLoadSecret
 )
 
@@ -244,6 +401,59 @@ const (
 	FlatItemCreateReplace
 )
 
+// PROVIDERS
+
+// ProviderID identifies a SecretService implementation (see Capabilities).
+type ProviderID int
+
+const (
+	ProviderUnknown ProviderID = iota
+	ProviderGnomeKeyring
+	ProviderKeePassXC
+	ProviderKWallet
+)
+
+// EVENTS
+
+// EventType is the type of change a WatchEvent describes.
+type EventType int
+
+const (
+	EventUnknown EventType = iota
+	EventCollectionCreated
+	EventCollectionDeleted
+	EventCollectionChanged
+	EventItemCreated
+	EventItemDeleted
+	EventItemChanged
+)
+
+// SCHEMAS
+
+// SchemaAttrType is the type of a Schema attribute's values (mirroring libsecret's SecretSchemaAttributeType).
+type SchemaAttrType int
+
+const (
+	// SchemaAttrString values are Go strings, stored as-is.
+	SchemaAttrString SchemaAttrType = iota
+	// SchemaAttrInteger values are Go integers (of any size), stored in decimal.
+	SchemaAttrInteger
+	// SchemaAttrBoolean values are Go bools, stored as "true" or "false".
+	SchemaAttrBoolean
+)
+
+// SchemaFlag is a flag for Schema.Flags (mirroring libsecret's SecretSchemaFlags).
+type SchemaFlag int
+
+const (
+	FlagSchemaNone SchemaFlag = iota
+	/*
+		FlagSchemaDontMatchName doesn't restrict lookups/searches to Items with the Schema's SchemaNameAttr,
+		so that Items stored by programs that don't set it can be found.
+	*/
+	FlagSchemaDontMatchName
+)
+
 // ERRORS
 
 /*
@@ -260,4 +470,46 @@ const (
 	EnumErrNoSuchObject
 	EnumErrAlreadyExists
 	EnumErrInvalidFileFormat
+	EnumErrNoSession
+	EnumErrUnknownProperty
+)
+
+/*
+	Dbus error names returned by SecretService implementations.
+	See https://specifications.freedesktop.org/secret-service/latest/ch15.html
+	They are translated to SecretServiceError values by TranslateDbusError.
+*/
+const (
+	DbusErrIsLocked        string = DbusServiceBase + ".Error.IsLocked"
+	DbusErrNoSession       string = DbusServiceBase + ".Error.NoSession"
+	DbusErrNoSuchObject    string = DbusServiceBase + ".Error.NoSuchObject"
+	DbusErrAlreadyExists   string = DbusServiceBase + ".Error.AlreadyExists"
+	DbusErrUnknownProperty string = "org.freedesktop.DBus.Error.UnknownProperty"
+	DbusErrNotSupported    string = "org.freedesktop.DBus.Error.NotSupported"
+	// DbusErrUnknownObject is returned by the bus (rather than the SecretService) for nonexistent paths.
+	DbusErrUnknownObject string = "org.freedesktop.DBus.Error.UnknownObject"
+	// DbusErrUnknownMethod and DbusErrUnknownInterface are returned for methods/interfaces an object does not implement.
+	DbusErrUnknownMethod    string = "org.freedesktop.DBus.Error.UnknownMethod"
+	DbusErrUnknownInterface string = "org.freedesktop.DBus.Error.UnknownInterface"
+	// DbusErrAccessDenied is returned by gnome-keyring if a master password is wrong.
+	DbusErrAccessDenied string = "org.freedesktop.DBus.Error.AccessDenied"
+)
+
+/*
+	Dbus error names returned by the bus if the SecretService is not running (or went away mid-call).
+	With WithReconnect, these (and DbusErrNoSession) cause a Session re-establishment and one retry of idempotent calls.
+*/
+const (
+	DbusErrServiceUnknown string = "org.freedesktop.DBus.Error.ServiceUnknown"
+	DbusErrNameHasNoOwner string = "org.freedesktop.DBus.Error.NameHasNoOwner"
+	DbusErrNoReply        string = "org.freedesktop.DBus.Error.NoReply"
+	DbusErrDisconnected   string = "org.freedesktop.DBus.Error.Disconnected"
+)
+
+const (
+	/*
+		promptDismissTimeout bounds the Prompt.Dismiss sent when Prompt.PromptContext's context is cancelled;
+		that context is already done, and a hung SecretService must not block the cancellation.
+	*/
+	promptDismissTimeout time.Duration = 3 * time.Second
 )
@@ -18,6 +18,11 @@ const (
 	testAlias              string = "GOSECRET_TESTING_ALIAS"
 	testSecretContent      string = "This is a test secret for gosecret."
 	testItemLabel          string = "Gosecret Test Item"
+	/*
+		envLiveTests, if set (to anything) in the environment, runs the tests against the live session bus's
+		SecretService (e.g. gnome-keyring) instead of a private bus with an in-memory provider (see TestMain).
+	*/
+	envLiveTests string = "GOSECRET_TEST_LIVE"
 )
 
 // Objects.
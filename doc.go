@@ -86,7 +86,109 @@ Full documentation can be found via inline documentation.
 Additionally, use either https://pkg.go.dev/r00t2.io/gosecret or https://pkg.go.dev/golang.org/x/tools/cmd/godoc (or `go doc`) in the source root.
 
 Note that many functions/methods may return a (r00t2.io/goutils/)multierr.MultiError (https://pkg.go.dev/r00t2.io/goutils/multierr#MultiError),
-which you may attempt to typeswitch back to a *multierr.MultiErr to receive the original errors in their native error format (MultiError.Errors).
+which you may attempt to typeswitch back to a *multierr.MultiError to receive the original errors in their native error format (MultiError.Errors).
 The functions/methods which may return a MultiError are noted as such in their individual documentation.
+As multierr.MultiError does not support errors.Is and errors.As, use ErrorIs and ErrorAs to match against any of the contained errors.
+
+Passwords
+
+For simply storing and fetching passwords, the Password* functions mirror libsecret's simple password API
+(secret_password_store/lookup/clear/search). Define a Schema once and use it for each call:
+
+	var schema *gosecret.Schema = &gosecret.Schema{
+		Name:       "org.example.Password",
+		Attributes: map[string]gosecret.SchemaAttrType{"user": gosecret.SchemaAttrString},
+	}
+
+	err = gosecret.PasswordStore(schema, gosecret.CollectionDefault, "Example password", "hunter2",
+		map[string]interface{}{"user": "alice"})
+	password, err = gosecret.PasswordLookup(schema, map[string]interface{}{"user": "alice"})
+
+The package-level functions open (and close) a Service for each call; use the Service methods of the same name
+to reuse one. Items stored this way are compatible with libsecret (e.g. secret-tool) using the same schema.
+
+Errors
+
+Any error returned by the SecretService over Dbus is returned as an *OpError, which records the Dbus method
+(or property) and object path involved. Known SecretService/Dbus errors are translated to the matching
+SecretServiceError, so you can e.g.:
+
+	if errors.Is(err, gosecret.ErrSecretServiceLocked) {
+		// Unlock and try again.
+	}
+
+The original dbus.Error remains available via errors.As.
+
+Options
+
+NewService accepts Option values to change its defaults, e.g.:
+
+	service, err = gosecret.NewService(
+		gosecret.WithDestination("org.example.TestSecrets"),
+		gosecret.WithLegacy(),
+		gosecret.WithFlags(gosecret.FlagServiceLoadCollections),
+	)
+
+By default NewService opens its own private session bus connection, which Service.Close closes;
+use WithConn to share an existing connection instead (which Service.Close then leaves open).
+
+Programs started at login (e.g. by systemd user units) may run before the keyring daemon has started;
+WithWaitForProvider makes NewService activate the SecretService and/or wait for it to appear,
+returning ErrProviderUnavailable if it doesn't.
+
+Capabilities
+
+NewService introspects the SecretService implementation (see Service.DetectCapabilities) and records what it
+supports in Service.Capabilities: whether it follows the legacy draft spec (which sets Service.Legacy), whether
+Items have a Type property, whether aliases are supported and, where possible, which implementation it is
+(e.g. ProviderGnomeKeyring). Use WithLegacy or WithoutCapabilityDetection to skip this.
+
+On headless systems (where nobody can answer a Prompt), gnome-keyring's master password interface
+(see Capabilities.MasterPassword) allows unlocking, creating and re-keying a Collection with its password via
+Collection.UnlockWithPassword, Service.CreateCollectionWithPassword and Collection.ChangePassword.
+
+Contexts
+
+Every function/method that performs Dbus calls has a context.Context-aware variant with the same name plus a "Context" suffix
+(e.g. Service.SearchItemsContext, Collection.CreateItemContext, Prompt.PromptContext).
+The non-context variants simply call the context variants with context.Background().
+
+If a context is cancelled while waiting on a Prompt, the Prompt is dismissed (see Prompt.Dismiss) and ctx.Err() is returned.
+
+Reconnecting
+
+Long-running programs can use WithReconnect to survive the SecretService (e.g. gnome-keyring-daemon) restarting:
+
+	service, err = gosecret.NewService(
+		gosecret.WithReconnect(func(evt *gosecret.ReconnectEvent) {
+			log.Printf("SecretService reconnected (generation %d): %v", evt.Generation, evt.Err)
+		}),
+	)
+
+The Session is re-opened automatically, idempotent calls are retried once, and Collection/Item objects loaded
+before the restart report Collection.Stale/Item.Stale until refreshed.
+
+Secret Memory
+
+Fetched Secret values are kept in Item.Secret until replaced. To limit how long they stay in memory, use Item.WithSecret
+(which wipes the value as soon as the callback returns) or call Item.ForgetSecret/SecretValue.Wipe when done.
+On Linux, WithLockedMemory additionally keeps fetched values out of swap and core dumps.
+
+Secret values are redacted (see RedactedSecret) when a SecretValue, Secret or Item is printed via fmt, logged via
+log/slog or marshaled via encoding/json. To export them, use MarshalOptions:
+
+	b, err = gosecret.MarshalOptions{Reveal: true, Indent: "  "}.Marshal(items)
+
+Concurrency
+
+A Service, and the Collection and Item objects obtained from it, may be shared between goroutines (e.g. HTTP handlers).
+Their cached fields (e.g. Item.LabelName, Collection.IsLocked) are updated under an internal lock, so read them via
+Item.Info and Collection.Info (which return copies) rather than directly when sharing them.
+Service.Legacy, Service.Capabilities and Service.Session (which is replaced on reconnecting) are likewise available via
+Service.IsLegacy, Service.GetCapabilities and Service.CurrentSession.
+
+To keep separate programs from silently overwriting each other's changes to an Item, take an Item.Snapshot
+before reading it and update it with Item.SetSecretIfUnmodified or Item.ReplaceAttributesIfUnmodified,
+which return ErrConflict if the Item changed in the meantime.
 */
 package gosecret
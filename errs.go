@@ -2,6 +2,8 @@ package gosecret
 
 import (
 	"errors"
+
+	"r00t2.io/gosecret/internal/sscrypto"
 )
 
 // General errors.
@@ -20,6 +22,56 @@ var (
 	ErrMissingAttrs error = errors.New("attributes must not be empty/nil")
 	// ErrDoesNotExist gets triggered if a Collection, Item, etc. is attempted to be fetched but none exists via the specified identifier.
 	ErrDoesNotExist error = errors.New("the object under that name/label/alias does not exist")
+	// ErrPromptDismissed gets triggered if a Prompt was dismissed (cancelled) rather than completed.
+	ErrPromptDismissed error = errors.New("the prompt was dismissed")
+	// ErrBadOption gets triggered if an Option passed to NewService has an invalid value.
+	ErrBadOption error = errors.New("invalid option value")
+	/*
+		ErrProviderUnavailable gets triggered if NewService was asked to wait for the SecretService (WithWaitForProvider)
+		but it could not be activated and did not appear before the timeout/context deadline.
+	*/
+	ErrProviderUnavailable error = errors.New("the SecretService provider is not available")
+	/*
+		ErrConflict gets triggered if Item.SetSecretIfUnmodified or Item.ReplaceAttributesIfUnmodified
+		find the Item has changed since the given ItemSnapshot was taken.
+	*/
+	ErrConflict error = errors.New("the item was modified since the snapshot was taken")
+	/*
+		ErrLockedMemory gets triggered if locked memory was requested (WithLockedMemory) but could not be allocated,
+		e.g. because the platform is not supported or RLIMIT_MEMLOCK (ulimit -l) is too low.
+	*/
+	ErrLockedMemory error = errors.New("could not allocate locked memory for secret value")
+	/*
+		ErrMasterPasswordUnsupported gets triggered if Collection.UnlockWithPassword, Collection.ChangePassword
+		or Service.CreateCollectionWithPassword are used with a SecretService that lacks DbusInterfaceGnomeKeyring.
+	*/
+	ErrMasterPasswordUnsupported error = errors.New("the SecretService does not support master passwords")
+	// ErrBadPassword gets triggered if the SecretService rejects a Collection's (master) password.
+	ErrBadPassword error = errors.New("the password was rejected")
+	// ErrMissingSchema gets triggered if a Password* function/method is passed a nil Schema or one without a Name.
+	ErrMissingSchema error = errors.New("missing schema or schema name")
+	// ErrSchemaAttr gets triggered if an attribute passed to a Password* function/method is not defined by its Schema.
+	ErrSchemaAttr error = errors.New("attribute is not defined by the schema")
+	// ErrSchemaAttrType gets triggered if an attribute value does not match the SchemaAttrType defined by its Schema.
+	ErrSchemaAttrType error = errors.New("attribute value has the wrong type for the schema")
+	// ErrLockStateUnchanged gets triggered (in a LockFailure) if Service.Lock/Service.Unlock did not change an object's state.
+	ErrLockStateUnchanged error = errors.New("the lock state of the object was not changed")
+	// ErrRevealUnsupported gets triggered if MarshalOptions.Marshal is asked to reveal Secret values in an unsupported type.
+	ErrRevealUnsupported error = errors.New("cannot reveal secret values in this type")
+	// ErrPromptRequired is the base error for PromptRequiredError (see FailFastPrompter).
+	ErrPromptRequired error = errors.New("a prompt is required to complete the operation")
+)
+
+// Session/transport encryption errors.
+var (
+	// ErrBadPubKey gets triggered if the SecretService's DH public key returned from Service.OpenSession is missing or invalid.
+	ErrBadPubKey error = sscrypto.ErrBadPubKey
+	// ErrNoSessionKey gets triggered if an encrypted Session is used without a negotiated key.
+	ErrNoSessionKey error = errors.New("session is encrypted but has no negotiated key")
+	// ErrBadIV gets triggered if an encrypted Secret's Parameters do not contain a valid AES IV.
+	ErrBadIV error = sscrypto.ErrBadIV
+	// ErrBadPadding gets triggered if a decrypted Secret value does not have valid PKCS#7 padding.
+	ErrBadPadding error = sscrypto.ErrBadPadding
 )
 
 /*
@@ -54,6 +106,21 @@ var (
 		ErrName: "SECRET_ERROR_INVALID_FILE_FORMAT",
 		ErrDesc: "the file/content format is invalid",
 	}
+	// ErrSecretServiceNoSession has no libsecret equivalent; it is the SecretService spec's NoSession error.
+	ErrSecretServiceNoSession SecretServiceError = SecretServiceError{
+		ErrCode: EnumErrNoSession,
+		ErrName: "SECRET_ERROR_NO_SESSION",
+		ErrDesc: "the session does not exist",
+	}
+	/*
+		ErrSecretServiceUnknownProperty has no libsecret equivalent; it is the standard Dbus UnknownProperty error.
+		Notably, legacy-spec SecretService implementations return it for the Item Type property (see CheckErrIsFromLegacy).
+	*/
+	ErrSecretServiceUnknownProperty SecretServiceError = SecretServiceError{
+		ErrCode: EnumErrUnknownProperty,
+		ErrName: "SECRET_ERROR_UNKNOWN_PROPERTY",
+		ErrDesc: "the object does not have the requested property",
+	}
 	/*
 		AllSecretServiceErrs provides a slice of these for easier iteration when translating.
 		TECHNICALLY, because they are indexed in the order of their enums, you could
@@ -62,7 +129,7 @@ var (
 			err = AllSecretServiceErrs[EnumErrProtocol]
 
 		But this should be considered UNSTABLE and UNSAFE due to it being potentially unpredictable in the future.
-		There are only 5 errors currently, so the performance benefits would be negligible compared to iteration.
+		There are only 7 errors currently, so the performance benefits would be negligible compared to iteration.
 		If SecretService adds more errors, however, this may be more desirable.
 	*/
 	AllSecretServiceErrs []SecretServiceError = []SecretServiceError{
@@ -71,5 +138,16 @@ var (
 		ErrSecretServiceNoObj,
 		ErrSecretServiceExists,
 		ErrSecretServiceInvalidFormat,
+		ErrSecretServiceNoSession,
+		ErrSecretServiceUnknownProperty,
+	}
+	// dbusErrMap maps Dbus error names to their SecretServiceError. Used by TranslateDbusError.
+	dbusErrMap map[string]SecretServiceError = map[string]SecretServiceError{
+		DbusErrIsLocked:        ErrSecretServiceLocked,
+		DbusErrNoSuchObject:    ErrSecretServiceNoObj,
+		DbusErrUnknownObject:   ErrSecretServiceNoObj,
+		DbusErrAlreadyExists:   ErrSecretServiceExists,
+		DbusErrNoSession:       ErrSecretServiceNoSession,
+		DbusErrUnknownProperty: ErrSecretServiceUnknownProperty,
 	}
 )
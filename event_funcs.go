@@ -0,0 +1,141 @@
+package gosecret
+
+import (
+	`context`
+	`fmt`
+
+	`github.com/godbus/dbus/v5`
+)
+
+// String returns a human-readable name for an EventType.
+func (e EventType) String() (s string) {
+
+	switch e {
+	case EventCollectionCreated:
+		s = "CollectionCreated"
+	case EventCollectionDeleted:
+		s = "CollectionDeleted"
+	case EventCollectionChanged:
+		s = "CollectionChanged"
+	case EventItemCreated:
+		s = "ItemCreated"
+	case EventItemDeleted:
+		s = "ItemDeleted"
+	case EventItemChanged:
+		s = "ItemChanged"
+	default:
+		s = fmt.Sprintf("EventUnknown(%d)", int(e))
+	}
+
+	return
+}
+
+/*
+	eventTypeFromSignal returns the EventType for a SecretService signal name (e.g. DbusCollectionItemCreated).
+	ok is false if name is not a SecretService change signal.
+*/
+func eventTypeFromSignal(name string) (evtType EventType, ok bool) {
+
+	ok = true
+
+	switch name {
+	case DbusServiceCollectionCreated:
+		evtType = EventCollectionCreated
+	case DbusServiceCollectionDeleted:
+		evtType = EventCollectionDeleted
+	case DbusServiceCollectionChanged:
+		evtType = EventCollectionChanged
+	case DbusCollectionItemCreated:
+		evtType = EventItemCreated
+	case DbusCollectionItemDeleted:
+		evtType = EventItemDeleted
+	case DbusCollectionItemChanged:
+		evtType = EventItemChanged
+	default:
+		ok = false
+	}
+
+	return
+}
+
+/*
+	watchSignals installs the match rules in matches on conn and delivers every received signal accepted by
+	convert as a *WatchEvent on events until ctx is cancelled (or the connection is closed), at which point the
+	match rules are removed and events is closed.
+
+	convert returns nil for signals that should be ignored.
+*/
+func watchSignals(
+	ctx context.Context, conn *dbus.Conn, matches [][]dbus.MatchOption, convert func(sig *dbus.Signal) (evt *WatchEvent),
+) (events <-chan *WatchEvent, err error) {
+
+	var c chan *dbus.Signal
+	var out chan *WatchEvent
+	var added [][]dbus.MatchOption = make([][]dbus.MatchOption, 0, len(matches))
+
+	for _, m := range matches {
+		if err = conn.AddMatchSignalContext(ctx, m...); err != nil {
+			for _, a := range added {
+				_ = conn.RemoveMatchSignal(a...)
+			}
+			return
+		}
+		added = append(added, m)
+	}
+
+	// As with Prompt.PromptContext, c is owned by conn and never closed by us.
+	c = make(chan *dbus.Signal, 32)
+	out = make(chan *WatchEvent, 16)
+
+	conn.Signal(c)
+
+	go func() {
+
+		var ok bool
+		var sig *dbus.Signal
+		var evt *WatchEvent
+
+		defer close(out)
+		defer func() {
+			conn.RemoveSignal(c)
+			for _, a := range added {
+				_ = conn.RemoveMatchSignal(a...)
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig, ok = <-c:
+				if !ok {
+					return
+				}
+				if evt = convert(sig); evt == nil {
+					continue
+				}
+				select {
+				case out <- evt:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	events = out
+
+	return
+}
+
+// signalPath returns the first element of a SecretService change signal's body (which is always an object path).
+func signalPath(sig *dbus.Signal) (path dbus.ObjectPath, ok bool) {
+
+	if sig.Body == nil || len(sig.Body) < 1 {
+		return
+	}
+
+	path, ok = sig.Body[0].(dbus.ObjectPath)
+
+	return
+}
@@ -0,0 +1,222 @@
+package gosecret_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret"
+	"r00t2.io/gosecret/gosecrettest"
+)
+
+const (
+	// watchTimeout is how long the Watch tests wait for an event.
+	watchTimeout time.Duration = 5 * time.Second
+)
+
+/*
+	TestService_Watch tests the following internal functions/methods via nested calls:
+
+		gosecret.Service.Watch
+			watchSignals
+			Service.eventFromSignal
+				eventTypeFromSignal
+				signalPath
+				Service.collectionForEvent
+				Collection.itemEvent
+
+*/
+func TestService_Watch(t *testing.T) {
+
+	var err error
+	var h *gosecrettest.Harness = gosecrettest.NewT(t)
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var events <-chan *gosecret.WatchEvent
+	var evt *gosecret.WatchEvent
+	var collection *gosecret.Collection
+	var item *gosecret.Item
+	var ssn *gosecret.Session
+	var collPath dbus.ObjectPath
+	var itemPath dbus.ObjectPath
+
+	if ssn, err = h.Service.GetSession(); err != nil {
+		t.Fatalf("could not open session: %v", err.Error())
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	if events, err = h.Service.Watch(ctx); err != nil {
+		t.Fatalf("Service.Watch failed: %v", err.Error())
+	}
+
+	if collection, err = h.Service.CreateCollection("Watch"); err != nil {
+		t.Fatalf("could not create collection: %v", err.Error())
+	}
+	collPath = collection.Dbus.Path()
+	evt = nextEvent(t, events, gosecret.EventCollectionCreated)
+	if evt.Path != collPath || evt.Collection == nil || evt.Collection.Dbus.Path() != collPath || evt.Err != nil {
+		t.Errorf("unexpected CollectionCreated event: %#v", evt)
+	}
+
+	if err = collection.Relabel("Watch (relabeled)"); err != nil {
+		t.Fatalf("could not relabel collection: %v", err.Error())
+	}
+	evt = nextEvent(t, events, gosecret.EventCollectionChanged)
+	if evt.Path != collPath || evt.Collection == nil || evt.Collection.Info().Label != "Watch (relabeled)" {
+		t.Errorf("unexpected CollectionChanged event: %#v", evt)
+	}
+
+	if item, err = collection.CreateItem(
+		"Watch item", map[string]string{"watch": "yes"}, gosecret.NewSecret(ssn, nil, []byte("value"), "text/plain"), false,
+	); err != nil {
+		t.Fatalf("could not create item: %v", err.Error())
+	}
+	itemPath = item.Dbus.Path()
+	evt = nextEvent(t, events, gosecret.EventItemCreated)
+	if evt.Path != itemPath || evt.Item == nil || evt.Item.Dbus.Path() != itemPath ||
+		evt.Collection == nil || evt.Collection.Dbus.Path() != collPath || evt.Err != nil {
+		t.Errorf("unexpected ItemCreated event: %#v", evt)
+	}
+
+	if err = item.Relabel("Watch item (relabeled)"); err != nil {
+		t.Fatalf("could not relabel item: %v", err.Error())
+	}
+	evt = nextEvent(t, events, gosecret.EventItemChanged)
+	if evt.Path != itemPath || evt.Item == nil || evt.Item.Info().Label != "Watch item (relabeled)" {
+		t.Errorf("unexpected ItemChanged event: %#v", evt)
+	}
+
+	if err = item.Delete(); err != nil {
+		t.Fatalf("could not delete item: %v", err.Error())
+	}
+	evt = nextEvent(t, events, gosecret.EventItemDeleted)
+	if evt.Path != itemPath || evt.Item != nil || evt.Collection == nil || evt.Collection.Dbus.Path() != collPath {
+		t.Errorf("unexpected ItemDeleted event: %#v", evt)
+	}
+
+	if err = collection.Delete(); err != nil {
+		t.Fatalf("could not delete collection: %v", err.Error())
+	}
+	evt = nextEvent(t, events, gosecret.EventCollectionDeleted)
+	if evt.Path != collPath || evt.Collection != nil {
+		t.Errorf("unexpected CollectionDeleted event: %#v", evt)
+	}
+
+	cancel()
+	waitClosed(t, events)
+}
+
+/*
+	TestCollection_Watch tests the following internal functions/methods via nested calls:
+
+		gosecret.Collection.Watch
+			watchSignals
+			Collection.itemEvent
+
+*/
+func TestCollection_Watch(t *testing.T) {
+
+	var err error
+	var h *gosecrettest.Harness = gosecrettest.NewT(t)
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var events <-chan *gosecret.WatchEvent
+	var evt *gosecret.WatchEvent
+	var watched *gosecret.Collection
+	var other *gosecret.Collection
+	var item *gosecret.Item
+	var ssn *gosecret.Session
+
+	if ssn, err = h.Service.GetSession(); err != nil {
+		t.Fatalf("could not open session: %v", err.Error())
+	}
+	if watched, err = h.Service.GetCollection(gosecrettest.DefaultAlias); err != nil {
+		t.Fatalf("could not get default collection: %v", err.Error())
+	}
+	if other, err = h.Service.CreateCollection("Watch (other)"); err != nil {
+		t.Fatalf("could not create collection: %v", err.Error())
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+
+	if events, err = watched.Watch(ctx); err != nil {
+		t.Fatalf("Collection.Watch failed: %v", err.Error())
+	}
+
+	// Neither Collection nor other Collections' Item events are delivered.
+	if err = other.Relabel("Watch (other, relabeled)"); err != nil {
+		t.Fatalf("could not relabel collection: %v", err.Error())
+	}
+	if _, err = other.CreateItem(
+		"Other item", map[string]string{"watch": "no"}, gosecret.NewSecret(ssn, nil, []byte("value"), "text/plain"), false,
+	); err != nil {
+		t.Fatalf("could not create item: %v", err.Error())
+	}
+
+	if item, err = watched.CreateItem(
+		"Watched item", map[string]string{"watch": "yes"}, gosecret.NewSecret(ssn, nil, []byte("value"), "text/plain"), false,
+	); err != nil {
+		t.Fatalf("could not create item: %v", err.Error())
+	}
+	evt = nextEvent(t, events, gosecret.EventItemCreated)
+	if evt.Path != item.Dbus.Path() || evt.Item == nil || evt.Collection != watched || evt.Err != nil {
+		t.Errorf("unexpected ItemCreated event (the other collection's may have been delivered): %#v", evt)
+	}
+
+	if err = item.Delete(); err != nil {
+		t.Fatalf("could not delete item: %v", err.Error())
+	}
+	evt = nextEvent(t, events, gosecret.EventItemDeleted)
+	if evt.Path != item.Dbus.Path() || evt.Item != nil || evt.Collection != watched {
+		t.Errorf("unexpected ItemDeleted event: %#v", evt)
+	}
+
+	cancel()
+	waitClosed(t, events)
+}
+
+// nextEvent returns the next event from events, failing t if it is not of type want or none arrives in time.
+func nextEvent(t *testing.T, events <-chan *gosecret.WatchEvent, want gosecret.EventType) (evt *gosecret.WatchEvent) {
+
+	var ok bool
+
+	t.Helper()
+
+	select {
+	case evt, ok = <-events:
+		if !ok {
+			t.Fatalf("events closed while waiting for %v", want)
+		}
+	case <-time.After(watchTimeout):
+		t.Fatalf("timed out waiting for %v", want)
+	}
+
+	if evt.Type != want {
+		t.Fatalf("received %v (%v) while waiting for %v", evt.Type, evt.Path, want)
+	}
+
+	return
+}
+
+// waitClosed fails t if events is not closed (after draining any pending events) in time.
+func waitClosed(t *testing.T, events <-chan *gosecret.WatchEvent) {
+
+	var ok bool = true
+	var timeout <-chan time.Time = time.After(watchTimeout)
+
+	t.Helper()
+
+	for ok {
+		select {
+		case _, ok = <-events:
+		case <-timeout:
+			t.Fatalf("events not closed after the context was cancelled")
+		}
+	}
+
+	return
+}
@@ -1,7 +1,15 @@
 package gosecret
 
 import (
+	`context`
+	`encoding/binary`
+	`errors`
+	`fmt`
+	`hash`
+	`sort`
+	`strconv`
 	`strings`
+	`sync`
 
 	`github.com/godbus/dbus/v5`
 	`r00t2.io/goutils/multierr`
@@ -62,6 +70,27 @@ func pathIsValid(path interface{}) (ok bool, err error) {
 	return
 }
 
+/*
+	errIsNotSupported returns true if err is a Dbus error indicating the requested operation/algorithm is not supported
+	(e.g. a SecretService that does not implement SessionAlgoDH or org.freedesktop.DBus.Properties.GetAll).
+*/
+func errIsNotSupported(err error) (notSupported bool) {
+
+	var name string
+	var dbusErr dbus.Error
+	var dbusErrPtr *dbus.Error
+
+	if errors.As(err, &dbusErr) {
+		name = dbusErr.Name
+	} else if errors.As(err, &dbusErrPtr) && dbusErrPtr != nil {
+		name = dbusErrPtr.Name
+	}
+
+	notSupported = name == DbusErrNotSupported || name == DbusErrUnknownMethod
+
+	return
+}
+
 /*
 	validConnPath condenses the checks for connIsValid and pathIsValid into one func due to how frequently this check is done.
 
@@ -89,6 +118,330 @@ func validConnPath(conn *dbus.Conn, path interface{}) (cr *ConnPathCheckResult,
 	return
 }
 
+/*
+	parallelEach calls fn for every index in [0, n), running at most limit calls concurrently
+	(serially if limit is 1), and returns once all calls have returned.
+	errs[idx] is the error returned by fn(idx); indices not yet started when ctx is cancelled get ctx.Err() instead.
+*/
+func parallelEach(ctx context.Context, n, limit int, fn func(idx int) (err error)) (errs []error) {
+
+	var wg sync.WaitGroup
+	var sem chan struct{}
+
+	errs = make([]error, n)
+
+	if limit < 1 {
+		limit = 1
+	}
+	if limit == 1 || n < 2 {
+		for idx := 0; idx < n; idx++ {
+			if errs[idx] = ctx.Err(); errs[idx] != nil {
+				continue
+			}
+			errs[idx] = fn(idx)
+		}
+		return
+	}
+
+	sem = make(chan struct{}, limit)
+
+	for idx := 0; idx < n; idx++ {
+		select {
+		case <-ctx.Done():
+			errs[idx] = ctx.Err()
+			continue
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			defer func() {
+				<-sem
+			}()
+			errs[idx] = fn(idx)
+		}(idx)
+	}
+
+	wg.Wait()
+
+	return
+}
+
+// copyAttrs returns a copy of attrs (nil if attrs is nil).
+func copyAttrs(attrs map[string]string) (copied map[string]string) {
+
+	if attrs == nil {
+		return
+	}
+
+	copied = make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		copied[k] = v
+	}
+
+	return
+}
+
+// formatDirective rebuilds the fmt directive (e.g. "%+v") from a fmt.Formatter's arguments.
+func formatDirective(f fmt.State, verb rune) (directive string) {
+
+	var ok bool
+	var n int
+	var sb strings.Builder
+
+	sb.WriteRune('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			sb.WriteRune(flag)
+		}
+	}
+	if n, ok = f.Width(); ok {
+		sb.WriteString(strconv.Itoa(n))
+	}
+	if n, ok = f.Precision(); ok {
+		sb.WriteRune('.')
+		sb.WriteString(strconv.Itoa(n))
+	}
+	sb.WriteRune(verb)
+
+	directive = sb.String()
+
+	return
+}
+
+// wipeBytes overwrites b with zeroes.
+func wipeBytes(b []byte) {
+
+	for idx := range b {
+		b[idx] = 0
+	}
+}
+
+// hashField writes b to h prefixed with its length.
+func hashField(h hash.Hash, b []byte) {
+
+	var l [8]byte
+
+	binary.BigEndian.PutUint64(l[:], uint64(len(b)))
+	h.Write(l[:])
+	h.Write(b)
+}
+
+// attrsEqual returns true if a and b contain exactly the same attributes (a nil map is equal to an empty one).
+func attrsEqual(a, b map[string]string) (equal bool) {
+
+	var ok bool
+	var v string
+
+	if len(a) != len(b) {
+		return
+	}
+
+	for k := range a {
+		if v, ok = b[k]; !ok || v != a[k] {
+			return
+		}
+	}
+
+	equal = true
+
+	return
+}
+
+/*
+	sortPaths returns a copy of paths sorted with pathLess, so e.g. .../login/2 comes before .../login/10
+	(i.e. Items numbered sequentially by the SecretService are in creation order).
+*/
+func sortPaths(paths []dbus.ObjectPath) (sorted []dbus.ObjectPath) {
+
+	sorted = make([]dbus.ObjectPath, len(paths))
+	copy(sorted, paths)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return pathLess(sorted[i], sorted[j])
+	})
+
+	return
+}
+
+/*
+	pathLess compares a and b element by element: elements that are both numeric compare numerically,
+	any others lexically. A path sorts before any path it is a prefix of.
+*/
+func pathLess(a, b dbus.ObjectPath) (less bool) {
+
+	var err error
+	var na uint64
+	var nb uint64
+	var aElems []string = strings.Split(string(a), "/")
+	var bElems []string = strings.Split(string(b), "/")
+
+	for idx := 0; idx < len(aElems) && idx < len(bElems); idx++ {
+		if aElems[idx] == bElems[idx] {
+			continue
+		}
+		if na, err = strconv.ParseUint(aElems[idx], 10, 64); err == nil {
+			if nb, err = strconv.ParseUint(bElems[idx], 10, 64); err == nil && na != nb {
+				less = na < nb
+				return
+			}
+		}
+		less = aElems[idx] < bElems[idx]
+		return
+	}
+
+	less = len(aElems) < len(bElems)
+
+	return
+}
+
+// hasItemFlag returns true if flag is in flags.
+func hasItemFlag(flags []ItemInitFlag, flag ItemInitFlag) (ok bool) {
+
+	for _, f := range flags {
+		if f == flag {
+			ok = true
+			return
+		}
+	}
+
+	return
+}
+
+// hasCollectionFlag returns true if flag is in flags.
+func hasCollectionFlag(flags []CollectionInitFlag, flag CollectionInitFlag) (ok bool) {
+
+	for _, f := range flags {
+		if f == flag {
+			ok = true
+			return
+		}
+	}
+
+	return
+}
+
+// hasSearchFlag returns true if flag is in flags.
+func hasSearchFlag(flags []ServiceSearchFlag, flag ServiceSearchFlag) (ok bool) {
+
+	for _, f := range flags {
+		if f == flag {
+			ok = true
+			return
+		}
+	}
+
+	return
+}
+
+/*
+	callContext calls Dbus method method on obj (with args) using ctx.
+	If the call fails, call.Err is an *OpError (see newOpError).
+*/
+func callContext(ctx context.Context, obj dbus.BusObject, method string, args ...interface{}) (call *dbus.Call) {
+
+	call = obj.CallWithContext(ctx, method, 0, args...)
+
+	if call.Err != nil {
+		call.Err = newOpError(method, obj.Path(), call.Err)
+	}
+
+	return
+}
+
+// nameOwner returns the unique connection name currently owning bus name name.
+func nameOwner(ctx context.Context, conn *dbus.Conn, name string) (owner string, err error) {
+
+	if err = callContext(
+		ctx, conn.Object(DbusBusName, dbus.ObjectPath(DbusBusPath)), DbusGetNameOwner, name,
+	).Store(&owner); err != nil {
+		return
+	}
+
+	return
+}
+
+/*
+	splitPropName splits a full Dbus property name (e.g. DbusItemLabel) into its interface and property name.
+	This is the same logic dbus.BusObject.GetProperty uses internally.
+*/
+func splitPropName(name string) (iface, prop string, err error) {
+
+	var idx int = strings.LastIndex(name, ".")
+
+	if idx == -1 || idx+1 == len(name) {
+		err = ErrInvalidProperty
+		return
+	}
+
+	iface = name[:idx]
+	prop = name[idx+1:]
+
+	return
+}
+
+/*
+	getPropertyContext is a context-aware dbus.BusObject.GetProperty.
+	name is the full property name (e.g. DbusItemLabel).
+*/
+func getPropertyContext(ctx context.Context, obj dbus.BusObject, name string) (variant dbus.Variant, err error) {
+
+	var iface string
+	var prop string
+
+	if iface, prop, err = splitPropName(name); err != nil {
+		return
+	}
+
+	if err = obj.CallWithContext(
+		ctx, DbusPropertiesGet, 0, iface, prop,
+	).Store(&variant); err != nil {
+		err = newOpError(name, obj.Path(), err)
+		return
+	}
+
+	return
+}
+
+/*
+	getAllPropertiesContext fetches all properties of interface iface (e.g. DbusInterfaceItem) on obj in one call.
+	props is keyed by the property name without the interface (e.g. "Label").
+*/
+func getAllPropertiesContext(ctx context.Context, obj dbus.BusObject, iface string) (props map[string]dbus.Variant, err error) {
+
+	if err = obj.CallWithContext(
+		ctx, DbusPropertiesGetAll, 0, iface,
+	).Store(&props); err != nil {
+		err = newOpError(DbusPropertiesGetAll, obj.Path(), err)
+		return
+	}
+
+	return
+}
+
+/*
+	setPropertyContext is a context-aware dbus.BusObject.SetProperty.
+	name is the full property name (e.g. DbusItemLabel).
+*/
+func setPropertyContext(ctx context.Context, obj dbus.BusObject, name string, variant dbus.Variant) (err error) {
+
+	var iface string
+	var prop string
+
+	if iface, prop, err = splitPropName(name); err != nil {
+		return
+	}
+
+	if err = obj.CallWithContext(
+		ctx, DbusPropertiesSet, 0, iface, prop, variant,
+	).Err; err != nil {
+		err = newOpError(name, obj.Path(), err)
+		return
+	}
+
+	return
+}
+
 /*
 	pathsFromProp returns a slice of dbus.ObjectPath (paths) from a dbus.Variant (prop).
 	If prop cannot typeswitch to paths, an ErrInvalidProperty will be raised.
@@ -112,9 +465,17 @@ func pathsFromProp(prop dbus.Variant) (paths []dbus.ObjectPath, err error) {
 */
 func pathsFromPath(bus dbus.BusObject, path string) (paths []dbus.ObjectPath, err error) {
 
+	paths, err = pathsFromPathContext(context.Background(), bus, path)
+
+	return
+}
+
+// pathsFromPathContext is like pathsFromPath but uses ctx for the Dbus call.
+func pathsFromPathContext(ctx context.Context, bus dbus.BusObject, path string) (paths []dbus.ObjectPath, err error) {
+
 	var v dbus.Variant
 
-	if v, err = bus.GetProperty(path); err != nil {
+	if v, err = getPropertyContext(ctx, bus, path); err != nil {
 		return
 	}
 
@@ -163,9 +524,8 @@ func NameFromPath(path dbus.ObjectPath) (name string, err error) {
 			Item.ChangeItemType
 			Item.Type
 
-	and (in order) attempt to typeswitch to a *multierr.MultiError, then iterate through
-	the *multierr.MultiError.Errors, attempt to typeswitch each of them to a Dbus.Error, and then finally
-	check if it is regarding a missing Type property.
+	and check (via errors.Is/errors.As, so this also works through an *OpError and within a *multierr.MultiError)
+	if it is regarding a missing Type property (ErrSecretServiceUnknownProperty).
 
 	This is *very explicitly* only useful for the above functions/methods. If used anywhere else,
 	it's liable to return an incorrect isLegacy even if parsed == true.
@@ -182,24 +542,83 @@ func NameFromPath(path dbus.ObjectPath) (name string, err error) {
 */
 func CheckErrIsFromLegacy(err error) (isLegacy, parsed bool) {
 
-	switch e := err.(type) {
-	case *multierr.MultiError:
-		parsed = true
-		for _, i := range e.Errors {
-			switch e2 := i.(type) {
-			case dbus.Error:
-				if e2.Name == "org.freedesktop.DBus.Error.UnknownProperty" {
-					isLegacy = true
-					return
-				}
-			default:
-				continue
+	var translated bool
+	var ssErr SecretServiceError
+	var dbusErr dbus.Error
+	var multi *multierr.MultiError
+
+	if err == nil {
+		return
+	}
+
+	if parsed = errors.As(err, &multi); parsed {
+		for _, e := range multi.Errors {
+			if isLegacy, _ = CheckErrIsFromLegacy(e); isLegacy {
+				return
 			}
 		}
-	case dbus.Error:
+		return
+	}
+
+	parsed = errors.As(err, &dbusErr)
+
+	if translated, ssErr = TranslateDbusError(err); translated && ssErr == ErrSecretServiceUnknownProperty {
+		isLegacy = true
+		return
+	}
+	if errors.Is(err, ErrSecretServiceUnknownProperty) {
 		parsed = true
-		if e.Name == "org.freedesktop.DBus.Error.UnknownProperty" {
-			isLegacy = true
+		isLegacy = true
+		return
+	}
+
+	return
+}
+
+/*
+	ErrorIs is like errors.Is, but also matches if err is (or wraps) a *multierr.MultiError
+	and any of its contained errors match target.
+
+	Use it in place of errors.Is for errors from functions/methods that may return a *multierr.MultiError,
+	e.g. errors.Is(err, ErrPromptDismissed) for an error from Service.Unlock.
+*/
+func ErrorIs(err, target error) (is bool) {
+
+	var multi *multierr.MultiError
+
+	if is = errors.Is(err, target); is {
+		return
+	}
+	if !errors.As(err, &multi) {
+		return
+	}
+
+	for _, e := range multi.Errors {
+		if is = ErrorIs(e, target); is {
+			return
+		}
+	}
+
+	return
+}
+
+/*
+	ErrorAs is like errors.As, but if err is (or wraps) a *multierr.MultiError, it also searches
+	its contained errors (in order) for the first one that matches target.
+*/
+func ErrorAs(err error, target interface{}) (ok bool) {
+
+	var multi *multierr.MultiError
+
+	if ok = errors.As(err, target); ok {
+		return
+	}
+	if !errors.As(err, &multi) {
+		return
+	}
+
+	for _, e := range multi.Errors {
+		if ok = ErrorAs(e, target); ok {
 			return
 		}
 	}
@@ -0,0 +1,91 @@
+package gosecret
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	TestParallelEach tests the following internal functions/methods via nested calls:
+
+		parallelEach
+		sortPaths
+			pathLess
+
+*/
+func TestParallelEach(t *testing.T) {
+
+	var errs []error
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var lock sync.Mutex
+	var inFlight int
+	var maxInFlight int
+	var results []int = make([]int, 20)
+	var errOdd error = errors.New("odd index")
+	var paths []dbus.ObjectPath = []dbus.ObjectPath{"/c/3", "/c/1", "/c/2"}
+	var sorted []dbus.ObjectPath
+
+	errs = parallelEach(context.Background(), len(results), 4, func(idx int) (err error) {
+
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+		results[idx] = idx * 2
+
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+
+		if idx%2 == 1 {
+			err = errOdd
+		}
+		return
+	})
+
+	if maxInFlight > 4 {
+		t.Errorf("parallelEach ran %v calls concurrently with a limit of 4", maxInFlight)
+	}
+	for idx, r := range results {
+		if r != idx*2 {
+			t.Errorf("result %v is %v, expected %v", idx, r, idx*2)
+		}
+		if (idx%2 == 1) != errors.Is(errs[idx], errOdd) {
+			t.Errorf("error %v is %v", idx, errs[idx])
+		}
+	}
+
+	// Calls not yet started when ctx is cancelled are not run.
+	ctx, cancel = context.WithCancel(context.Background())
+	errs = parallelEach(ctx, 3, 1, func(idx int) (err error) {
+		cancel()
+		return
+	})
+	if errs[0] != nil || !errors.Is(errs[1], context.Canceled) || !errors.Is(errs[2], context.Canceled) {
+		t.Errorf("parallelEach did not stop on ctx cancellation: %v", errs)
+	}
+
+	sorted = sortPaths(paths)
+	if sorted[0] != "/c/1" || sorted[1] != "/c/2" || sorted[2] != "/c/3" || paths[0] != "/c/3" {
+		t.Errorf("sortPaths returned %v (input now %v)", sorted, paths)
+	}
+
+	// Numeric path elements sort numerically.
+	sorted = sortPaths([]dbus.ObjectPath{"/c/login/10", "/c/login/2", "/c/b", "/c/login/1", "/c/a/3", "/c/login"})
+	if !reflect.DeepEqual(
+		sorted, []dbus.ObjectPath{"/c/a/3", "/c/b", "/c/login", "/c/login/1", "/c/login/2", "/c/login/10"},
+	) {
+		t.Errorf("sortPaths did not sort numerically: %v", sorted)
+	}
+}
@@ -0,0 +1,175 @@
+package gosecret
+
+import (
+	`context`
+	`errors`
+
+	`github.com/godbus/dbus/v5`
+)
+
+/*
+	CreateCollectionWithPassword creates a new Collection (keyring) labeled label and protected by password
+	without a Prompt, via gnome-keyring's DbusInterfaceGnomeKeyring (see Capabilities.MasterPassword).
+	This allows creating a keyring on a headless system.
+
+	password is sent as a Secret via Service.Session (and so is encrypted in transit if the Session is).
+	ErrMasterPasswordUnsupported is returned if the SecretService does not support this.
+*/
+func (s *Service) CreateCollectionWithPassword(label string, password []byte) (collection *Collection, err error) {
+
+	collection, err = s.CreateCollectionWithPasswordContext(context.Background(), label, password)
+
+	return
+}
+
+// CreateCollectionWithPasswordContext is like Service.CreateCollectionWithPassword but uses ctx for the Dbus call(s).
+func (s *Service) CreateCollectionWithPasswordContext(
+	ctx context.Context, label string, password []byte,
+) (collection *Collection, err error) {
+
+	var call *dbus.Call
+	var master *Secret
+	var path dbus.ObjectPath
+	var props map[string]dbus.Variant = map[string]dbus.Variant{
+		DbusCollectionLabel: dbus.MakeVariant(label),
+	}
+
+	if master, err = s.masterPassword(password); err != nil {
+		return
+	}
+
+	if call, err = s.masterPasswordCall(ctx, DbusGnomeKeyringCreateWithMasterPassword, props, master); err != nil {
+		return
+	}
+	if err = call.Store(&path); err != nil {
+		return
+	}
+
+	collection, err = NewCollectionContext(ctx, s, path)
+
+	return
+}
+
+/*
+	UnlockWithPassword unlocks the Collection with its password without a Prompt,
+	via gnome-keyring's DbusInterfaceGnomeKeyring (see Capabilities.MasterPassword).
+	This allows unlocking e.g. the "login" Collection on a headless system.
+
+	password is sent as a Secret via Service.Session (and so is encrypted in transit if the Session is).
+	ErrBadPassword is returned if the password is wrong, and ErrMasterPasswordUnsupported if the SecretService
+	does not support this.
+*/
+func (c *Collection) UnlockWithPassword(password []byte) (err error) {
+
+	err = c.UnlockWithPasswordContext(context.Background(), password)
+
+	return
+}
+
+// UnlockWithPasswordContext is like Collection.UnlockWithPassword but uses ctx for the Dbus call(s).
+func (c *Collection) UnlockWithPasswordContext(ctx context.Context, password []byte) (err error) {
+
+	var master *Secret
+
+	// The Secret is re-encoded on a retry, as the Session (and thus its key) may have been replaced.
+	if err = c.service.withRetry(ctx, func() (err error) {
+		if master, err = c.service.masterPassword(password); err != nil {
+			return
+		}
+		_, err = c.service.masterPasswordCall(ctx, DbusGnomeKeyringUnlockWithMasterPassword, c.Dbus.Path(), master)
+		return
+	}); err != nil {
+		return
+	}
+
+	if _, err = c.LockedContext(ctx); err != nil {
+		return
+	}
+
+	return
+}
+
+/*
+	ChangePassword changes the Collection's password from oldPassword to newPassword without a Prompt,
+	via gnome-keyring's DbusInterfaceGnomeKeyring (see Capabilities.MasterPassword).
+
+	Both passwords are sent as Secrets via Service.Session (and so are encrypted in transit if the Session is).
+	ErrBadPassword is returned if oldPassword is wrong, and ErrMasterPasswordUnsupported if the SecretService
+	does not support this.
+*/
+func (c *Collection) ChangePassword(oldPassword, newPassword []byte) (err error) {
+
+	err = c.ChangePasswordContext(context.Background(), oldPassword, newPassword)
+
+	return
+}
+
+// ChangePasswordContext is like Collection.ChangePassword but uses ctx for the Dbus call(s).
+func (c *Collection) ChangePasswordContext(ctx context.Context, oldPassword, newPassword []byte) (err error) {
+
+	var original *Secret
+	var master *Secret
+
+	if original, err = c.service.masterPassword(oldPassword); err != nil {
+		return
+	}
+	if master, err = c.service.masterPassword(newPassword); err != nil {
+		return
+	}
+
+	if _, err = c.service.masterPasswordCall(
+		ctx, DbusGnomeKeyringChangeWithMasterPassword, c.Dbus.Path(), original, master,
+	); err != nil {
+		return
+	}
+
+	return
+}
+
+// masterPassword returns password as a (wire-encoded) Secret for the current Session.
+func (s *Service) masterPassword(password []byte) (secret *Secret, err error) {
+
+	var ssn *Session = s.CurrentSession()
+
+	if ssn == nil {
+		err = ErrNoDbusConn
+		return
+	}
+
+	secret, err = ssn.encodeSecret(NewSecret(ssn, []byte{}, password, "text/plain"))
+
+	return
+}
+
+/*
+	masterPasswordCall calls method (on DbusInterfaceGnomeKeyring) with args.
+	It fails with ErrMasterPasswordUnsupported without calling if the Service's Capabilities show the interface
+	is missing, and translates the SecretService's "unknown method/interface" and "access denied" errors
+	to ErrMasterPasswordUnsupported and ErrBadPassword.
+*/
+func (s *Service) masterPasswordCall(ctx context.Context, method string, args ...interface{}) (call *dbus.Call, err error) {
+
+	var ok bool
+	var dbusErr *dbus.Error
+	var caps Capabilities
+
+	if caps, ok = s.GetCapabilities(); ok && !caps.Partial && !caps.MasterPassword {
+		err = ErrMasterPasswordUnsupported
+		return
+	}
+
+	if call = callContext(ctx, s.Dbus, method, args...); call.Err != nil {
+		err = call.Err
+		if errors.As(err, &dbusErr) {
+			switch dbusErr.Name {
+			case DbusErrUnknownMethod, DbusErrUnknownInterface:
+				err = ErrMasterPasswordUnsupported
+			case DbusErrAccessDenied:
+				err = ErrBadPassword
+			}
+		}
+		return
+	}
+
+	return
+}
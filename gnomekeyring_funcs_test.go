@@ -0,0 +1,121 @@
+package gosecret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/server"
+)
+
+/*
+	TestCollection_UnlockWithPassword tests the following internal functions/methods via nested calls:
+
+		Service.CreateCollectionWithPassword
+			Service.masterPassword
+			Service.masterPasswordCall
+		Collection.UnlockWithPassword
+		Collection.ChangePassword
+		Service.DetectCapabilities
+
+	It requires the private test bus (see TestMain), as it starts a second (gnome-keyring-like) provider on it.
+*/
+func TestCollection_UnlockWithPassword(t *testing.T) {
+
+	var err error
+	var svc *Service
+	var conn *dbus.Conn
+	var srv *server.Server
+	var collection *Collection
+	var locked bool
+	var password []byte = []byte("correct horse battery staple")
+	var newPassword []byte = []byte("Tr0ub4dor&3")
+	var gnomeName string = "org.example.gosecret.GnomeKeyring"
+
+	if os.Getenv(envLiveTests) != "" {
+		t.Skip("master password tests require the private test bus")
+	}
+
+	// The default provider does not support master passwords.
+	if svc, err = NewService(); err != nil {
+		t.Fatalf("could not get new Service via NewService: %v", err.Error())
+	}
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+	if err = collection.UnlockWithPassword(password); !errors.Is(err, ErrMasterPasswordUnsupported) {
+		t.Errorf("Collection.UnlockWithPassword returned '%v'; expected ErrMasterPasswordUnsupported", err)
+	}
+	if _, err = svc.CreateCollectionWithPassword("Headless", password); !errors.Is(err, ErrMasterPasswordUnsupported) {
+		t.Errorf("Service.CreateCollectionWithPassword returned '%v'; expected ErrMasterPasswordUnsupported", err)
+	}
+	if err = svc.Close(); err != nil {
+		t.Errorf("could not close Service: %v", err.Error())
+	}
+
+	if conn, err = dbus.ConnectSessionBus(); err != nil {
+		t.Fatalf("could not connect to session bus: %v", err.Error())
+	}
+	defer conn.Close()
+	if srv, err = server.New(conn, server.NewMemoryStorage()); err != nil {
+		t.Fatalf("could not start provider: %v", err.Error())
+	}
+	defer srv.Close()
+	srv.MasterPassword = true
+	srv.PromptHandler = func(_ context.Context, _ *server.PromptRequest) (accept bool) {
+		t.Errorf("a prompt was issued")
+		return
+	}
+	if err = srv.RequestName(gnomeName); err != nil {
+		t.Fatalf("could not request name for provider: %v", err.Error())
+	}
+
+	if svc, err = NewService(WithDestination(gnomeName), WithSessionAlgorithm(SessionAlgoDH)); err != nil {
+		t.Fatalf("could not get new Service via NewService: %v", err.Error())
+	}
+	defer svc.Close()
+	if !svc.Capabilities.MasterPassword || svc.Capabilities.Provider != ProviderGnomeKeyring {
+		t.Errorf("unexpected Capabilities for gnome-keyring provider: %#v", svc.Capabilities)
+	}
+
+	if collection, err = svc.CreateCollectionWithPassword("Headless", password); err != nil {
+		t.Fatalf("Service.CreateCollectionWithPassword failed: %v", err.Error())
+	}
+	if collection.Info().Label != "Headless" || collection.Info().Locked {
+		t.Errorf("unexpected collection created by Service.CreateCollectionWithPassword: %#v", collection.Info())
+	}
+
+	if err = collection.Lock(); err != nil {
+		t.Fatalf("could not lock collection: %v", err.Error())
+	}
+	if err = collection.UnlockWithPassword(newPassword); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("Collection.UnlockWithPassword with a wrong password returned '%v'; expected ErrBadPassword", err)
+	}
+	if locked, err = collection.Locked(); err != nil || !locked {
+		t.Errorf("collection unlocked (err: %v) with a wrong password", err)
+	}
+	if err = collection.UnlockWithPassword(password); err != nil {
+		t.Errorf("Collection.UnlockWithPassword failed: %v", err.Error())
+	}
+	if collection.Info().Locked {
+		t.Errorf("Collection.UnlockWithPassword did not unlock the collection")
+	}
+
+	if err = collection.ChangePassword(newPassword, newPassword); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("Collection.ChangePassword with a wrong password returned '%v'; expected ErrBadPassword", err)
+	}
+	if err = collection.ChangePassword(password, newPassword); err != nil {
+		t.Fatalf("Collection.ChangePassword failed: %v", err.Error())
+	}
+	if err = collection.Lock(); err != nil {
+		t.Fatalf("could not lock collection: %v", err.Error())
+	}
+	if err = collection.UnlockWithPassword(password); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("Collection.UnlockWithPassword with the old password returned '%v'; expected ErrBadPassword", err)
+	}
+	if err = collection.UnlockWithPassword(newPassword); err != nil {
+		t.Errorf("Collection.UnlockWithPassword with the new password failed: %v", err.Error())
+	}
+}
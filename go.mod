@@ -5,5 +5,8 @@ go 1.17
 require (
 	github.com/godbus/dbus/v5 v5.0.6
 	github.com/google/uuid v1.3.0
+	golang.org/x/crypto v0.9.0
 	r00t2.io/goutils v1.1.2
 )
+
+require golang.org/x/sys v0.8.0 // indirect
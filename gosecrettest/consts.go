@@ -0,0 +1,21 @@
+package gosecrettest
+
+// Default contents of a Harness's provider, mirroring a typical gnome-keyring setup.
+const (
+	// DefaultCollectionID is the ID (last path element) of the pre-created collection.
+	DefaultCollectionID string = "login"
+	// DefaultCollectionLabel is the label of the pre-created collection.
+	DefaultCollectionLabel string = "Login"
+	// DefaultAlias is the alias pointing to the pre-created collection.
+	DefaultAlias string = "default"
+)
+
+// PromptAction is the scripted outcome of prompts issued by a Harness's provider (see Harness.SetPromptAction).
+type PromptAction int
+
+const (
+	// PromptAccept completes prompts successfully.
+	PromptAccept PromptAction = iota
+	// PromptDismiss dismisses prompts, as if the user cancelled them.
+	PromptDismiss
+)
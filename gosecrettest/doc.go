@@ -0,0 +1,26 @@
+// See LICENSE in source root directory for copyright and licensing information.
+
+/*
+Package gosecrettest provides a hermetic test environment for code using gosecret.
+
+A Harness starts a private session bus (via dbus-daemon, which must be installed) running an in-memory
+org.freedesktop.secrets provider (see the server package) and returns a gosecret.Service connected to it.
+Nothing touches the developer's session keyring.
+
+	func TestSomething(t *testing.T) {
+
+		var h *gosecrettest.Harness = gosecrettest.NewT(t)
+		var collection *gosecret.Collection
+		var err error
+
+		if collection, err = h.Service.GetCollection(gosecrettest.DefaultAlias); err != nil {
+			t.Fatal(err)
+		}
+		// ...
+	}
+
+The provider starts with a single, unlocked collection (DefaultCollectionID) aliased to DefaultAlias.
+Prompts are scriptable via Harness.SetPromptAction (accept or dismiss, optionally after a delay)
+or Harness.SetPromptHandler.
+*/
+package gosecrettest
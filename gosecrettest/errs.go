@@ -0,0 +1,10 @@
+package gosecrettest
+
+import (
+	"r00t2.io/gosecret/internal/testbus"
+)
+
+var (
+	// ErrNoDaemon is returned by New if dbus-daemon is not installed. (NewT skips the test instead.)
+	ErrNoDaemon error = testbus.ErrNoDaemon
+)
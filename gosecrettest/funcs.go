@@ -0,0 +1,224 @@
+package gosecrettest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret"
+	"r00t2.io/gosecret/internal/testbus"
+	"r00t2.io/gosecret/server"
+)
+
+/*
+	New starts a private session bus and an in-memory SecretService provider on it,
+	and returns a Harness with a gosecret.Service connected to the provider.
+
+	Prompts are accepted immediately until changed via Harness.SetPromptAction/Harness.SetPromptHandler.
+	Call Harness.Close when done.
+*/
+func New() (h *Harness, err error) {
+
+	var harness Harness = Harness{
+		Storage: server.NewMemoryStorage(),
+	}
+	var now time.Time = time.Now()
+
+	if harness.bus, err = testbus.Start(); err != nil {
+		return
+	}
+	harness.Address = harness.bus.Address
+
+	if err = harness.Storage.PutCollection(&server.CollectionRecord{
+		ID:       DefaultCollectionID,
+		Label:    DefaultCollectionLabel,
+		Created:  now,
+		Modified: now,
+	}); err != nil {
+		_ = harness.Close()
+		return
+	}
+	if err = harness.Storage.SetAlias(DefaultAlias, DefaultCollectionID); err != nil {
+		_ = harness.Close()
+		return
+	}
+
+	if harness.serverConn, err = dbus.Connect(harness.Address); err != nil {
+		_ = harness.Close()
+		return
+	}
+	if harness.Server, err = server.New(harness.serverConn, harness.Storage); err != nil {
+		_ = harness.Close()
+		return
+	}
+	harness.Server.PromptHandler = harness.handlePrompt
+
+	if err = harness.Server.RequestName(server.DbusService); err != nil {
+		_ = harness.Close()
+		return
+	}
+
+	if harness.Service, err = harness.NewService(); err != nil {
+		_ = harness.Close()
+		return
+	}
+
+	h = &harness
+
+	return
+}
+
+/*
+	NewT is like New, but registers Harness.Close with t.Cleanup and fails t (via t.Fatalf) on error.
+	If dbus-daemon is not installed, t is skipped instead.
+*/
+func NewT(t testing.TB) (h *Harness) {
+
+	var err error
+
+	t.Helper()
+
+	if h, err = New(); err != nil {
+		if errors.Is(err, ErrNoDaemon) {
+			t.Skipf("cannot start test SecretService: %v", err.Error())
+		}
+		t.Fatalf("cannot start test SecretService: %v", err.Error())
+	}
+
+	t.Cleanup(func() {
+		var closeErr error
+		if closeErr = h.Close(); closeErr != nil {
+			t.Errorf("could not close test SecretService: %v", closeErr.Error())
+		}
+	})
+
+	return
+}
+
+/*
+	NewService returns an additional gosecret.Service (on its own connection) connected to the provider,
+	e.g. to act as a second client. It is closed by Harness.Close.
+*/
+func (h *Harness) NewService() (svc *gosecret.Service, err error) {
+
+	if svc, err = gosecret.NewService(gosecret.WithBusAddress(h.Address)); err != nil {
+		return
+	}
+
+	h.lock.Lock()
+	h.services = append(h.services, svc)
+	h.lock.Unlock()
+
+	return
+}
+
+/*
+	SetPromptAction scripts the outcome of subsequent prompts: each is accepted or dismissed
+	(per action) after delay. A delayed prompt that is dismissed by the client in the meantime is not accepted.
+	It replaces any handler set via Harness.SetPromptHandler.
+*/
+func (h *Harness) SetPromptAction(action PromptAction, delay time.Duration) {
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.promptAction = action
+	h.promptDelay = delay
+	h.promptHandler = nil
+
+	return
+}
+
+/*
+	SetPromptHandler sets a custom handler for subsequent prompts, overriding Harness.SetPromptAction.
+	Prompts are still recorded for Harness.Prompts.
+*/
+func (h *Harness) SetPromptHandler(handler server.PromptHandler) {
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.promptHandler = handler
+
+	return
+}
+
+// Prompts returns a copy of every prompt the provider has handled so far, in order.
+func (h *Harness) Prompts() (prompts []server.PromptRequest) {
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	prompts = make([]server.PromptRequest, len(h.prompts))
+	copy(prompts, h.prompts)
+
+	return
+}
+
+/*
+	Close closes every gosecret.Service created by the Harness (including Harness.Service),
+	stops the provider and the private bus.
+*/
+func (h *Harness) Close() (err error) {
+
+	var busErr error
+	var services []*gosecret.Service
+
+	h.lock.Lock()
+	services = h.services
+	h.services = nil
+	h.lock.Unlock()
+
+	for _, svc := range services {
+		// The test may have closed it already.
+		_ = svc.Close()
+	}
+
+	if h.Server != nil {
+		err = h.Server.Close()
+	}
+	if h.serverConn != nil {
+		_ = h.serverConn.Close()
+	}
+	if h.bus != nil {
+		if busErr = h.bus.Close(); busErr != nil && err == nil {
+			err = busErr
+		}
+	}
+
+	return
+}
+
+// handlePrompt is the provider's server.PromptHandler.
+func (h *Harness) handlePrompt(ctx context.Context, req *server.PromptRequest) (accept bool) {
+
+	var action PromptAction
+	var delay time.Duration
+	var handler server.PromptHandler
+
+	h.lock.Lock()
+	h.prompts = append(h.prompts, *req)
+	action = h.promptAction
+	delay = h.promptDelay
+	handler = h.promptHandler
+	h.lock.Unlock()
+
+	if handler != nil {
+		accept = handler(ctx, req)
+		return
+	}
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	accept = action == PromptAccept
+
+	return
+}
@@ -0,0 +1,85 @@
+package gosecrettest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"r00t2.io/gosecret"
+	"r00t2.io/gosecret/server"
+)
+
+/*
+	TestHarness tests the following internal functions/methods via nested calls:
+
+		NewT
+			New
+			Harness.NewService
+		Harness.SetPromptAction
+		Harness.Prompts
+		Harness.Close (via t.Cleanup)
+
+*/
+func TestHarness(t *testing.T) {
+
+	var err error
+	var h *Harness = NewT(t)
+	var collection *gosecret.Collection
+	var label string
+	var locked bool
+	var prompts []server.PromptRequest
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	if collection, err = h.Service.GetCollection(DefaultAlias); err != nil {
+		t.Fatalf("could not get default collection: %v", err.Error())
+	}
+	if label, err = collection.Label(); err != nil {
+		t.Fatalf("could not get default collection label: %v", err.Error())
+	}
+	if label != DefaultCollectionLabel {
+		t.Errorf("default collection label is '%v', expected '%v'", label, DefaultCollectionLabel)
+	}
+
+	if err = collection.Lock(); err != nil {
+		t.Fatalf("could not lock default collection: %v", err.Error())
+	}
+
+	// Dismissed.
+	h.SetPromptAction(PromptDismiss, 0)
+	if err = collection.Unlock(); !errors.Is(err, gosecret.ErrPromptDismissed) {
+		t.Errorf("unlock with a dismissed prompt returned '%v', expected ErrPromptDismissed", err)
+	}
+
+	// Delayed past the caller's deadline.
+	h.SetPromptAction(PromptAccept, 5*time.Second)
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	err = collection.UnlockContext(ctx)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("unlock with a delayed prompt returned '%v', expected context.DeadlineExceeded", err)
+	}
+	if locked, err = collection.Locked(); err != nil || !locked {
+		t.Errorf("collection unlocked (err: %v) even though the prompt was dismissed on cancellation", err)
+	}
+
+	// Delayed, but accepted.
+	h.SetPromptAction(PromptAccept, 10*time.Millisecond)
+	if err = collection.Unlock(); err != nil {
+		t.Fatalf("unlock with an accepted prompt failed: %v", err.Error())
+	}
+	if locked, err = collection.Locked(); err != nil || locked {
+		t.Errorf("collection still locked (err: %v) after an accepted prompt", err)
+	}
+
+	prompts = h.Prompts()
+	if len(prompts) != 3 {
+		t.Fatalf("%d prompts recorded, expected 3", len(prompts))
+	}
+	for idx, p := range prompts {
+		if p.Kind != server.PromptUnlock || len(p.Objects) != 1 {
+			t.Errorf("prompt #%d is not an unlock prompt for one object: %#v", idx, p)
+		}
+	}
+}
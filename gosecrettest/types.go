@@ -0,0 +1,42 @@
+package gosecrettest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret"
+	"r00t2.io/gosecret/internal/testbus"
+	"r00t2.io/gosecret/server"
+)
+
+/*
+	Harness is a private session bus running an in-memory SecretService provider,
+	with a gosecret.Service connected to it.
+*/
+type Harness struct {
+	// Address is the private bus address.
+	Address string
+	// Storage is the provider's backend; it may be inspected/modified directly.
+	Storage *server.MemoryStorage
+	// Server is the provider.
+	Server *server.Server
+	// Service is a gosecret.Service (with an open Session) connected to the provider.
+	Service *gosecret.Service
+	// bus is the private dbus-daemon.
+	bus *testbus.Bus
+	// serverConn is the provider's connection.
+	serverConn *dbus.Conn
+	// lock protects the fields below.
+	lock sync.Mutex
+	// promptAction is the scripted outcome of prompts.
+	promptAction PromptAction
+	// promptDelay is how long prompts take to complete.
+	promptDelay time.Duration
+	// promptHandler, if non-nil, overrides promptAction/promptDelay.
+	promptHandler server.PromptHandler
+	// prompts are the prompts handled so far.
+	prompts []server.PromptRequest
+	// services are the additional gosecret.Service objects created via Harness.NewService.
+	services []*gosecret.Service
+}
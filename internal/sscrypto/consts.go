@@ -0,0 +1,6 @@
+package sscrypto
+
+const (
+	// KeySize is the size (in bytes) of the AES-128 key derived by Keypair.DeriveKey.
+	KeySize int = 16
+)
@@ -0,0 +1,7 @@
+/*
+Package sscrypto implements the SecretService "dh-ietf1024-sha256-aes128-cbc-pkcs7" transport encryption:
+the Diffie-Hellman key exchange (with HKDF-SHA256 key derivation) and AES-128-CBC with PKCS#7 padding.
+
+It is internal so that gosecret (the client side) and the server package (the provider side) share one implementation.
+*/
+package sscrypto
@@ -0,0 +1,16 @@
+package sscrypto
+
+import (
+	"errors"
+)
+
+var (
+	// ErrBadPubKey gets triggered if the peer's DH public key is missing or invalid.
+	ErrBadPubKey error = errors.New("invalid or missing DH public key")
+	// ErrBadKey gets triggered if Encrypt/Decrypt is called without a KeySize key.
+	ErrBadKey error = errors.New("invalid AES key")
+	// ErrBadIV gets triggered if an encrypted secret's parameters do not contain a valid AES IV.
+	ErrBadIV error = errors.New("invalid IV in secret parameters")
+	// ErrBadPadding gets triggered if a decrypted secret value does not have valid PKCS#7 padding.
+	ErrBadPadding error = errors.New("invalid PKCS#7 padding on decrypted secret value")
+)
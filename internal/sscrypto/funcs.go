@@ -0,0 +1,184 @@
+package sscrypto
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// dhPrime is the 1024-bit MODP group prime ("Second Oakley Group") from RFC 2409, section 6.2.
+var dhPrime, _ = new(big.Int).SetString(
+	"FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD1"+
+		"29024E088A67CC74020BBEA63B139B22514A08798E3404DD"+
+		"EF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245"+
+		"E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7ED"+
+		"EE386BFB5A899FA5AE9F24117C4B1FE649286651ECE65381"+
+		"FFFFFFFFFFFFFFFF",
+	16,
+)
+
+// dhGenerator is the generator for dhPrime.
+var dhGenerator *big.Int = big.NewInt(2)
+
+// NewKeypair generates a new random Keypair.
+func NewKeypair() (kp *Keypair, err error) {
+
+	var max *big.Int = new(big.Int).Sub(dhPrime, big.NewInt(2))
+
+	kp = new(Keypair)
+
+	// priv is in the range [1, p-2].
+	if kp.priv, err = rand.Int(rand.Reader, max); err != nil {
+		kp = nil
+		return
+	}
+	kp.priv.Add(kp.priv, big.NewInt(1))
+
+	kp.pub = new(big.Int).Exp(dhGenerator, kp.priv, dhPrime)
+
+	return
+}
+
+// PublicKey returns the big-endian byte representation of the public value, as sent to the peer.
+func (kp *Keypair) PublicKey() (b []byte) {
+
+	b = kp.pub.Bytes()
+
+	return
+}
+
+/*
+	DeriveKey derives the (KeySize) AES-128 key from the peer's (big-endian) public value peerPub.
+	The shared secret is left-padded with zero bytes to the size of the prime and passed through
+	HKDF-SHA256 (with no salt and no info), as per the SecretService spec and libsecret.
+*/
+func (kp *Keypair) DeriveKey(peerPub []byte) (key []byte, err error) {
+
+	var peer *big.Int
+	var shared *big.Int
+	var sharedBytes []byte
+	var primeLen int = (dhPrime.BitLen() + 7) / 8
+
+	if len(peerPub) == 0 || len(peerPub) > primeLen {
+		err = ErrBadPubKey
+		return
+	}
+
+	peer = new(big.Int).SetBytes(peerPub)
+
+	// Reject the trivial/degenerate values (1 and p-1) and anything out of range.
+	if peer.Cmp(big.NewInt(1)) <= 0 || peer.Cmp(new(big.Int).Sub(dhPrime, big.NewInt(1))) >= 0 {
+		err = ErrBadPubKey
+		return
+	}
+
+	shared = new(big.Int).Exp(peer, kp.priv, dhPrime)
+
+	sharedBytes = make([]byte, primeLen)
+	shared.FillBytes(sharedBytes)
+	defer wipe(sharedBytes)
+
+	key = make([]byte, KeySize)
+	if _, err = io.ReadFull(hkdf.New(sha256.New, sharedBytes, nil, nil), key); err != nil {
+		key = nil
+		return
+	}
+
+	return
+}
+
+// Encrypt encrypts plain using AES-128-CBC (with PKCS#7 padding) with key and a new random IV.
+func Encrypt(key, plain []byte) (iv, ciphertext []byte, err error) {
+
+	var block cipher.Block
+	var padLen int
+	var padded []byte
+
+	if block, err = newCipher(key); err != nil {
+		return
+	}
+
+	iv = make([]byte, aes.BlockSize)
+	if _, err = io.ReadFull(rand.Reader, iv); err != nil {
+		iv = nil
+		return
+	}
+
+	padLen = aes.BlockSize - (len(plain) % aes.BlockSize)
+	padded = make([]byte, len(plain)+padLen)
+	copy(padded, plain)
+	copy(padded[len(plain):], bytes.Repeat([]byte{byte(padLen)}, padLen))
+	defer wipe(padded)
+
+	ciphertext = make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+
+	return
+}
+
+// Decrypt decrypts ciphertext (as produced by Encrypt) with key and the IV iv.
+func Decrypt(key, iv, ciphertext []byte) (plain []byte, err error) {
+
+	var block cipher.Block
+	var padLen int
+	var padded []byte
+
+	if len(iv) != aes.BlockSize {
+		err = ErrBadIV
+		return
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		err = ErrBadPadding
+		return
+	}
+
+	if block, err = newCipher(key); err != nil {
+		return
+	}
+
+	padded = make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(padded, ciphertext)
+
+	padLen = int(padded[len(padded)-1])
+	if padLen == 0 || padLen > aes.BlockSize || padLen > len(padded) {
+		wipe(padded)
+		err = ErrBadPadding
+		return
+	}
+	if !bytes.Equal(padded[len(padded)-padLen:], bytes.Repeat([]byte{byte(padLen)}, padLen)) {
+		wipe(padded)
+		err = ErrBadPadding
+		return
+	}
+
+	plain = padded[:len(padded)-padLen]
+
+	return
+}
+
+// newCipher returns the AES block cipher for key, which must be KeySize bytes.
+func newCipher(key []byte) (block cipher.Block, err error) {
+
+	if len(key) != KeySize {
+		err = ErrBadKey
+		return
+	}
+
+	block, err = aes.NewCipher(key)
+
+	return
+}
+
+// wipe zeroes b.
+func wipe(b []byte) {
+
+	for idx := range b {
+		b[idx] = 0
+	}
+}
@@ -0,0 +1,91 @@
+package sscrypto
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+/*
+	TestKeyExchange tests the following internal functions/methods via nested calls:
+
+		NewKeypair
+		Keypair.PublicKey
+		Keypair.DeriveKey
+		Encrypt
+		Decrypt
+			newCipher
+
+*/
+func TestKeyExchange(t *testing.T) {
+
+	var err error
+	var client *Keypair
+	var server *Keypair
+	var clientKey []byte
+	var serverKey []byte
+	var iv []byte
+	var iv2 []byte
+	var ciphertext []byte
+	var plain []byte
+	var tampered []byte
+	var value []byte = []byte("a secret value")
+
+	if client, err = NewKeypair(); err != nil {
+		t.Fatalf("could not generate client keypair: %v", err.Error())
+	}
+	if server, err = NewKeypair(); err != nil {
+		t.Fatalf("could not generate server keypair: %v", err.Error())
+	}
+
+	if clientKey, err = client.DeriveKey(server.PublicKey()); err != nil {
+		t.Fatalf("could not derive client key: %v", err.Error())
+	}
+	if serverKey, err = server.DeriveKey(client.PublicKey()); err != nil {
+		t.Fatalf("could not derive server key: %v", err.Error())
+	}
+	if !bytes.Equal(clientKey, serverKey) {
+		t.Fatalf("derived keys do not match (client %x, server %x)", clientKey, serverKey)
+	}
+	if len(clientKey) != KeySize {
+		t.Errorf("derived key is %v bytes; expected %v", len(clientKey), KeySize)
+	}
+
+	for _, pub := range [][]byte{nil, {0x01}, new(big.Int).Sub(dhPrime, big.NewInt(1)).Bytes(), make([]byte, 129)} {
+		if _, err = client.DeriveKey(pub); err != ErrBadPubKey {
+			t.Errorf("invalid public key %x was not rejected (err: %v)", pub, err)
+		}
+	}
+
+	if iv, ciphertext, err = Encrypt(clientKey, value); err != nil {
+		t.Fatalf("could not encrypt: %v", err.Error())
+	}
+	if bytes.Equal(ciphertext, value) || len(ciphertext)%16 != 0 || len(iv) != 16 {
+		t.Errorf("unexpected ciphertext %x (IV %x)", ciphertext, iv)
+	}
+	if iv2, _, err = Encrypt(clientKey, value); err != nil || bytes.Equal(iv, iv2) {
+		t.Errorf("IV was reused between two encryptions (err: %v)", err)
+	}
+
+	if plain, err = Decrypt(serverKey, iv, ciphertext); err != nil {
+		t.Fatalf("could not decrypt: %v", err.Error())
+	}
+	if !bytes.Equal(plain, value) {
+		t.Errorf("decrypted value '%v' does not match original '%v'", string(plain), string(value))
+	}
+
+	if _, err = Decrypt(serverKey, iv[:8], ciphertext); err != ErrBadIV {
+		t.Errorf("short IV returned '%v'; expected ErrBadIV", err)
+	}
+	if _, err = Decrypt(serverKey, iv, ciphertext[:len(ciphertext)-1]); err != ErrBadPadding {
+		t.Errorf("truncated ciphertext returned '%v'; expected ErrBadPadding", err)
+	}
+	tampered = append([]byte{}, ciphertext...)
+	tampered[len(tampered)-1] ^= 0xff
+	if plain, err = Decrypt(serverKey, iv, tampered); err == nil && bytes.Equal(plain, value) {
+		t.Errorf("tampered ciphertext decrypted to the original value")
+	}
+	if _, _, err = Encrypt(clientKey[:8], value); err != ErrBadKey {
+		t.Errorf("short key returned '%v'; expected ErrBadKey", err)
+	}
+}
@@ -0,0 +1,13 @@
+package sscrypto
+
+import (
+	"math/big"
+)
+
+// Keypair is a Diffie-Hellman keypair (in the RFC 2409 "Second Oakley Group") for one end of the exchange.
+type Keypair struct {
+	// priv is the private exponent.
+	priv *big.Int
+	// pub is the public value (g^priv mod p).
+	pub *big.Int
+}
@@ -0,0 +1,29 @@
+package testbus
+
+import (
+	"time"
+)
+
+const (
+	// daemonBinary is the name of the dbus-daemon binary.
+	daemonBinary string = "dbus-daemon"
+	// startTimeout is how long to wait for dbus-daemon to report its address.
+	startTimeout time.Duration = 10 * time.Second
+	/*
+		busConfig is the dbus-daemon configuration for the private bus. %v is the socket path.
+		No service directories are configured, so nothing (e.g. a real keyring) is ever activated.
+	*/
+	busConfig string = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%v</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+)
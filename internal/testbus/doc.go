@@ -0,0 +1,6 @@
+/*
+Package testbus launches private dbus-daemon session buses for gosecret's tests.
+
+It is internal so that it may be used both by gosecret's own (in-package) tests and by gosecrettest.
+*/
+package testbus
@@ -0,0 +1,12 @@
+package testbus
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNoDaemon gets triggered if no dbus-daemon binary can be found in PATH.
+	ErrNoDaemon error = errors.New("dbus-daemon not found in PATH")
+	// ErrNoAddress gets triggered if dbus-daemon exits (or times out) without printing its address.
+	ErrNoAddress error = errors.New("dbus-daemon did not report a bus address")
+)
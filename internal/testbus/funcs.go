@@ -0,0 +1,92 @@
+package testbus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*
+	Start launches a new private session bus in a temporary directory.
+	If dbus-daemon is not installed, err will be ErrNoDaemon.
+	Call Bus.Close when done with it.
+*/
+func Start() (bus *Bus, err error) {
+
+	var daemon string
+	var stdout io.ReadCloser
+	var addrChan chan string = make(chan string, 1)
+	var b Bus
+
+	if daemon, err = exec.LookPath(daemonBinary); err != nil {
+		err = ErrNoDaemon
+		return
+	}
+
+	if b.dir, err = os.MkdirTemp("", "gosecret-testbus-"); err != nil {
+		return
+	}
+
+	if err = os.WriteFile(
+		filepath.Join(b.dir, "bus.conf"),
+		[]byte(fmt.Sprintf(busConfig, filepath.Join(b.dir, "bus.sock"))),
+		0600,
+	); err != nil {
+		_ = os.RemoveAll(b.dir)
+		return
+	}
+
+	b.cmd = exec.Command(daemon, "--config-file="+filepath.Join(b.dir, "bus.conf"), "--nofork", "--print-address")
+	if stdout, err = b.cmd.StdoutPipe(); err != nil {
+		_ = os.RemoveAll(b.dir)
+		return
+	}
+	if err = b.cmd.Start(); err != nil {
+		_ = os.RemoveAll(b.dir)
+		return
+	}
+
+	go func() {
+		var line string
+		var scanner *bufio.Scanner = bufio.NewScanner(stdout)
+		if scanner.Scan() {
+			line = strings.TrimSpace(scanner.Text())
+		}
+		addrChan <- line
+		// Keep draining so the daemon never blocks on a full pipe.
+		_, _ = io.Copy(io.Discard, stdout)
+	}()
+
+	select {
+	case b.Address = <-addrChan:
+	case <-time.After(startTimeout):
+	}
+
+	if b.Address == "" {
+		_ = b.Close()
+		err = ErrNoAddress
+		return
+	}
+
+	bus = &b
+
+	return
+}
+
+// Close stops the dbus-daemon and removes its temporary directory.
+func (b *Bus) Close() (err error) {
+
+	if b.cmd != nil && b.cmd.Process != nil {
+		_ = b.cmd.Process.Kill()
+		_ = b.cmd.Wait()
+	}
+
+	err = os.RemoveAll(b.dir)
+
+	return
+}
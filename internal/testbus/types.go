@@ -0,0 +1,15 @@
+package testbus
+
+import (
+	"os/exec"
+)
+
+// Bus is a private dbus-daemon session bus.
+type Bus struct {
+	// Address is the bus address (suitable for dbus.Connect or DBUS_SESSION_BUS_ADDRESS).
+	Address string
+	// dir is the temporary directory holding the bus config and socket.
+	dir string
+	// cmd is the running dbus-daemon.
+	cmd *exec.Cmd
+}
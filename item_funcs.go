@@ -1,6 +1,12 @@
 package gosecret
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -8,13 +14,27 @@ import (
 	"github.com/godbus/dbus/v5"
 )
 
-// NewItem returns a pointer to an Item based on Collection and a Dbus path.
-func NewItem(collection *Collection, path dbus.ObjectPath) (item *Item, err error) {
+/*
+	NewItem returns a pointer to an Item based on Collection and a Dbus path.
+
+	Only the Item's metadata (label, attributes, etc.) is fetched; Item.Secret is nil until Item.GetSecret
+	is called, unless FlagItemLoadSecret is specified in flags.
+*/
+func NewItem(collection *Collection, path dbus.ObjectPath, flags ...ItemInitFlag) (item *Item, err error) {
+
+	item, err = NewItemContext(context.Background(), collection, path, flags...)
+
+	return
+}
+
+// NewItemContext is like NewItem but uses ctx for the Dbus call(s).
+func NewItemContext(ctx context.Context, collection *Collection, path dbus.ObjectPath, flags ...ItemInitFlag) (item *Item, err error) {
 
 	var splitPath []string
 
 	if collection == nil {
 		err = ErrNoDbusConn
+		return
 	}
 
 	if _, err = validConnPath(collection.Conn, path); err != nil {
@@ -24,7 +44,7 @@ func NewItem(collection *Collection, path dbus.ObjectPath) (item *Item, err erro
 	item = &Item{
 		DbusObject: &DbusObject{
 			Conn: collection.Conn,
-			Dbus: collection.Conn.Object(DbusService, path),
+			Dbus: collection.Conn.Object(collection.Dbus.Destination(), path),
 		},
 	}
 
@@ -34,27 +54,14 @@ func NewItem(collection *Collection, path dbus.ObjectPath) (item *Item, err erro
 	item.collection = collection
 
 	// Populate the struct fields...
-	// TODO: use channel for errors; condense into a MultiError and switch to goroutines.
-	if _, err = item.GetSecret(collection.service.Session); err != nil {
-		return
-	}
-	if _, err = item.Locked(); err != nil {
+	if err = item.RefreshContext(ctx); err != nil {
 		return
 	}
-	if _, err = item.Attributes(); err != nil {
-		return
-	}
-	if _, err = item.Label(); err != nil {
-		return
-	}
-	if _, err = item.Type(); err != nil {
-		return
-	}
-	if _, err = item.Created(); err != nil {
-		return
-	}
-	if _, _, err = item.Modified(); err != nil {
-		return
+
+	if hasItemFlag(flags, FlagItemLoadSecret) {
+		if _, err = item.GetSecretContext(ctx, collection.service.CurrentSession()); err != nil {
+			return
+		}
 	}
 
 	return
@@ -63,14 +70,24 @@ func NewItem(collection *Collection, path dbus.ObjectPath) (item *Item, err erro
 // Attributes returns the Item's attributes from Dbus.
 func (i *Item) Attributes() (attrs map[string]string, err error) {
 
+	attrs, err = i.AttributesContext(context.Background())
+
+	return
+}
+
+// AttributesContext is like Item.Attributes but uses ctx for the Dbus call.
+func (i *Item) AttributesContext(ctx context.Context) (attrs map[string]string, err error) {
+
 	var variant dbus.Variant
 
-	if variant, err = i.Dbus.GetProperty(DbusItemAttributes); err != nil {
+	if variant, err = getPropertyContext(ctx, i.Dbus, DbusItemAttributes); err != nil {
 		return
 	}
 
 	attrs = variant.Value().(map[string]string)
-	i.Attrs = attrs
+	i.lock.Lock()
+	i.Attrs = copyAttrs(attrs)
+	i.lock.Unlock()
 
 	return
 }
@@ -83,10 +100,18 @@ func (i *Item) Attributes() (attrs map[string]string, err error) {
 */
 func (i *Item) ChangeItemType(newItemType string) (err error) {
 
+	err = i.ChangeItemTypeContext(context.Background(), newItemType)
+
+	return
+}
+
+// ChangeItemTypeContext is like Item.ChangeItemType but uses ctx for the Dbus call(s).
+func (i *Item) ChangeItemTypeContext(ctx context.Context, newItemType string) (err error) {
+
 	var variant dbus.Variant
 
 	// Legacy spec.
-	if i.collection.service.Legacy {
+	if i.collection.service.IsLegacy() {
 		return
 	}
 
@@ -96,12 +121,14 @@ func (i *Item) ChangeItemType(newItemType string) (err error) {
 
 	variant = dbus.MakeVariant(newItemType)
 
-	if err = i.Dbus.SetProperty(DbusItemType, variant); err != nil {
+	if err = setPropertyContext(ctx, i.Dbus, DbusItemType, variant); err != nil {
 		return
 	}
+	i.lock.Lock()
 	i.SecretType = newItemType
+	i.lock.Unlock()
 
-	if _, _, err = i.Modified(); err != nil {
+	if _, _, err = i.ModifiedContext(ctx); err != nil {
 		return
 	}
 
@@ -111,12 +138,22 @@ func (i *Item) ChangeItemType(newItemType string) (err error) {
 // Delete removes an Item from a Collection.
 func (i *Item) Delete() (err error) {
 
+	err = i.DeleteContext(context.Background())
+
+	return
+}
+
+/*
+	DeleteContext is like Item.Delete but uses ctx for the Dbus call(s).
+	If a Prompt is required, it is passed (with ctx) to the Service.Prompter.
+*/
+func (i *Item) DeleteContext(ctx context.Context) (err error) {
+
 	var call *dbus.Call
 	var promptPath dbus.ObjectPath
-	var prompt *Prompt
 
-	if call = i.Dbus.Call(
-		DbusItemDelete, 0,
+	if call = callContext(
+		ctx, i.Dbus, DbusItemDelete,
 	); call.Err != nil {
 		err = call.Err
 		return
@@ -127,8 +164,7 @@ func (i *Item) Delete() (err error) {
 
 	if isPrompt(promptPath) {
 
-		prompt = NewPrompt(i.Conn, promptPath)
-		if _, err = prompt.Prompt(); err != nil {
+		if _, err = i.collection.service.handlePrompt(ctx, promptPath); err != nil {
 			return
 		}
 	}
@@ -136,32 +172,104 @@ func (i *Item) Delete() (err error) {
 	return
 }
 
-// GetSecret returns the Secret in an Item using a Session.
+// GetSecret returns the Secret in an Item using a Session. If session is encrypted, the returned Secret's Value is decrypted.
 func (i *Item) GetSecret(session *Session) (secret *Secret, err error) {
 
-	var call *dbus.Call
+	secret, err = i.GetSecretContext(context.Background(), session)
+
+	return
+}
+
+// GetSecretContext is like Item.GetSecret but uses ctx for the Dbus call.
+func (i *Item) GetSecretContext(ctx context.Context, session *Session) (secret *Secret, err error) {
+
+	if secret, err = i.fetchSecret(ctx, session); err != nil {
+		return
+	}
+	i.lock.Lock()
+	i.Secret = secret
+	i.lock.Unlock()
+
+	return
+}
+
+/*
+	WithSecret fetches the Item's Secret (via Service.Session) and calls fn with its Value, which is wiped
+	(see SecretValue.Wipe) as soon as fn returns. The Secret is not cached in Item.Secret.
+	fn must not retain value (or slices of it); copy what it needs.
+
+	err is fn's error, if any, or an error from fetching the Secret (in which case fn is not called).
+*/
+func (i *Item) WithSecret(fn func(value SecretValue) (err error)) (err error) {
+
+	err = i.WithSecretContext(context.Background(), fn)
+
+	return
+}
+
+// WithSecretContext is like Item.WithSecret but uses ctx for the Dbus call.
+func (i *Item) WithSecretContext(ctx context.Context, fn func(value SecretValue) (err error)) (err error) {
+
+	var secret *Secret
+
+	if secret, err = i.fetchSecret(ctx, i.collection.service.CurrentSession()); err != nil {
+		return
+	}
+	defer secret.Value.Wipe()
+
+	err = fn(secret.Value)
+
+	return
+}
+
+/*
+	ForgetSecret wipes (see SecretValue.Wipe) and removes the cached Item.Secret.
+	Note that this is the same Secret returned by Item.GetSecret or passed to Item.SetSecret, so it is wiped for
+	those callers as well. It does not change the Secret in the SecretService.
+*/
+func (i *Item) ForgetSecret() {
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if i.Secret == nil {
+		return
+	}
+	i.Secret.Value.Wipe()
+	i.Secret = nil
+}
+
+// fetchSecret fetches (and decodes) the Item's Secret via session without caching it in Item.Secret.
+func (i *Item) fetchSecret(ctx context.Context, session *Session) (secret *Secret, err error) {
+
+	var ssn *Session
 
 	if session == nil {
 		err = ErrNoDbusConn
+		return
 	}
 
 	if _, err = connIsValid(session.Conn); err != nil {
 		return
 	}
 
-	if call = i.Dbus.Call(
-		DbusItemGetSecret, 0, session.Dbus.Path(),
-	); call.Err != nil {
-		err = call.Err
+	if err = i.collection.service.withRetry(ctx, func() (err error) {
+		ssn = session.current()
+		secret = nil
+		err = callContext(
+			ctx, i.Dbus, DbusItemGetSecret, ssn.Dbus.Path(),
+		).Store(&secret)
 		return
-	}
-	if err = call.Store(&secret); err != nil {
+	}); err != nil {
+		secret = nil
 		return
 	}
 
-	secret.session = session
+	if err = ssn.decodeSecret(secret); err != nil {
+		secret = nil
+		return
+	}
 	secret.item = i
-	i.Secret = secret
 
 	return
 }
@@ -169,14 +277,24 @@ func (i *Item) GetSecret(session *Session) (secret *Secret, err error) {
 // Label returns the label ("name") of an Item.
 func (i *Item) Label() (label string, err error) {
 
+	label, err = i.LabelContext(context.Background())
+
+	return
+}
+
+// LabelContext is like Item.Label but uses ctx for the Dbus call.
+func (i *Item) LabelContext(ctx context.Context) (label string, err error) {
+
 	var variant dbus.Variant
 
-	if variant, err = i.Dbus.GetProperty(DbusItemLabel); err != nil {
+	if variant, err = getPropertyContext(ctx, i.Dbus, DbusItemLabel); err != nil {
 		return
 	}
 
 	label = variant.Value().(string)
+	i.lock.Lock()
 	i.LabelName = label
+	i.lock.Unlock()
 
 	return
 }
@@ -192,6 +310,14 @@ func (i *Item) Label() (label string, err error) {
 */
 func (i *Item) ModifyAttributes(replaceAttrs map[string]string) (err error) {
 
+	err = i.ModifyAttributesContext(context.Background(), replaceAttrs)
+
+	return
+}
+
+// ModifyAttributesContext is like Item.ModifyAttributes but uses ctx for the Dbus call(s).
+func (i *Item) ModifyAttributesContext(ctx context.Context, replaceAttrs map[string]string) (err error) {
+
 	var ok bool
 	var currentProps map[string]string = make(map[string]string, 0)
 	var currentVal string
@@ -201,7 +327,7 @@ func (i *Item) ModifyAttributes(replaceAttrs map[string]string) (err error) {
 		return
 	}
 
-	if currentProps, err = i.Attributes(); err != nil {
+	if currentProps, err = i.AttributesContext(ctx); err != nil {
 		return
 	}
 
@@ -218,7 +344,7 @@ func (i *Item) ModifyAttributes(replaceAttrs map[string]string) (err error) {
 		}
 	}
 
-	if err = i.ReplaceAttributes(currentProps); err != nil {
+	if err = i.ReplaceAttributesContext(ctx, currentProps); err != nil {
 		return
 	}
 
@@ -228,14 +354,24 @@ func (i *Item) ModifyAttributes(replaceAttrs map[string]string) (err error) {
 // Relabel modifies the Item's label in Dbus.
 func (i *Item) Relabel(newLabel string) (err error) {
 
+	err = i.RelabelContext(context.Background(), newLabel)
+
+	return
+}
+
+// RelabelContext is like Item.Relabel but uses ctx for the Dbus call(s).
+func (i *Item) RelabelContext(ctx context.Context, newLabel string) (err error) {
+
 	var variant dbus.Variant = dbus.MakeVariant(newLabel)
 
-	if err = i.Dbus.SetProperty(DbusItemLabel, variant); err != nil {
+	if err = setPropertyContext(ctx, i.Dbus, DbusItemLabel, variant); err != nil {
 		return
 	}
+	i.lock.Lock()
 	i.LabelName = newLabel
+	i.lock.Unlock()
 
-	if _, _, err = i.Modified(); err != nil {
+	if _, _, err = i.ModifiedContext(ctx); err != nil {
 		return
 	}
 
@@ -245,79 +381,290 @@ func (i *Item) Relabel(newLabel string) (err error) {
 // ReplaceAttributes replaces the Item's attributes in Dbus.
 func (i *Item) ReplaceAttributes(newAttrs map[string]string) (err error) {
 
+	err = i.ReplaceAttributesContext(context.Background(), newAttrs)
+
+	return
+}
+
+// ReplaceAttributesContext is like Item.ReplaceAttributes but uses ctx for the Dbus call(s).
+func (i *Item) ReplaceAttributesContext(ctx context.Context, newAttrs map[string]string) (err error) {
+
 	var props dbus.Variant
 
 	props = dbus.MakeVariant(newAttrs)
 
-	if err = i.Dbus.SetProperty(DbusItemAttributes, props); err != nil {
+	if err = setPropertyContext(ctx, i.Dbus, DbusItemAttributes, props); err != nil {
 		return
 	}
-	i.Attrs = newAttrs
+	i.lock.Lock()
+	i.Attrs = copyAttrs(newAttrs)
+	i.lock.Unlock()
 
-	if _, _, err = i.Modified(); err != nil {
+	if _, _, err = i.ModifiedContext(ctx); err != nil {
 		return
 	}
 
 	return
 }
 
-// SetSecret sets the Secret for an Item.
+/*
+	SetSecret sets the Secret for an Item.
+	secret's Value is encrypted automatically if its Session (or the Service's Session, if it has none) is encrypted.
+*/
 func (i *Item) SetSecret(secret *Secret) (err error) {
 
-	var call *dbus.Call
+	err = i.SetSecretContext(context.Background(), secret)
 
-	if call = i.Dbus.Call(
-		DbusItemSetSecret, 0,
-	); call.Err != nil {
-		err = call.Err
+	return
+}
+
+// SetSecretContext is like Item.SetSecret but uses ctx for the Dbus call(s).
+func (i *Item) SetSecretContext(ctx context.Context, secret *Secret) (err error) {
+
+	var wire *Secret
+
+	// The Secret is re-encoded on a retry, as the Session (and thus its key) may have been replaced.
+	if err = i.collection.service.withRetry(ctx, func() (err error) {
+		if wire, err = secret.wireSession(i.collection.service.CurrentSession()).encodeSecret(secret); err != nil {
+			return
+		}
+		err = callContext(ctx, i.Dbus, DbusItemSetSecret, wire).Err
+		return
+	}); err != nil {
 		return
 	}
 
+	secret.item = i
+	i.lock.Lock()
 	i.Secret = secret
+	i.lock.Unlock()
 
-	if _, _, err = i.Modified(); err != nil {
+	if _, _, err = i.ModifiedContext(ctx); err != nil {
 		return
 	}
 
 	return
 }
 
+/*
+	Snapshot returns an ItemSnapshot of the Item's current modification time (as reported by Dbus) for use with
+	Item.SetSecretIfUnmodified and Item.ReplaceAttributesIfUnmodified.
+
+	If withHash is true, the Item's label, type, attributes and Secret are fetched and hashed as well
+	(see ItemSnapshot.Hash); use this if the SecretService may be changed more than once a second.
+*/
+func (i *Item) Snapshot(withHash bool) (snap *ItemSnapshot, err error) {
+
+	snap, err = i.SnapshotContext(context.Background(), withHash)
+
+	return
+}
+
+// SnapshotContext is like Item.Snapshot but uses ctx for the Dbus call(s).
+func (i *Item) SnapshotContext(ctx context.Context, withHash bool) (snap *ItemSnapshot, err error) {
+
+	var s ItemSnapshot
+
+	if s.Modified, _, err = i.ModifiedContext(ctx); err != nil {
+		return
+	}
+
+	if withHash {
+		if s.Hash, err = i.contentHash(ctx); err != nil {
+			return
+		}
+	}
+
+	snap = &s
+
+	return
+}
+
+/*
+	SetSecretIfUnmodified is like Item.SetSecret, but returns ErrConflict (without setting the Secret)
+	if the Item has changed since snap was taken (see Item.Snapshot).
+
+	The check and the update are two separate Dbus calls, as the SecretService has no atomic compare-and-swap;
+	calls on the same Item object are serialized, but another client may still write in between.
+*/
+func (i *Item) SetSecretIfUnmodified(secret *Secret, snap *ItemSnapshot) (err error) {
+
+	err = i.SetSecretIfUnmodifiedContext(context.Background(), secret, snap)
+
+	return
+}
+
+// SetSecretIfUnmodifiedContext is like Item.SetSecretIfUnmodified but uses ctx for the Dbus call(s).
+func (i *Item) SetSecretIfUnmodifiedContext(ctx context.Context, secret *Secret, snap *ItemSnapshot) (err error) {
+
+	i.casLock.Lock()
+	defer i.casLock.Unlock()
+
+	if err = i.checkUnmodified(ctx, snap); err != nil {
+		return
+	}
+
+	err = i.SetSecretContext(ctx, secret)
+
+	return
+}
+
+/*
+	ReplaceAttributesIfUnmodified is like Item.ReplaceAttributes, but returns ErrConflict (without replacing
+	the attributes) if the Item has changed since snap was taken (see Item.Snapshot).
+
+	See Item.SetSecretIfUnmodified for caveats.
+*/
+func (i *Item) ReplaceAttributesIfUnmodified(newAttrs map[string]string, snap *ItemSnapshot) (err error) {
+
+	err = i.ReplaceAttributesIfUnmodifiedContext(context.Background(), newAttrs, snap)
+
+	return
+}
+
+// ReplaceAttributesIfUnmodifiedContext is like Item.ReplaceAttributesIfUnmodified but uses ctx for the Dbus call(s).
+func (i *Item) ReplaceAttributesIfUnmodifiedContext(
+	ctx context.Context, newAttrs map[string]string, snap *ItemSnapshot,
+) (err error) {
+
+	i.casLock.Lock()
+	defer i.casLock.Unlock()
+
+	if err = i.checkUnmodified(ctx, snap); err != nil {
+		return
+	}
+
+	err = i.ReplaceAttributesContext(ctx, newAttrs)
+
+	return
+}
+
 // Type updates the Item.ItemType from DBus (and returns it).
 func (i *Item) Type() (itemType string, err error) {
 
+	itemType, err = i.TypeContext(context.Background())
+
+	return
+}
+
+// TypeContext is like Item.Type but uses ctx for the Dbus call.
+func (i *Item) TypeContext(ctx context.Context) (itemType string, err error) {
+
 	var variant dbus.Variant
 
 	// Legacy spec.
-	if i.collection.service.Legacy {
+	if i.collection.service.IsLegacy() {
 		return
 	}
 
-	if variant, err = i.Dbus.GetProperty(DbusItemType); err != nil {
+	if variant, err = getPropertyContext(ctx, i.Dbus, DbusItemType); err != nil {
 		return
 	}
 
 	itemType = variant.Value().(string)
+	i.lock.Lock()
 	i.SecretType = itemType
+	i.lock.Unlock()
+
+	return
+}
+
+/*
+	Info returns a copy of the Item's cached fields. It does not perform any Dbus calls; see Item.Refresh.
+
+	Only the metadata of a cached Secret is copied, not its Value, so that Info never makes a copy of the cleartext
+	(which WithLockedMemory and Item.ForgetSecret could not protect). Use Item.GetSecret or Item.WithSecret for the value.
+*/
+func (i *Item) Info() (info ItemInfo) {
+
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	info = ItemInfo{
+		Label:      i.LabelName,
+		Type:       i.SecretType,
+		Attributes: copyAttrs(i.Attrs),
+		Locked:     i.IsLocked,
+		Created:    i.CreatedAt,
+		Modified:   i.LastModified,
+	}
+	if i.Secret != nil {
+		info.Secret = &Secret{
+			Session:     i.Secret.Session,
+			Parameters:  append([]byte(nil), i.Secret.Parameters...),
+			ContentType: i.Secret.ContentType,
+		}
+	}
+	if i.DbusObject != nil && i.Dbus != nil {
+		info.Path = i.Dbus.Path()
+	}
+
+	return
+}
+
+/*
+	String returns a summary of the Item (its ItemInfo; see Item.Info) with the Secret value redacted
+	(see RedactedSecret).
+*/
+func (i *Item) String() (str string) {
+
+	str = fmt.Sprintf("%v", i)
+
+	return
+}
+
+// GoString returns a Go-syntax representation of the Item's ItemInfo with the Secret value redacted (for fmt's %#v).
+func (i *Item) GoString() (str string) {
+
+	str = fmt.Sprintf("%#v", i.Info())
 
 	return
 }
 
+/*
+	Format implements fmt.Formatter. The Item is printed as its ItemInfo (see Item.Info) so that its internal state
+	is not printed (or read without locking) and the Secret value is redacted (see SecretValue.Format).
+*/
+func (i *Item) Format(f fmt.State, verb rune) {
+
+	if i == nil {
+		_, _ = fmt.Fprint(f, "<nil>")
+		return
+	}
+
+	_, _ = fmt.Fprintf(f, formatDirective(f, verb), i.Info())
+}
+
 // Lock will lock an unlocked Item. It will no-op if the Item is currently locked.
 func (i *Item) Lock() (err error) {
 
-	if _, err = i.Locked(); err != nil {
+	err = i.LockContext(context.Background())
+
+	return
+}
+
+// LockContext is like Item.Lock but uses ctx for the Dbus call(s).
+func (i *Item) LockContext(ctx context.Context) (err error) {
+
+	var isLocked bool
+	var result *LockResult
+
+	if isLocked, err = i.LockedContext(ctx); err != nil {
 		return
 	}
-	if i.IsLocked {
+	if isLocked {
 		return
 	}
 
-	if err = i.collection.service.Lock(i); err != nil {
+	if result, err = i.collection.service.LockContext(ctx, i); err != nil {
+		err = singleLockErr(result, err)
 		return
 	}
+	i.lock.Lock()
 	i.IsLocked = true
+	i.lock.Unlock()
 
-	if _, _, err = i.Modified(); err != nil {
+	if _, _, err = i.ModifiedContext(ctx); err != nil {
 		return
 	}
 
@@ -327,15 +674,25 @@ func (i *Item) Lock() (err error) {
 // Locked indicates if an Item is locked (true) or unlocked (false).
 func (i *Item) Locked() (isLocked bool, err error) {
 
+	isLocked, err = i.LockedContext(context.Background())
+
+	return
+}
+
+// LockedContext is like Item.Locked but uses ctx for the Dbus call.
+func (i *Item) LockedContext(ctx context.Context) (isLocked bool, err error) {
+
 	var variant dbus.Variant
 
-	if variant, err = i.Dbus.GetProperty(DbusItemLocked); err != nil {
+	if variant, err = getPropertyContext(ctx, i.Dbus, DbusItemLocked); err != nil {
 		isLocked = true
 		return
 	}
 
 	isLocked = variant.Value().(bool)
+	i.lock.Lock()
 	i.IsLocked = isLocked
+	i.lock.Unlock()
 
 	return
 }
@@ -343,19 +700,36 @@ func (i *Item) Locked() (isLocked bool, err error) {
 // Unlock will unlock a locked Item. It will no-op if the Item is currently unlocked.
 func (i *Item) Unlock() (err error) {
 
-	if _, err = i.Locked(); err != nil {
+	err = i.UnlockContext(context.Background())
+
+	return
+}
+
+/*
+	UnlockContext is like Item.Unlock but uses ctx for the Dbus call(s).
+	If a Prompt is required, it is passed (with ctx) to the Service.Prompter.
+*/
+func (i *Item) UnlockContext(ctx context.Context) (err error) {
+
+	var isLocked bool
+	var result *LockResult
+
+	if isLocked, err = i.LockedContext(ctx); err != nil {
 		return
 	}
-	if !i.IsLocked {
+	if !isLocked {
 		return
 	}
 
-	if err = i.collection.service.Unlock(i); err != nil {
+	if result, err = i.collection.service.UnlockContext(ctx, i); err != nil {
+		err = singleLockErr(result, err)
 		return
 	}
+	i.lock.Lock()
 	i.IsLocked = false
+	i.lock.Unlock()
 
-	if _, _, err = i.Modified(); err != nil {
+	if _, _, err = i.ModifiedContext(ctx); err != nil {
 		return
 	}
 
@@ -365,17 +739,27 @@ func (i *Item) Unlock() (err error) {
 // Created returns the time.Time of when an Item was created.
 func (i *Item) Created() (created time.Time, err error) {
 
+	created, err = i.CreatedContext(context.Background())
+
+	return
+}
+
+// CreatedContext is like Item.Created but uses ctx for the Dbus call.
+func (i *Item) CreatedContext(ctx context.Context) (created time.Time, err error) {
+
 	var variant dbus.Variant
 	var timeInt uint64
 
-	if variant, err = i.Dbus.GetProperty(DbusItemCreated); err != nil {
+	if variant, err = getPropertyContext(ctx, i.Dbus, DbusItemCreated); err != nil {
 		return
 	}
 
 	timeInt = variant.Value().(uint64)
 
 	created = time.Unix(int64(timeInt), 0)
+	i.lock.Lock()
 	i.CreatedAt = created
+	i.lock.Unlock()
 
 	return
 }
@@ -390,16 +774,188 @@ func (i *Item) Created() (created time.Time, err error) {
 */
 func (i *Item) Modified() (modified time.Time, isChanged bool, err error) {
 
+	modified, isChanged, err = i.ModifiedContext(context.Background())
+
+	return
+}
+
+// ModifiedContext is like Item.Modified but uses ctx for the Dbus call.
+func (i *Item) ModifiedContext(ctx context.Context) (modified time.Time, isChanged bool, err error) {
+
 	var variant dbus.Variant
 	var timeInt uint64
 
-	if variant, err = i.Dbus.GetProperty(DbusItemModified); err != nil {
+	if variant, err = getPropertyContext(ctx, i.Dbus, DbusItemModified); err != nil {
 		return
 	}
 
 	timeInt = variant.Value().(uint64)
 
 	modified = time.Unix(int64(timeInt), 0)
+	i.lock.Lock()
+	isChanged = i.setModified(modified)
+	i.lock.Unlock()
+
+	return
+}
+
+/*
+	Refresh updates all of the Item's cached fields (Item.IsLocked, Item.Attrs, Item.LabelName, Item.SecretType,
+	Item.CreatedAt and Item.LastModified) from Dbus. It does not fetch the Secret; use Item.GetSecret for that.
+
+	All properties are fetched in a single org.freedesktop.DBus.Properties.GetAll call; if the SecretService
+	does not support that (UnknownMethod/NotSupported), each property is fetched individually instead.
+	Any other error is returned as-is.
+*/
+func (i *Item) Refresh() (err error) {
+
+	err = i.RefreshContext(context.Background())
+
+	return
+}
+
+// RefreshContext is like Item.Refresh but uses ctx for the Dbus call(s).
+func (i *Item) RefreshContext(ctx context.Context) (err error) {
+
+	var props map[string]dbus.Variant
+	var gen uint64 = i.collection.service.generation()
+
+	if err = i.collection.service.withRetry(ctx, func() (err error) {
+		if props, err = getAllPropertiesContext(ctx, i.Dbus, DbusInterfaceItem); err != nil {
+			if !errIsNotSupported(err) {
+				return
+			}
+			err = i.refreshEach(ctx)
+			return
+		}
+		err = i.applyProperties(props)
+		return
+	}); err != nil {
+		return
+	}
+
+	i.lock.Lock()
+	i.generation = gen
+	i.lock.Unlock()
+
+	return
+}
+
+// refreshEach is the fallback for Item.RefreshContext if GetAll is not supported; it fetches each property separately.
+func (i *Item) refreshEach(ctx context.Context) (err error) {
+
+	if _, err = i.LockedContext(ctx); err != nil {
+		return
+	}
+	if _, err = i.AttributesContext(ctx); err != nil {
+		return
+	}
+	if _, err = i.LabelContext(ctx); err != nil {
+		return
+	}
+	if _, err = i.TypeContext(ctx); err != nil {
+		return
+	}
+	if _, err = i.CreatedContext(ctx); err != nil {
+		return
+	}
+	if _, _, err = i.ModifiedContext(ctx); err != nil {
+		return
+	}
+
+	return
+}
+
+/*
+	applyProperties populates the Item's cached fields from the result of a GetAll call.
+	Properties missing from props are left unchanged (e.g. Type on legacy-spec implementations).
+	If any property is of the wrong type, err is ErrInvalidProperty and none of them are applied.
+*/
+func (i *Item) applyProperties(props map[string]dbus.Variant) (err error) {
+
+	var ok bool
+	var v dbus.Variant
+	var locked bool
+	var attrs map[string]string
+	var label string
+	var secretType string
+	var created uint64
+	var modified uint64
+	var hasLocked bool
+	var hasAttrs bool
+	var hasLabel bool
+	var hasType bool
+	var hasCreated bool
+	var hasModified bool
+	var legacy bool = i.collection.service.IsLegacy()
+
+	if v, hasLocked = props["Locked"]; hasLocked {
+		if locked, ok = v.Value().(bool); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+	if v, hasAttrs = props["Attributes"]; hasAttrs {
+		if attrs, ok = v.Value().(map[string]string); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+	if v, hasLabel = props["Label"]; hasLabel {
+		if label, ok = v.Value().(string); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+	if v, hasType = props["Type"]; hasType && !legacy {
+		if secretType, ok = v.Value().(string); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+	if v, hasCreated = props["Created"]; hasCreated {
+		if created, ok = v.Value().(uint64); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+	if v, hasModified = props["Modified"]; hasModified {
+		if modified, ok = v.Value().(uint64); !ok {
+			err = ErrInvalidProperty
+			return
+		}
+	}
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	if hasLocked {
+		i.IsLocked = locked
+	}
+	if hasAttrs {
+		i.Attrs = attrs
+	}
+	if hasLabel {
+		i.LabelName = label
+	}
+	if hasType && !legacy {
+		i.SecretType = secretType
+	}
+	if hasCreated {
+		i.CreatedAt = time.Unix(int64(created), 0)
+	}
+	if hasModified {
+		i.setModified(time.Unix(int64(modified), 0))
+	}
+
+	return
+}
+
+/*
+	setModified records modified as Item.LastModified and returns true if it is newer than the previous value.
+	The caller must hold i.lock.
+*/
+func (i *Item) setModified(modified time.Time) (isChanged bool) {
 
 	if !i.lastModifiedSet {
 		// It's "nil", so set it to modified. We can't check for a zero-value in case Dbus has it as a zero-value.
@@ -413,6 +969,74 @@ func (i *Item) Modified() (modified time.Time, isChanged bool, err error) {
 	return
 }
 
+// checkUnmodified returns ErrConflict if the Item has changed since snap was taken.
+func (i *Item) checkUnmodified(ctx context.Context, snap *ItemSnapshot) (err error) {
+
+	var current *ItemSnapshot
+
+	if snap == nil {
+		err = ErrMissingObj
+		return
+	}
+
+	if current, err = i.SnapshotContext(ctx, snap.Hash != nil); err != nil {
+		return
+	}
+
+	if !current.Modified.Equal(snap.Modified) {
+		err = ErrConflict
+		return
+	}
+	if snap.Hash != nil && !bytes.Equal(current.Hash, snap.Hash) {
+		err = ErrConflict
+		return
+	}
+
+	return
+}
+
+/*
+	contentHash returns a SHA-256 hash of the Item's label, type, attributes and Secret (content type and value)
+	as currently stored in the SecretService.
+*/
+func (i *Item) contentHash(ctx context.Context) (sum []byte, err error) {
+
+	var h hash.Hash
+	var secret *Secret
+	var info ItemInfo
+	var keys []string
+
+	if err = i.RefreshContext(ctx); err != nil {
+		return
+	}
+	if secret, err = i.fetchSecret(ctx, i.collection.service.CurrentSession()); err != nil {
+		return
+	}
+	defer secret.Value.Wipe()
+	info = i.Info()
+
+	keys = make([]string, 0, len(info.Attributes))
+	for k := range info.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h = sha256.New()
+	// Each field is length-prefixed so that e.g. label "ab" + type "c" and label "a" + type "bc" differ.
+	hashField(h, []byte(info.Label))
+	hashField(h, []byte(info.Type))
+	for _, k := range keys {
+		hashField(h, []byte(k))
+		hashField(h, []byte(info.Attributes[k]))
+	}
+	hashField(h, []byte(secret.ContentType))
+	hashField(h, secret.Value)
+
+	sum = h.Sum(nil)
+
+	return
+}
+
 // path is a *very* thin wrapper around Item.Dbus.Path(). It is needed for LockableObject membership.
 func (i *Item) path() (dbusPath dbus.ObjectPath) {
 
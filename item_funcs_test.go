@@ -1,8 +1,14 @@
 package gosecret
 
 import (
+	`context`
+	`errors`
 	`reflect`
+	`sync`
 	`testing`
+	`time`
+
+	`github.com/godbus/dbus/v5`
 )
 
 // Some functions are covered in the Service tests and Collection tests.
@@ -151,3 +157,387 @@ func TestItem(t *testing.T) {
 		t.Errorf("could not close Service.Session: %v", err.Error())
 	}
 }
+
+/*
+	TestItem_Refresh tests the following internal functions/methods via nested calls:
+
+		NewItem
+		Item.Refresh
+			getAllPropertiesContext
+			Item.applyProperties
+		Item.refreshEach
+		Collection.Refresh
+			Collection.applyProperties
+		errIsNotSupported
+
+*/
+func TestItem_Refresh(t *testing.T) {
+
+	var svc *Service
+	var collection *Collection
+	var item *Item
+	var other *Item
+	var fallback *Item
+	var secret *Secret
+	var opErr *OpError
+	var newLabel string = testItemLabel + " (refreshed)"
+	var err error
+
+	if svc, err = NewService(); err != nil {
+		t.Fatalf("NewService failed: %v", err.Error())
+	}
+	defer svc.Close()
+
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	secret = NewSecret(svc.Session, []byte{}, []byte(testSecretContent), "text/plain")
+
+	if item, err = collection.CreateItem(testItemLabel, itemAttrs, secret, false); err != nil {
+		t.Fatalf("could not create item '%v' in collection '%v': %v", testItemLabel, defaultCollection, err.Error())
+	}
+	defer item.Delete()
+
+	if other, err = NewItem(collection, item.Dbus.Path()); err != nil {
+		t.Fatalf("NewItem failed: %v", err.Error())
+	}
+	if other.LabelName != testItemLabel || !reflect.DeepEqual(other.Attrs, item.Attrs) || other.CreatedAt.IsZero() {
+		t.Errorf("NewItem did not populate the cached fields: %#v", other)
+	}
+
+	if err = item.Relabel(newLabel); err != nil {
+		t.Fatalf("could not relabel item: %v", err.Error())
+	}
+	if err = other.Refresh(); err != nil {
+		t.Errorf("Item.Refresh failed: %v", err.Error())
+	}
+	if other.LabelName != newLabel {
+		t.Errorf("Item.Refresh did not update the label; got '%v', expected '%v'", other.LabelName, newLabel)
+	}
+
+	// The per-property fallback must yield the same result.
+	fallback = &Item{DbusObject: other.DbusObject, collection: collection}
+	if err = fallback.refreshEach(context.Background()); err != nil {
+		t.Errorf("Item.refreshEach failed: %v", err.Error())
+	}
+	if fallback.LabelName != other.LabelName || fallback.SecretType != other.SecretType ||
+		fallback.IsLocked != other.IsLocked || !fallback.CreatedAt.Equal(other.CreatedAt) ||
+		!fallback.LastModified.Equal(other.LastModified) || !reflect.DeepEqual(fallback.Attrs, other.Attrs) {
+		t.Errorf("Item.refreshEach does not match Item.Refresh:\n%#v\n%#v", fallback, other)
+	}
+
+	// An invalid property leaves every cached field (including valid ones in the same call) unchanged.
+	if err = other.applyProperties(map[string]dbus.Variant{
+		"Attributes": dbus.MakeVariant(map[string]string{"clobbered": "yes"}),
+		"Locked":     dbus.MakeVariant(!other.IsLocked),
+		"Label":      dbus.MakeVariant(uint64(1)),
+	}); !errors.Is(err, ErrInvalidProperty) {
+		t.Errorf("Item.applyProperties did not reject an invalid property: %v", err)
+	}
+	if other.LabelName != newLabel || other.IsLocked != fallback.IsLocked || !reflect.DeepEqual(other.Attrs, fallback.Attrs) {
+		t.Errorf("Item.applyProperties changed cached fields despite an invalid property: %#v", other)
+	}
+
+	if err = collection.Refresh(); err != nil {
+		t.Errorf("Collection.Refresh failed: %v", err.Error())
+	}
+	if collection.LabelName == "" || collection.CreatedAt.IsZero() {
+		t.Errorf("Collection.Refresh did not populate the cached fields: %#v", collection)
+	}
+	if err = collection.applyProperties(map[string]dbus.Variant{
+		"Label":    dbus.MakeVariant("Clobbered"),
+		"Modified": dbus.MakeVariant("not a timestamp"),
+	}); !errors.Is(err, ErrInvalidProperty) {
+		t.Errorf("Collection.applyProperties did not reject an invalid property: %v", err)
+	}
+	if collection.LabelName == "Clobbered" {
+		t.Errorf("Collection.applyProperties changed cached fields despite an invalid property")
+	}
+
+	// Errors other than an unsupported GetAll are returned as-is rather than falling back to refreshEach.
+	if !errIsNotSupported(dbus.Error{Name: DbusErrUnknownMethod}) || errIsNotSupported(dbus.Error{Name: DbusErrNoSuchObject}) {
+		t.Errorf("errIsNotSupported misclassified an error")
+	}
+	if err = item.Delete(); err != nil {
+		t.Fatalf("could not delete item: %v", err.Error())
+	}
+	if err = other.Refresh(); !errors.As(err, &opErr) || opErr.Op != DbusPropertiesGetAll {
+		t.Errorf("Item.Refresh of a deleted item returned '%v'; expected the GetAll error", err)
+	}
+}
+
+/*
+	TestItem_Concurrent exercises a shared Service, Collection and Item from multiple goroutines.
+	It is only meaningful with the race detector enabled (go test -race).
+
+		Item.Info
+		Item.Label
+		Item.Locked
+		Item.Modified
+		Item.Attributes
+		Item.GetSecret
+		Item.Refresh
+		Collection.Info
+		Collection.Refresh
+		Service.DetectCapabilities
+		Service.IsLegacy
+		Service.GetCapabilities
+
+*/
+func TestItem_Concurrent(t *testing.T) {
+
+	var svc *Service
+	var collection *Collection
+	var item *Item
+	var secret *Secret
+	var wg sync.WaitGroup
+	var errs chan error
+	var err error
+	var workers int = 8
+
+	if svc, err = NewService(); err != nil {
+		t.Fatalf("NewService failed: %v", err.Error())
+	}
+	defer svc.Close()
+
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	secret = NewSecret(svc.Session, []byte{}, []byte(testSecretContent), "text/plain")
+
+	if item, err = collection.CreateItem(testItemLabel, itemAttrs, secret, false); err != nil {
+		t.Fatalf("could not create item '%v' in collection '%v': %v", testItemLabel, defaultCollection, err.Error())
+	}
+	defer item.Delete()
+
+	errs = make(chan error, workers*10)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+
+			var info ItemInfo
+			var err error
+
+			defer wg.Done()
+
+			for n := 0; n < 5; n++ {
+				if _, err = item.Label(); err != nil {
+					errs <- err
+				}
+				if _, err = item.Locked(); err != nil {
+					errs <- err
+				}
+				if _, _, err = item.Modified(); err != nil {
+					errs <- err
+				}
+				if _, err = item.Attributes(); err != nil {
+					errs <- err
+				}
+				if _, err = item.GetSecret(svc.Session); err != nil {
+					errs <- err
+				}
+				if err = item.Refresh(); err != nil {
+					errs <- err
+				}
+				if err = collection.Refresh(); err != nil {
+					errs <- err
+				}
+				info = item.Info()
+				// Modifying the copy must not affect the Item.
+				info.Attributes["concurrent"] = "yes"
+				_ = collection.Info()
+				if w == 0 {
+					if _, err = svc.DetectCapabilities(); err != nil {
+						errs <- err
+					}
+				}
+				_ = svc.IsLegacy()
+				_, _ = svc.GetCapabilities()
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err = range errs {
+		t.Errorf("concurrent call failed: %v", err.Error())
+	}
+
+	if item.Info().Attributes["concurrent"] != "" {
+		t.Errorf("Item.Info did not return a copy of the attributes")
+	}
+	if item.Info().Secret == nil || item.Info().Secret.ContentType != "text/plain" {
+		t.Errorf("Item.Info did not return a copy of the secret's metadata")
+	}
+	if item.Info().Secret.Value != nil || string(item.Secret.Value) != testSecretContent {
+		t.Errorf("Item.Info copied the secret value")
+	}
+}
+
+/*
+	TestItem_IfUnmodified tests the following internal functions/methods via nested calls:
+
+		NewItem
+		Item.Snapshot
+			Item.contentHash
+		Item.SetSecretIfUnmodified
+			Item.checkUnmodified
+		Item.ReplaceAttributesIfUnmodified
+
+*/
+func TestItem_IfUnmodified(t *testing.T) {
+
+	var svc *Service
+	var collection *Collection
+	var item *Item
+	var other *Item
+	var secret *Secret
+	var snap *ItemSnapshot
+	var stale *ItemSnapshot
+	var newAttrs map[string]string = map[string]string{"GOSECRET": "yes", "cas": "yes"}
+	var err error
+
+	if svc, err = NewService(); err != nil {
+		t.Fatalf("NewService failed: %v", err.Error())
+	}
+	defer svc.Close()
+
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	secret = NewSecret(svc.Session, []byte{}, []byte(testSecretContent), "text/plain")
+
+	if item, err = collection.CreateItem(testItemLabel, itemAttrs, secret, false); err != nil {
+		t.Fatalf("could not create item '%v' in collection '%v': %v", testItemLabel, defaultCollection, err.Error())
+	}
+	defer item.Delete()
+
+	if other, err = NewItem(collection, item.Dbus.Path()); err != nil {
+		t.Fatalf("NewItem failed: %v", err.Error())
+	}
+
+	// Unchanged; the update succeeds.
+	if snap, err = item.Snapshot(true); err != nil {
+		t.Fatalf("Item.Snapshot failed: %v", err.Error())
+	}
+	if snap.Modified.IsZero() || len(snap.Hash) == 0 {
+		t.Errorf("Item.Snapshot returned an incomplete snapshot: %#v", snap)
+	}
+	secret = NewSecret(svc.Session, []byte{}, []byte("first"), "text/plain")
+	if err = item.SetSecretIfUnmodified(secret, snap); err != nil {
+		t.Errorf("Item.SetSecretIfUnmodified failed on an unmodified item: %v", err.Error())
+	}
+
+	// Changed by another writer (likely within the same second); only the hash catches it.
+	if snap, err = item.Snapshot(true); err != nil {
+		t.Fatalf("Item.Snapshot failed: %v", err.Error())
+	}
+	if err = other.SetSecret(NewSecret(svc.Session, []byte{}, []byte("second"), "text/plain")); err != nil {
+		t.Fatalf("Item.SetSecret failed: %v", err.Error())
+	}
+	secret = NewSecret(svc.Session, []byte{}, []byte("third"), "text/plain")
+	if err = item.SetSecretIfUnmodified(secret, snap); !errors.Is(err, ErrConflict) {
+		t.Errorf("Item.SetSecretIfUnmodified returned %v; expected %v", err, ErrConflict)
+	}
+	if secret, err = other.GetSecret(svc.Session); err != nil {
+		t.Fatalf("Item.GetSecret failed: %v", err.Error())
+	}
+	if string(secret.Value) != "second" {
+		t.Errorf("Item.SetSecretIfUnmodified overwrote the secret despite a conflict: '%v'", string(secret.Value))
+	}
+
+	// Timestamp only.
+	if snap, err = item.Snapshot(false); err != nil {
+		t.Fatalf("Item.Snapshot failed: %v", err.Error())
+	}
+	if snap.Hash != nil {
+		t.Errorf("Item.Snapshot returned a hash when not requested")
+	}
+	stale = &ItemSnapshot{Modified: snap.Modified.Add(-time.Hour)}
+	if err = item.ReplaceAttributesIfUnmodified(newAttrs, stale); !errors.Is(err, ErrConflict) {
+		t.Errorf("Item.ReplaceAttributesIfUnmodified returned %v; expected %v", err, ErrConflict)
+	}
+	if err = item.ReplaceAttributesIfUnmodified(newAttrs, snap); err != nil {
+		t.Errorf("Item.ReplaceAttributesIfUnmodified failed on an unmodified item: %v", err.Error())
+	}
+	if !reflect.DeepEqual(item.Info().Attributes, newAttrs) {
+		t.Errorf("Item.ReplaceAttributesIfUnmodified did not replace the attributes: %#v", item.Info().Attributes)
+	}
+}
+
+/*
+	TestItem_WithSecret tests the following internal functions/methods via nested calls:
+
+		WithLockedMemory
+		Item.WithSecret
+			Item.fetchSecret
+			Session.decodeSecret
+		Item.GetSecret
+		Item.ForgetSecret
+
+*/
+func TestItem_WithSecret(t *testing.T) {
+
+	var svc *Service
+	var collection *Collection
+	var item *Item
+	var secret *Secret
+	var seen string
+	var errFn error = errors.New("callback failed")
+	var err error
+
+	if svc, err = NewService(WithLockedMemory()); err != nil {
+		if errors.Is(err, ErrLockedMemory) {
+			t.Skipf("locked memory not available: %v", err)
+		}
+		t.Fatalf("NewService failed: %v", err.Error())
+	}
+	defer svc.Close()
+
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	secret = NewSecret(svc.Session, []byte{}, []byte(testSecretContent), "text/plain")
+
+	if item, err = collection.CreateItem(testItemLabel, itemAttrs, secret, false); err != nil {
+		t.Fatalf("could not create item '%v' in collection '%v': %v", testItemLabel, defaultCollection, err.Error())
+	}
+	defer item.Delete()
+	item.ForgetSecret()
+
+	if err = item.WithSecret(func(value SecretValue) (err error) {
+		seen = string(value)
+		return
+	}); err != nil {
+		t.Fatalf("Item.WithSecret failed: %v", err.Error())
+	}
+	if seen != testSecretContent {
+		t.Errorf("Item.WithSecret passed '%v'; expected '%v'", seen, testSecretContent)
+	}
+	if item.Info().Secret != nil {
+		t.Errorf("Item.WithSecret cached the secret")
+	}
+	if err = item.WithSecret(func(value SecretValue) (err error) {
+		err = errFn
+		return
+	}); err != errFn {
+		t.Errorf("Item.WithSecret returned %v; expected %v", err, errFn)
+	}
+
+	if secret, err = item.GetSecret(svc.Session); err != nil {
+		t.Fatalf("Item.GetSecret failed: %v", err.Error())
+	}
+	if string(secret.Value) != testSecretContent {
+		t.Errorf("Item.GetSecret (locked memory) returned '%v'", string(secret.Value))
+	}
+	item.ForgetSecret()
+	if secret.Value != nil || item.Info().Secret != nil {
+		t.Errorf("Item.ForgetSecret did not wipe the secret")
+	}
+}
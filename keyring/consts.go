@@ -0,0 +1,57 @@
+package keyring
+
+import (
+	"os"
+)
+
+// Backend names (see Config.Backends and ParseBackend).
+const (
+	// BackendSecretService stores Items in a SecretService Collection.
+	BackendSecretService Backend = "secret-service"
+	// BackendFile stores Items in an encrypted file.
+	BackendFile Backend = "file"
+	// BackendMemory keeps Items in memory only.
+	BackendMemory Backend = "memory"
+)
+
+// DefaultBackends are the backends tried by Open if Config.Backends is empty.
+var DefaultBackends []Backend = []Backend{BackendSecretService, BackendFile}
+
+const (
+	// AttrKey is the SecretService attribute holding an Item's Key.
+	AttrKey string = "gosecret:key"
+	// DefaultCollection is the SecretService Collection (alias) used if Config.Collection is empty.
+	DefaultCollection string = "default"
+	// defaultContentType is the content type of Secrets stored in the SecretService.
+	defaultContentType string = "text/plain"
+)
+
+// Encrypted file parameters.
+const (
+	// FileVersion is the format version of files written by the file backend.
+	FileVersion int = 1
+	// KeySize is the size (in bytes) of the AES-256 key; keys passed to NewFileWithKey must be this size.
+	KeySize int = 32
+	// SaltSize is the size (in bytes) of the Argon2id salt.
+	SaltSize int = 16
+	// ArgonTime is the Argon2id number of passes (RFC 9106, second recommended option).
+	ArgonTime uint32 = 3
+	// ArgonMemory is the Argon2id memory cost in KiB (64 MiB).
+	ArgonMemory uint32 = 64 * 1024
+	// ArgonThreads is the Argon2id parallelism.
+	ArgonThreads uint8 = 4
+	/*
+		maxArgonTime and maxArgonMemory bound the Argon2id parameters accepted from a file's (unauthenticated) header,
+		so a corrupt or tampered file can not make deriving the key hang or exhaust memory.
+	*/
+	maxArgonTime   uint32 = 4 * ArgonTime
+	maxArgonMemory uint32 = 1024 * 1024
+	// kdfArgon2id marks a file whose key is derived from a password.
+	kdfArgon2id string = "argon2id"
+	// kdfNone marks a file whose key was supplied directly.
+	kdfNone string = "none"
+	// fileMode is the permission mode of the encrypted file.
+	fileMode os.FileMode = 0600
+	// dirMode is the permission mode of the encrypted file's directory, if created.
+	dirMode os.FileMode = 0700
+)
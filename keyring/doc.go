@@ -0,0 +1,44 @@
+// See LICENSE in source root directory for copyright and licensing information.
+
+/*
+Package keyring provides a small, backend-agnostic Keyring interface for storing secrets, so that code does not
+need to depend on a *gosecret.Service (and thus a session bus) directly.
+
+Three backends are provided:
+
+- BackendSecretService stores Items in a SecretService Collection (see FromService).
+
+- BackendFile stores Items in a single file, encrypted with AES-256-GCM under a key derived from a password
+via Argon2id (or under a key supplied directly, e.g. from the portal subpackage's Portal.MasterKey). See NewFile.
+
+- BackendMemory keeps Items in memory only (see NewMemory); useful for tests and as a last resort.
+
+Open selects a backend at runtime, trying each of Config.Backends in turn, so that e.g. a program uses the
+SecretService on a desktop and falls back to an encrypted file on a server without a session bus:
+
+	var kr keyring.Keyring
+	var item *keyring.Item
+	var err error
+
+	if kr, err = keyring.Open(&keyring.Config{
+		Backends:     []keyring.Backend{keyring.BackendSecretService, keyring.BackendFile},
+		FilePath:     "/var/lib/example/secrets.json",
+		FilePassword: password,
+	}); err != nil {
+		log.Fatal(err)
+	}
+	defer kr.Close()
+
+	if err = kr.Set(&keyring.Item{
+		Key: "db", Label: "Database password", Attributes: map[string]string{"host": "db1"}, Secret: []byte("hunter2"),
+	}); err != nil {
+		log.Fatal(err)
+	}
+	if item, err = kr.Get("db"); err != nil {
+		log.Fatal(err)
+	}
+
+Items are identified by Item.Key. In the SecretService, the key is stored in the AttrKey attribute;
+Items without it (e.g. those created by other programs) are not visible through a Keyring.
+*/
+package keyring
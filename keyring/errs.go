@@ -0,0 +1,28 @@
+package keyring
+
+import (
+	"errors"
+)
+
+var (
+	// ErrNotFound gets triggered if no Item has the requested Key.
+	ErrNotFound error = errors.New("no item with that key")
+	// ErrMissingKey gets triggered if an Item's Key (or the key passed to Get/Delete) is empty.
+	ErrMissingKey error = errors.New("missing item key")
+	// ErrUnknownBackend gets triggered if ParseBackend/Open is given an unknown Backend.
+	ErrUnknownBackend error = errors.New("unknown keyring backend")
+	// ErrNoBackend gets triggered (in a *multierr.MultiError with each backend's error) if no backend could be opened.
+	ErrNoBackend error = errors.New("no keyring backend could be opened")
+	// ErrClosed gets triggered if a Keyring is used after Keyring.Close.
+	ErrClosed error = errors.New("keyring is closed")
+	// ErrMissingPath gets triggered if the file backend is selected without a Config.FilePath.
+	ErrMissingPath error = errors.New("missing keyring file path")
+	// ErrMissingPassword gets triggered if the file backend is selected without a Config.FilePassword or Config.FileKey.
+	ErrMissingPassword error = errors.New("missing keyring file password or key")
+	// ErrKeySize gets triggered if a key passed to NewFileWithKey is not KeySize bytes.
+	ErrKeySize error = errors.New("invalid keyring file key size")
+	// ErrBadPassword gets triggered if an encrypted file cannot be decrypted with the given password (or key).
+	ErrBadPassword error = errors.New("wrong keyring file password or key")
+	// ErrFileFormat gets triggered if an encrypted file is malformed or of an unsupported FileVersion.
+	ErrFileFormat error = errors.New("malformed or unsupported keyring file")
+)
@@ -0,0 +1,449 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/crypto/argon2"
+)
+
+/*
+	NewFile returns a Keyring stored in the file at path, encrypted with AES-256-GCM under a key derived from
+	password via Argon2id (see ArgonTime, ArgonMemory and ArgonThreads; the parameters used are stored in the file).
+
+	If the file does not exist, it is created (along with its directory) on the first Keyring.Set.
+	If it exists but cannot be decrypted with password, err will be ErrBadPassword.
+	If it is malformed, or its Argon2id parameters are unreasonably large (over 1 GiB of memory or
+	four times ArgonTime passes), err will be ErrFileFormat.
+
+	The whole file is rewritten (atomically, via a temporary file) on every Keyring.Set/Keyring.Delete,
+	so it is meant for modest numbers of Items. It is not safe for use by multiple processes at once.
+*/
+func NewFile(path string, password []byte) (kr Keyring, err error) {
+
+	if path == "" {
+		err = ErrMissingPath
+		return
+	}
+	if len(password) == 0 {
+		err = ErrMissingPassword
+		return
+	}
+
+	kr, err = openFile(path, password, nil)
+
+	return
+}
+
+/*
+	NewFileWithKey is like NewFile, but uses key (which must be KeySize bytes, e.g. from the portal subpackage's
+	Portal.MasterKey) directly instead of deriving one from a password.
+*/
+func NewFileWithKey(path string, key []byte) (kr Keyring, err error) {
+
+	if path == "" {
+		err = ErrMissingPath
+		return
+	}
+	if len(key) != KeySize {
+		err = ErrKeySize
+		return
+	}
+
+	kr, err = openFile(path, nil, key)
+
+	return
+}
+
+// Backend returns BackendFile.
+func (f *fileKeyring) Backend() (backend Backend) {
+
+	backend = BackendFile
+
+	return
+}
+
+// Get implements Keyring.Get.
+func (f *fileKeyring) Get(key string) (item *Item, err error) {
+
+	var ok bool
+	var stored *Item
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		err = ErrClosed
+		return
+	}
+	if stored, ok = f.items[key]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	item = copyItem(stored, true)
+
+	return
+}
+
+// Set implements Keyring.Set.
+func (f *fileKeyring) Set(item *Item) (err error) {
+
+	var ok bool
+	var old *Item
+
+	if item == nil || item.Key == "" {
+		err = ErrMissingKey
+		return
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		err = ErrClosed
+		return
+	}
+
+	old, ok = f.items[item.Key]
+	f.items[item.Key] = copyItem(item, true)
+
+	if err = f.save(); err != nil {
+		// Keep memory in sync with the file.
+		if ok {
+			f.items[item.Key] = old
+		} else {
+			delete(f.items, item.Key)
+		}
+		return
+	}
+	if ok {
+		wipe(old.Secret)
+	}
+
+	return
+}
+
+// Delete implements Keyring.Delete.
+func (f *fileKeyring) Delete(key string) (err error) {
+
+	var ok bool
+	var old *Item
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		err = ErrClosed
+		return
+	}
+	if old, ok = f.items[key]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	delete(f.items, key)
+
+	if err = f.save(); err != nil {
+		f.items[key] = old
+		return
+	}
+	wipe(old.Secret)
+
+	return
+}
+
+// List implements Keyring.List.
+func (f *fileKeyring) List() (items []*Item, err error) {
+
+	items, err = f.Search(nil)
+
+	return
+}
+
+// Search implements Keyring.Search.
+func (f *fileKeyring) Search(attrs map[string]string) (items []*Item, err error) {
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.closed {
+		err = ErrClosed
+		return
+	}
+
+	items = make([]*Item, 0)
+	for _, item := range f.items {
+		if matchAttrs(item.Attributes, attrs) {
+			items = append(items, copyItem(item, false))
+		}
+	}
+	sortItems(items)
+
+	return
+}
+
+// Close wipes the key and all Items' Secrets from memory.
+func (f *fileKeyring) Close() (err error) {
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	wipe(f.key)
+	for _, item := range f.items {
+		wipe(item.Secret)
+	}
+	f.items = nil
+	f.closed = true
+
+	return
+}
+
+/*
+	openFile opens (reads and decrypts) the file at path, if it exists.
+	Exactly one of password and key must be set.
+*/
+func openFile(path string, password, key []byte) (kr Keyring, err error) {
+
+	var b []byte
+	var plain []byte
+	var list []*Item
+	var f fileKeyring = fileKeyring{
+		path:  path,
+		items: make(map[string]*Item),
+	}
+
+	if b, err = os.ReadFile(path); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		err = nil
+		// A new file.
+		f.header.Version = FileVersion
+		if key != nil {
+			f.header.KDF = kdfNone
+			f.key = append([]byte{}, key...)
+		} else {
+			f.header.KDF = kdfArgon2id
+			f.header.Time = ArgonTime
+			f.header.Memory = ArgonMemory
+			f.header.Threads = ArgonThreads
+			f.header.Salt = make([]byte, SaltSize)
+			if _, err = rand.Read(f.header.Salt); err != nil {
+				return
+			}
+			f.key = f.header.deriveKey(password)
+		}
+		kr = &f
+		return
+	}
+
+	if err = json.Unmarshal(b, &f.header); err != nil || f.header.Version != FileVersion {
+		err = ErrFileFormat
+		return
+	}
+
+	switch f.header.KDF {
+	case kdfNone:
+		if key == nil {
+			err = ErrBadPassword
+			return
+		}
+		f.key = append([]byte{}, key...)
+	case kdfArgon2id:
+		if password == nil {
+			err = ErrBadPassword
+			return
+		}
+		if !f.header.validArgon() {
+			err = ErrFileFormat
+			return
+		}
+		f.key = f.header.deriveKey(password)
+	default:
+		err = ErrFileFormat
+		return
+	}
+
+	if plain, err = f.header.open(f.key); err != nil {
+		wipe(f.key)
+		return
+	}
+	defer wipe(plain)
+
+	if err = json.Unmarshal(plain, &list); err != nil {
+		wipe(f.key)
+		err = ErrFileFormat
+		return
+	}
+	for _, item := range list {
+		if item == nil || item.Key == "" {
+			continue
+		}
+		f.items[item.Key] = item
+	}
+
+	kr = &f
+
+	return
+}
+
+// save encrypts and (atomically) writes the Items to the file. f.lock must be held.
+func (f *fileKeyring) save() (err error) {
+
+	var b []byte
+	var plain []byte
+	var tmp *os.File
+	var list []*Item = make([]*Item, 0, len(f.items))
+	var dir string = filepath.Dir(f.path)
+
+	for _, item := range f.items {
+		list = append(list, item)
+	}
+	sortItems(list)
+
+	if plain, err = json.Marshal(list); err != nil {
+		return
+	}
+	defer wipe(plain)
+
+	if err = f.header.seal(f.key, plain); err != nil {
+		return
+	}
+	if b, err = json.MarshalIndent(f.header, "", "\t"); err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(dir, dirMode); err != nil {
+		return
+	}
+	if tmp, err = os.CreateTemp(dir, "."+filepath.Base(f.path)+".*"); err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = tmp.Chmod(fileMode); err != nil {
+		_ = tmp.Close()
+		return
+	}
+	if _, err = tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return
+	}
+	if err = tmp.Close(); err != nil {
+		return
+	}
+
+	err = os.Rename(tmp.Name(), f.path)
+
+	return
+}
+
+/*
+	validArgon returns true if h's Argon2id parameters are set and within maxArgonTime and maxArgonMemory.
+	They are checked before deriving the key, as the header is only authenticated once the key is known.
+*/
+func (h *fileHeader) validArgon() (valid bool) {
+
+	valid = len(h.Salt) > 0 &&
+		h.Time > 0 && h.Time <= maxArgonTime &&
+		h.Memory > 0 && h.Memory <= maxArgonMemory &&
+		h.Threads > 0
+
+	return
+}
+
+// deriveKey derives the AES-256 key from password with h's Argon2id parameters.
+func (h *fileHeader) deriveKey(password []byte) (key []byte) {
+
+	key = argon2.IDKey(password, h.Salt, h.Time, h.Memory, h.Threads, uint32(KeySize))
+
+	return
+}
+
+// seal encrypts plain into h.Data under key, with a new random h.Nonce.
+func (h *fileHeader) seal(key, plain []byte) (err error) {
+
+	var aead cipher.AEAD
+	var nonce []byte
+
+	if aead, err = newAEAD(key); err != nil {
+		return
+	}
+
+	nonce = make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return
+	}
+
+	h.Nonce = nonce
+	h.Data = aead.Seal(nil, nonce, plain, h.additionalData())
+
+	return
+}
+
+// open decrypts h.Data under key. If that fails (e.g. the key is wrong), err will be ErrBadPassword.
+func (h *fileHeader) open(key []byte) (plain []byte, err error) {
+
+	var aead cipher.AEAD
+
+	if aead, err = newAEAD(key); err != nil {
+		return
+	}
+	if len(h.Nonce) != aead.NonceSize() {
+		err = ErrFileFormat
+		return
+	}
+
+	if plain, err = aead.Open(nil, h.Nonce, h.Data, h.additionalData()); err != nil {
+		err = ErrBadPassword
+		return
+	}
+
+	return
+}
+
+// additionalData binds the (unencrypted) header fields to the ciphertext, so they can't be altered undetected.
+func (h *fileHeader) additionalData() (ad []byte) {
+
+	var b []byte = make([]byte, 4)
+
+	binary.BigEndian.PutUint32(b, uint32(h.Version))
+	ad = append(ad, b...)
+	ad = append(ad, byte(len(h.KDF)))
+	ad = append(ad, h.KDF...)
+	ad = append(ad, byte(len(h.Salt)))
+	ad = append(ad, h.Salt...)
+	binary.BigEndian.PutUint32(b, h.Time)
+	ad = append(ad, b...)
+	binary.BigEndian.PutUint32(b, h.Memory)
+	ad = append(ad, b...)
+	ad = append(ad, h.Threads)
+
+	return
+}
+
+// newAEAD returns AES-GCM for key.
+func newAEAD(key []byte) (aead cipher.AEAD, err error) {
+
+	var block cipher.Block
+
+	if block, err = aes.NewCipher(key); err != nil {
+		return
+	}
+
+	aead, err = cipher.NewGCM(block)
+
+	return
+}
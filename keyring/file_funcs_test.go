@@ -0,0 +1,138 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/*
+	TestFile tests the following internal functions/methods via nested calls:
+
+		NewFile
+		NewFileWithKey
+			openFile
+				fileHeader.deriveKey
+				fileHeader.open
+		fileKeyring.Set
+			fileKeyring.save
+				fileHeader.seal
+				fileHeader.additionalData
+		fileKeyring.Get
+		fileKeyring.Delete
+		fileKeyring.List
+		fileKeyring.Search
+		fileKeyring.Close
+
+*/
+func TestFile(t *testing.T) {
+
+	var err error
+	var kr Keyring
+	var item *Item
+	var b []byte
+	var fi os.FileInfo
+	var dir string = t.TempDir()
+	var path string = filepath.Join(dir, "sub", "keyring.json")
+	var password []byte = []byte("correct horse battery staple")
+	var key []byte = bytes.Repeat([]byte{0x42}, KeySize)
+
+	if _, err = NewFile("", password); !errors.Is(err, ErrMissingPath) {
+		t.Errorf("NewFile without a path returned '%v'; expected ErrMissingPath", err)
+	}
+	if _, err = NewFile(path, nil); !errors.Is(err, ErrMissingPassword) {
+		t.Errorf("NewFile without a password returned '%v'; expected ErrMissingPassword", err)
+	}
+	if _, err = NewFileWithKey(path, key[:16]); !errors.Is(err, ErrKeySize) {
+		t.Errorf("NewFileWithKey with a short key returned '%v'; expected ErrKeySize", err)
+	}
+
+	if kr, err = NewFile(path, password); err != nil {
+		t.Fatalf("NewFile failed: %v", err.Error())
+	}
+	testKeyring(t, kr)
+	if err = kr.Close(); err != nil {
+		t.Errorf("Close failed: %v", err.Error())
+	}
+
+	if fi, err = os.Stat(path); err != nil {
+		t.Fatalf("keyring file was not written: %v", err.Error())
+	}
+	if fi.Mode().Perm() != fileMode {
+		t.Errorf("keyring file has mode %v; expected %v", fi.Mode().Perm(), fileMode)
+	}
+	if b, err = os.ReadFile(path); err != nil {
+		t.Fatalf("could not read keyring file: %v", err.Error())
+	}
+	if bytes.Contains(b, []byte("correct horse")) || bytes.Contains(b, []byte("Database")) {
+		t.Errorf("keyring file contains plaintext")
+	}
+
+	// Reopen.
+	if _, err = NewFile(path, []byte("wrong")); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("NewFile with a wrong password returned '%v'; expected ErrBadPassword", err)
+	}
+	if _, err = NewFileWithKey(path, key); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("NewFileWithKey on a password file returned '%v'; expected ErrBadPassword", err)
+	}
+	if kr, err = NewFile(path, password); err != nil {
+		t.Fatalf("NewFile (reopen) failed: %v", err.Error())
+	}
+	if item, err = kr.Get("db"); err != nil {
+		t.Fatalf("Get after reopen failed: %v", err.Error())
+	}
+	if !bytes.Equal(item.Secret, []byte("correct horse")) || item.Attributes["host"] != "db2" {
+		t.Errorf("Get after reopen returned %#v", item)
+	}
+	if err = kr.Close(); err != nil {
+		t.Errorf("Close failed: %v", err.Error())
+	}
+
+	// Tampering with the (unencrypted) header is detected.
+	b = bytes.Replace(b, []byte(`"time": 3`), []byte(`"time": 1`), 1)
+	if err = os.WriteFile(path, b, fileMode); err != nil {
+		t.Fatalf("could not write keyring file: %v", err.Error())
+	}
+	if _, err = NewFile(path, password); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("NewFile with a tampered header returned '%v'; expected ErrBadPassword", err)
+	}
+
+	// Unreasonable Argon2id parameters are rejected before deriving the key.
+	for _, tampered := range [][]byte{
+		bytes.Replace(b, []byte(`"time": 1`), []byte(`"time": 4294967295`), 1),
+		bytes.Replace(b, []byte(`"memory": 65536`), []byte(`"memory": 4294967295`), 1),
+	} {
+		if err = os.WriteFile(path, tampered, fileMode); err != nil {
+			t.Fatalf("could not write keyring file: %v", err.Error())
+		}
+		if _, err = NewFile(path, password); !errors.Is(err, ErrFileFormat) {
+			t.Errorf("NewFile with unreasonable Argon2id parameters returned '%v'; expected ErrFileFormat", err)
+		}
+	}
+
+	// A key file.
+	path = filepath.Join(dir, "key.json")
+	if kr, err = NewFileWithKey(path, key); err != nil {
+		t.Fatalf("NewFileWithKey failed: %v", err.Error())
+	}
+	if err = kr.Set(&Item{Key: "k", Secret: []byte("v")}); err != nil {
+		t.Fatalf("Set failed: %v", err.Error())
+	}
+	if err = kr.Close(); err != nil {
+		t.Errorf("Close failed: %v", err.Error())
+	}
+	if _, err = NewFile(path, password); !errors.Is(err, ErrBadPassword) {
+		t.Errorf("NewFile on a key file returned '%v'; expected ErrBadPassword", err)
+	}
+	if kr, err = NewFileWithKey(path, key); err != nil {
+		t.Fatalf("NewFileWithKey (reopen) failed: %v", err.Error())
+	}
+	if item, err = kr.Get("k"); err != nil || !bytes.Equal(item.Secret, []byte("v")) {
+		t.Errorf("Get after reopen returned %#v (err: %v)", item, err)
+	}
+	if err = kr.Close(); err != nil {
+		t.Errorf("Close failed: %v", err.Error())
+	}
+}
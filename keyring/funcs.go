@@ -0,0 +1,153 @@
+package keyring
+
+import (
+	"fmt"
+	"sort"
+
+	"r00t2.io/goutils/multierr"
+	"r00t2.io/gosecret"
+)
+
+/*
+	Open returns a Keyring using the first of cfg.Backends (or DefaultBackends) that can be opened:
+
+		BackendSecretService: gosecret.NewService(cfg.ServiceOptions...) and cfg.Collection (see FromService).
+		BackendFile:          NewFileWithKey(cfg.FilePath, cfg.FileKey) if cfg.FileKey is set,
+		                      otherwise NewFile(cfg.FilePath, cfg.FilePassword).
+		BackendMemory:        NewMemory.
+
+	If no backend can be opened, err is a *multierr.MultiError containing ErrNoBackend and each backend's error
+	(see gosecret.ErrorIs and gosecret.ErrorAs).
+*/
+func Open(cfg *Config) (kr Keyring, err error) {
+
+	var e error
+	var backends []Backend = DefaultBackends
+	var errs *multierr.MultiError = multierr.NewMultiError()
+
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	if len(cfg.Backends) > 0 {
+		backends = cfg.Backends
+	}
+
+	errs.AddError(ErrNoBackend)
+
+	for _, b := range backends {
+		if kr, e = openBackend(cfg, b); e != nil {
+			errs.AddError(fmt.Errorf("%v: %w", b, e))
+			continue
+		}
+		return
+	}
+
+	err = errs
+
+	return
+}
+
+/*
+	ParseBackend returns the Backend named s (e.g. from a command-line flag or environment variable).
+	If s does not name a Backend, err will be ErrUnknownBackend.
+*/
+func ParseBackend(s string) (backend Backend, err error) {
+
+	switch Backend(s) {
+	case BackendSecretService, BackendFile, BackendMemory:
+		backend = Backend(s)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownBackend, s)
+	}
+
+	return
+}
+
+// openBackend opens the Keyring for a single Backend.
+func openBackend(cfg *Config, backend Backend) (kr Keyring, err error) {
+
+	var svc *gosecret.Service
+	var collection string = cfg.Collection
+
+	switch backend {
+	case BackendSecretService:
+		if collection == "" {
+			collection = DefaultCollection
+		}
+		if svc, err = gosecret.NewService(cfg.ServiceOptions...); err != nil {
+			return
+		}
+		if kr, err = FromService(svc, collection); err != nil {
+			_ = svc.Close()
+			return
+		}
+		kr.(*serviceKeyring).ownsService = true
+	case BackendFile:
+		if len(cfg.FileKey) > 0 {
+			kr, err = NewFileWithKey(cfg.FilePath, cfg.FileKey)
+		} else {
+			kr, err = NewFile(cfg.FilePath, cfg.FilePassword)
+		}
+	case BackendMemory:
+		kr = NewMemory()
+	default:
+		err = ErrUnknownBackend
+	}
+
+	return
+}
+
+// copyItem returns a deep copy of item; the Secret is only copied if withSecret is true.
+func copyItem(item *Item, withSecret bool) (copied *Item) {
+
+	copied = &Item{
+		Key:   item.Key,
+		Label: item.Label,
+	}
+
+	if item.Attributes != nil {
+		copied.Attributes = make(map[string]string, len(item.Attributes))
+		for k, v := range item.Attributes {
+			copied.Attributes[k] = v
+		}
+	}
+	if withSecret && item.Secret != nil {
+		copied.Secret = append([]byte{}, item.Secret...)
+	}
+
+	return
+}
+
+// matchAttrs returns true if have contains all of want (with the same values).
+func matchAttrs(have, want map[string]string) (match bool) {
+
+	var ok bool
+	var v string
+
+	for k, w := range want {
+		if v, ok = have[k]; !ok || v != w {
+			return
+		}
+	}
+
+	match = true
+
+	return
+}
+
+// sortItems sorts items by Key.
+func sortItems(items []*Item) {
+
+	sort.Slice(items, func(i, j int) (less bool) {
+		less = items[i].Key < items[j].Key
+		return
+	})
+}
+
+// wipe zeroes b.
+func wipe(b []byte) {
+
+	for i := range b {
+		b[i] = 0
+	}
+}
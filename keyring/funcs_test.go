@@ -0,0 +1,179 @@
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"r00t2.io/gosecret"
+)
+
+/*
+	testKeyring exercises the Keyring interface against kr, which must be empty.
+	It is shared by the tests of all backends.
+*/
+func testKeyring(t *testing.T, kr Keyring) {
+
+	var err error
+	var item *Item
+	var items []*Item
+	var db *Item = &Item{
+		Key: "db", Label: "Database", Attributes: map[string]string{"host": "db1", "env": "prod"}, Secret: []byte("hunter2"),
+	}
+	var api *Item = &Item{
+		Key: "api", Label: "API token", Attributes: map[string]string{"env": "prod"}, Secret: []byte("s3cr3t"),
+	}
+
+	if _, err = kr.Get("db"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a missing key returned '%v'; expected ErrNotFound", err)
+	}
+	if err = kr.Set(&Item{Label: "no key"}); !errors.Is(err, ErrMissingKey) {
+		t.Errorf("Set without a key returned '%v'; expected ErrMissingKey", err)
+	}
+
+	if err = kr.Set(db); err != nil {
+		t.Fatalf("Set failed: %v", err.Error())
+	}
+	if err = kr.Set(api); err != nil {
+		t.Fatalf("Set failed: %v", err.Error())
+	}
+
+	if item, err = kr.Get("db"); err != nil {
+		t.Fatalf("Get failed: %v", err.Error())
+	}
+	if item.Key != db.Key || item.Label != db.Label || !bytes.Equal(item.Secret, db.Secret) ||
+		len(item.Attributes) != 2 || item.Attributes["host"] != "db1" {
+		t.Errorf("Get returned %#v; expected %#v", item, db)
+	}
+
+	// Update in place.
+	db.Secret = []byte("correct horse")
+	db.Label = "Database (rotated)"
+	db.Attributes = map[string]string{"host": "db2", "env": "prod"}
+	if err = kr.Set(db); err != nil {
+		t.Fatalf("Set (update) failed: %v", err.Error())
+	}
+	if item, err = kr.Get("db"); err != nil {
+		t.Fatalf("Get failed: %v", err.Error())
+	}
+	if item.Label != db.Label || !bytes.Equal(item.Secret, db.Secret) || item.Attributes["host"] != "db2" {
+		t.Errorf("Get after update returned %#v; expected %#v", item, db)
+	}
+
+	if items, err = kr.List(); err != nil {
+		t.Fatalf("List failed: %v", err.Error())
+	}
+	if len(items) != 2 || items[0].Key != "api" || items[1].Key != "db" {
+		t.Errorf("List returned %#v; expected api and db", items)
+	}
+	for _, i := range items {
+		if i.Secret != nil {
+			t.Errorf("List returned the Secret of '%v'", i.Key)
+		}
+	}
+
+	if items, err = kr.Search(map[string]string{"env": "prod"}); err != nil {
+		t.Fatalf("Search failed: %v", err.Error())
+	}
+	if len(items) != 2 {
+		t.Errorf("Search returned %d items; expected 2", len(items))
+	}
+	if items, err = kr.Search(map[string]string{"host": "db2"}); err != nil {
+		t.Fatalf("Search failed: %v", err.Error())
+	}
+	if len(items) != 1 || items[0].Key != "db" {
+		t.Errorf("Search returned %#v; expected db", items)
+	}
+	if items, err = kr.Search(map[string]string{"host": "db1"}); err != nil {
+		t.Fatalf("Search failed: %v", err.Error())
+	}
+	if len(items) != 0 {
+		t.Errorf("Search for a replaced attribute returned %#v", items)
+	}
+
+	if err = kr.Delete("api"); err != nil {
+		t.Fatalf("Delete failed: %v", err.Error())
+	}
+	if err = kr.Delete("api"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete of a missing key returned '%v'; expected ErrNotFound", err)
+	}
+	if _, err = kr.Get("api"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a deleted key returned '%v'; expected ErrNotFound", err)
+	}
+}
+
+/*
+	TestMemory tests the following internal functions/methods via nested calls:
+
+		NewMemory
+		memoryKeyring.Get
+		memoryKeyring.Set
+		memoryKeyring.Delete
+		memoryKeyring.List
+		memoryKeyring.Search
+			matchAttrs
+			sortItems
+		memoryKeyring.Close
+
+*/
+func TestMemory(t *testing.T) {
+
+	var err error
+	var kr Keyring = NewMemory()
+
+	testKeyring(t, kr)
+
+	if err = kr.Close(); err != nil {
+		t.Errorf("Close failed: %v", err.Error())
+	}
+	if _, err = kr.List(); !errors.Is(err, ErrClosed) {
+		t.Errorf("List after Close returned '%v'; expected ErrClosed", err)
+	}
+}
+
+/*
+	TestOpen tests the following internal functions/methods via nested calls:
+
+		ParseBackend
+		Open
+			openBackend
+
+*/
+func TestOpen(t *testing.T) {
+
+	var err error
+	var kr Keyring
+	var backend Backend
+
+	if backend, err = ParseBackend("file"); err != nil || backend != BackendFile {
+		t.Errorf("ParseBackend(\"file\") returned %v, %v", backend, err)
+	}
+	if _, err = ParseBackend("carrier-pigeon"); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("ParseBackend of an unknown backend returned '%v'; expected ErrUnknownBackend", err)
+	}
+
+	// Nothing can be opened.
+	if _, err = Open(&Config{
+		Backends:       []Backend{BackendSecretService, BackendFile},
+		ServiceOptions: []gosecret.Option{gosecret.WithBusAddress("unix:path=" + filepath.Join(t.TempDir(), "nobus"))},
+	}); !gosecret.ErrorIs(err, ErrNoBackend) || !gosecret.ErrorIs(err, ErrMissingPath) {
+		t.Errorf("Open with no usable backend returned '%v'; expected ErrNoBackend and ErrMissingPath", err)
+	}
+
+	// Falls back.
+	if kr, err = Open(&Config{
+		Backends:       []Backend{BackendSecretService, BackendFile, BackendMemory},
+		ServiceOptions: []gosecret.Option{gosecret.WithBusAddress("unix:path=" + filepath.Join(t.TempDir(), "nobus"))},
+		FilePath:       filepath.Join(t.TempDir(), "keyring.json"),
+		FileKey:        make([]byte, KeySize),
+	}); err != nil {
+		t.Fatalf("Open failed: %v", err.Error())
+	}
+	if kr.Backend() != BackendFile {
+		t.Errorf("Open selected %v; expected %v", kr.Backend(), BackendFile)
+	}
+	if err = kr.Close(); err != nil {
+		t.Errorf("Close failed: %v", err.Error())
+	}
+}
@@ -0,0 +1,138 @@
+package keyring
+
+// NewMemory returns an empty Keyring that keeps its Items in memory only.
+func NewMemory() (kr Keyring) {
+
+	kr = &memoryKeyring{
+		items: make(map[string]*Item),
+	}
+
+	return
+}
+
+// Backend returns BackendMemory.
+func (m *memoryKeyring) Backend() (backend Backend) {
+
+	backend = BackendMemory
+
+	return
+}
+
+// Get implements Keyring.Get.
+func (m *memoryKeyring) Get(key string) (item *Item, err error) {
+
+	var ok bool
+	var stored *Item
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.closed {
+		err = ErrClosed
+		return
+	}
+	if stored, ok = m.items[key]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	item = copyItem(stored, true)
+
+	return
+}
+
+// Set implements Keyring.Set.
+func (m *memoryKeyring) Set(item *Item) (err error) {
+
+	var ok bool
+	var old *Item
+
+	if item == nil || item.Key == "" {
+		err = ErrMissingKey
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		err = ErrClosed
+		return
+	}
+	if old, ok = m.items[item.Key]; ok {
+		wipe(old.Secret)
+	}
+
+	m.items[item.Key] = copyItem(item, true)
+
+	return
+}
+
+// Delete implements Keyring.Delete.
+func (m *memoryKeyring) Delete(key string) (err error) {
+
+	var ok bool
+	var old *Item
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		err = ErrClosed
+		return
+	}
+	if old, ok = m.items[key]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	wipe(old.Secret)
+	delete(m.items, key)
+
+	return
+}
+
+// List implements Keyring.List.
+func (m *memoryKeyring) List() (items []*Item, err error) {
+
+	items, err = m.Search(nil)
+
+	return
+}
+
+// Search implements Keyring.Search.
+func (m *memoryKeyring) Search(attrs map[string]string) (items []*Item, err error) {
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	if m.closed {
+		err = ErrClosed
+		return
+	}
+
+	items = make([]*Item, 0)
+	for _, item := range m.items {
+		if matchAttrs(item.Attributes, attrs) {
+			items = append(items, copyItem(item, false))
+		}
+	}
+	sortItems(items)
+
+	return
+}
+
+// Close wipes all Items' Secrets.
+func (m *memoryKeyring) Close() (err error) {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, item := range m.items {
+		wipe(item.Secret)
+	}
+	m.items = nil
+	m.closed = true
+
+	return
+}
@@ -0,0 +1,306 @@
+package keyring
+
+import (
+	"strings"
+
+	"r00t2.io/gosecret"
+)
+
+/*
+	FromService returns a Keyring storing Items in service's Collection named collection (a name, alias or label;
+	see gosecret.Service.GetCollection). Each Item's Key is stored in its AttrKey attribute.
+
+	The Collection is unlocked (which may Prompt; see gosecret.Service.Prompter) whenever it is found locked.
+	Keyring.Close does not close service.
+*/
+func FromService(service *gosecret.Service, collection string) (kr Keyring, err error) {
+
+	var s serviceKeyring = serviceKeyring{
+		service: service,
+	}
+
+	if s.collection, err = service.GetCollection(collection); err != nil {
+		return
+	}
+
+	kr = &s
+
+	return
+}
+
+// Backend returns BackendSecretService.
+func (s *serviceKeyring) Backend() (backend Backend) {
+
+	backend = BackendSecretService
+
+	return
+}
+
+// Get implements Keyring.Get.
+func (s *serviceKeyring) Get(key string) (item *Item, err error) {
+
+	var found *gosecret.Item
+
+	if key == "" {
+		err = ErrMissingKey
+		return
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		err = ErrClosed
+		return
+	}
+	if found, err = s.find(key); err != nil {
+		return
+	}
+
+	item = fromServiceItem(found)
+	if err = found.WithSecret(func(value gosecret.SecretValue) (err error) {
+		item.Secret = append([]byte{}, value...)
+		return
+	}); err != nil {
+		item = nil
+		return
+	}
+
+	return
+}
+
+/*
+	Set implements Keyring.Set.
+	An existing Item with the same Key is updated in place (Secret, then attributes and label);
+	otherwise a new one is created.
+*/
+func (s *serviceKeyring) Set(item *Item) (err error) {
+
+	var found *gosecret.Item
+	var secret *gosecret.Secret
+	var attrs map[string]string
+
+	if item == nil || item.Key == "" {
+		err = ErrMissingKey
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		err = ErrClosed
+		return
+	}
+
+	attrs = make(map[string]string, len(item.Attributes)+1)
+	for k, v := range item.Attributes {
+		attrs[k] = v
+	}
+	attrs[AttrKey] = item.Key
+
+	secret = gosecret.NewSecret(s.service.CurrentSession(), []byte{}, append([]byte{}, item.Secret...), defaultContentType)
+
+	if found, err = s.find(item.Key); err != nil && err != ErrNotFound {
+		return
+	}
+	if err == ErrNotFound {
+		_, err = s.collection.CreateItem(item.Label, attrs, secret, false)
+		return
+	}
+
+	if err = found.SetSecret(secret); err != nil {
+		return
+	}
+	if err = found.ReplaceAttributes(attrs); err != nil {
+		return
+	}
+	if found.Info().Label != item.Label {
+		if err = found.Relabel(item.Label); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Delete implements Keyring.Delete.
+func (s *serviceKeyring) Delete(key string) (err error) {
+
+	var found *gosecret.Item
+
+	if key == "" {
+		err = ErrMissingKey
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		err = ErrClosed
+		return
+	}
+	if found, err = s.find(key); err != nil {
+		return
+	}
+
+	err = found.Delete()
+
+	return
+}
+
+// List implements Keyring.List.
+func (s *serviceKeyring) List() (items []*Item, err error) {
+
+	var ok bool
+	var all []*gosecret.Item
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		err = ErrClosed
+		return
+	}
+	if err = s.unlock(); err != nil {
+		return
+	}
+	if all, err = s.collection.Items(); err != nil {
+		return
+	}
+
+	items = make([]*Item, 0, len(all))
+	for _, i := range all {
+		if _, ok = i.Info().Attributes[AttrKey]; ok {
+			items = append(items, fromServiceItem(i))
+		}
+	}
+	sortItems(items)
+
+	return
+}
+
+// Search implements Keyring.Search.
+func (s *serviceKeyring) Search(attrs map[string]string) (items []*Item, err error) {
+
+	var ok bool
+	var found []*gosecret.Item
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if s.closed {
+		err = ErrClosed
+		return
+	}
+	if found, err = s.search(attrs); err != nil {
+		return
+	}
+
+	items = make([]*Item, 0, len(found))
+	for _, i := range found {
+		if _, ok = i.Info().Attributes[AttrKey]; ok {
+			items = append(items, fromServiceItem(i))
+		}
+	}
+	sortItems(items)
+
+	return
+}
+
+// Close closes the gosecret.Service if it was opened by Open.
+func (s *serviceKeyring) Close() (err error) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+
+	if s.ownsService {
+		err = s.service.Close()
+	}
+
+	return
+}
+
+// find returns the Item with the given key, or ErrNotFound. s.lock must be held.
+func (s *serviceKeyring) find(key string) (item *gosecret.Item, err error) {
+
+	var found []*gosecret.Item
+
+	if found, err = s.search(map[string]string{AttrKey: key}); err != nil {
+		return
+	}
+	if len(found) == 0 {
+		err = ErrNotFound
+		return
+	}
+
+	// Should there somehow be more than one, use the first (by path) consistently.
+	item = found[0]
+
+	return
+}
+
+/*
+	search returns the Items in the Collection matching attrs, ordered by path (unlocking the Collection first
+	if needed). s.lock must be held.
+*/
+func (s *serviceKeyring) search(attrs map[string]string) (items []*gosecret.Item, err error) {
+
+	var unlocked []*gosecret.Item
+	var prefix string = string(s.collection.Dbus.Path()) + "/"
+
+	if err = s.unlock(); err != nil {
+		return
+	}
+	if unlocked, _, err = s.service.SearchItems(attrs); err != nil {
+		return
+	}
+
+	items = make([]*gosecret.Item, 0, len(unlocked))
+	for _, i := range unlocked {
+		if strings.HasPrefix(string(i.Dbus.Path()), prefix) {
+			items = append(items, i)
+		}
+	}
+
+	return
+}
+
+// unlock unlocks the Collection, if it is locked.
+func (s *serviceKeyring) unlock() (err error) {
+
+	var locked bool
+
+	if locked, err = s.collection.Locked(); err != nil || !locked {
+		return
+	}
+
+	err = s.collection.Unlock()
+
+	return
+}
+
+// fromServiceItem returns the Item (without its Secret) for a gosecret.Item.
+func fromServiceItem(i *gosecret.Item) (item *Item) {
+
+	var info gosecret.ItemInfo = i.Info()
+
+	item = &Item{
+		Key:        info.Attributes[AttrKey],
+		Label:      info.Label,
+		Attributes: make(map[string]string, len(info.Attributes)),
+	}
+	for k, v := range info.Attributes {
+		if k != AttrKey {
+			item.Attributes[k] = v
+		}
+	}
+
+	return
+}
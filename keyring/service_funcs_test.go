@@ -0,0 +1,95 @@
+package keyring
+
+import (
+	"errors"
+	"testing"
+
+	"r00t2.io/gosecret"
+	"r00t2.io/gosecret/gosecrettest"
+)
+
+/*
+	TestFromService tests the following internal functions/methods via nested calls:
+
+		FromService
+		serviceKeyring.Get
+			serviceKeyring.find
+				serviceKeyring.search
+					serviceKeyring.unlock
+		serviceKeyring.Set
+		serviceKeyring.Delete
+		serviceKeyring.List
+			fromServiceItem
+		serviceKeyring.Search
+		Open
+		serviceKeyring.Close
+
+	It uses a gosecrettest.Harness (skipping the test if dbus-daemon is not installed).
+*/
+func TestFromService(t *testing.T) {
+
+	var err error
+	var kr Keyring
+	var items []*Item
+	var collection *gosecret.Collection
+	var h *gosecrettest.Harness = gosecrettest.NewT(t)
+
+	if _, err = FromService(h.Service, "nonexistent"); err == nil {
+		t.Errorf("FromService with a nonexistent collection succeeded")
+	}
+	if kr, err = FromService(h.Service, gosecrettest.DefaultAlias); err != nil {
+		t.Fatalf("FromService failed: %v", err.Error())
+	}
+
+	// An Item not created via a Keyring is not visible through one.
+	if collection, err = h.Service.GetCollection(gosecrettest.DefaultAlias); err != nil {
+		t.Fatalf("could not get default collection: %v", err.Error())
+	}
+	if _, err = collection.CreateItem(
+		"foreign", map[string]string{"env": "prod"},
+		gosecret.NewSecret(h.Service.CurrentSession(), []byte{}, []byte("x"), "text/plain"), false,
+	); err != nil {
+		t.Fatalf("could not create item: %v", err.Error())
+	}
+
+	testKeyring(t, kr)
+
+	// A locked Collection is unlocked as needed.
+	if err = collection.Lock(); err != nil {
+		t.Fatalf("could not lock default collection: %v", err.Error())
+	}
+	if items, err = kr.List(); err != nil {
+		t.Fatalf("List of a locked collection failed: %v", err.Error())
+	}
+	if len(items) != 1 || items[0].Key != "db" {
+		t.Errorf("List returned %#v; expected db", items)
+	}
+
+	if err = kr.Close(); err != nil {
+		t.Errorf("Close failed: %v", err.Error())
+	}
+	if _, err = kr.Get("db"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Get after Close returned '%v'; expected ErrClosed", err)
+	}
+	// The Service is not closed.
+	if _, err = h.Service.GetCollection(gosecrettest.DefaultAlias); err != nil {
+		t.Errorf("FromService Keyring closed the Service: %v", err.Error())
+	}
+
+	// Via Open.
+	if kr, err = Open(&Config{
+		Backends:       []Backend{BackendSecretService, BackendMemory},
+		ServiceOptions: []gosecret.Option{gosecret.WithBusAddress(h.Address)},
+	}); err != nil {
+		t.Fatalf("Open failed: %v", err.Error())
+	}
+	if kr.Backend() != BackendSecretService {
+		t.Errorf("Open selected %v; expected %v", kr.Backend(), BackendSecretService)
+	}
+	if items, err = kr.List(); err != nil || len(items) != 1 {
+		t.Errorf("List via Open returned %#v (err: %v)", items, err)
+	}
+	if err = kr.Close(); err != nil {
+		t.Errorf("Close failed: %v", err.Error())
+	}
+}
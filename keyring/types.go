@@ -0,0 +1,109 @@
+package keyring
+
+import (
+	"sync"
+
+	"r00t2.io/gosecret"
+)
+
+/*
+	Keyring is a store of Items, identified by Item.Key.
+	All implementations are safe for concurrent use.
+*/
+type Keyring interface {
+	// Backend returns which backend the Keyring uses.
+	Backend() (backend Backend)
+	// Get returns the Item (including its Secret) with the given key, or ErrNotFound.
+	Get(key string) (item *Item, err error)
+	// Set stores item, replacing any Item with the same Key.
+	Set(item *Item) (err error)
+	// Delete removes the Item with the given key, or returns ErrNotFound.
+	Delete(key string) (err error)
+	// List returns all Items, ordered by Key. Their Secret is not included (see Get).
+	List() (items []*Item, err error)
+	/*
+		Search returns the Items that have all of attrs (with the same values), ordered by Key.
+		Their Secret is not included (see Get).
+	*/
+	Search(attrs map[string]string) (items []*Item, err error)
+	// Close releases the Keyring's resources. It must not be used afterwards.
+	Close() (err error)
+}
+
+// Backend names a Keyring implementation (see Open).
+type Backend string
+
+// Item is a secret stored in a Keyring.
+type Item struct {
+	// Key uniquely identifies the Item within its Keyring.
+	Key string `json:"key"`
+	// Label is a human-readable description of the Item.
+	Label string `json:"label"`
+	// Attributes are searchable (unencrypted, in the SecretService) metadata; see Keyring.Search.
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Secret is the secret value. It is nil in Items returned by Keyring.List and Keyring.Search.
+	Secret []byte `json:"secret,omitempty"`
+}
+
+// Config selects and configures the backend(s) tried by Open.
+type Config struct {
+	// Backends are tried in order; the first one that can be opened is used. If empty, DefaultBackends is used.
+	Backends []Backend
+	// ServiceOptions are passed to gosecret.NewService for BackendSecretService.
+	ServiceOptions []gosecret.Option
+	// Collection is the SecretService Collection (name, alias or label) for BackendSecretService; see DefaultCollection.
+	Collection string
+	// FilePath is the encrypted file for BackendFile. It is created on the first Keyring.Set if it does not exist.
+	FilePath string
+	// FilePassword is the password the key for BackendFile is derived from (see NewFile).
+	FilePassword []byte
+	// FileKey, if set, is used as the key for BackendFile instead of deriving one from FilePassword (see NewFileWithKey).
+	FileKey []byte
+}
+
+// memoryKeyring is the BackendMemory Keyring.
+type memoryKeyring struct {
+	lock   sync.RWMutex
+	items  map[string]*Item
+	closed bool
+}
+
+// fileKeyring is the BackendFile Keyring.
+type fileKeyring struct {
+	lock sync.Mutex
+	path string
+	// key is the AES-256 key; it is wiped on Close.
+	key []byte
+	// header is the (unencrypted) file header; only Nonce and Data change when the file is rewritten.
+	header fileHeader
+	items  map[string]*Item
+	closed bool
+}
+
+// fileHeader is the on-disk (JSON) format of the encrypted file.
+type fileHeader struct {
+	// Version is FileVersion.
+	Version int `json:"version"`
+	// KDF is kdfArgon2id or kdfNone.
+	KDF string `json:"kdf"`
+	// Salt, Time, Memory and Threads are the Argon2id parameters (if KDF is kdfArgon2id).
+	Salt    []byte `json:"salt,omitempty"`
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+	// Nonce is the AES-GCM nonce used for Data.
+	Nonce []byte `json:"nonce"`
+	// Data is the AES-GCM encrypted JSON of the Items.
+	Data []byte `json:"data"`
+}
+
+// serviceKeyring is the BackendSecretService Keyring.
+type serviceKeyring struct {
+	service    *gosecret.Service
+	collection *gosecret.Collection
+	// ownsService is true if service was opened by Open (and so is closed by Close).
+	ownsService bool
+	// lock serializes Set/Delete (so that two Sets of the same Key can't both create an Item) and Close.
+	lock   sync.RWMutex
+	closed bool
+}
@@ -0,0 +1,36 @@
+package gosecret
+
+import (
+	`fmt`
+)
+
+// Error returns the Dbus path of the object and the reason it was not locked/unlocked.
+func (l *LockFailure) Error() (errStr string) {
+
+	errStr = fmt.Sprintf("%v: %v", l.Object.path(), l.Err)
+
+	return
+}
+
+// Unwrap returns LockFailure.Err (for errors.Is/errors.As).
+func (l *LockFailure) Unwrap() (err error) {
+
+	err = l.Err
+
+	return
+}
+
+/*
+	singleLockErr returns the error for a Lock/Unlock of a single object: its *LockFailure
+	(so errors.Is/errors.As work without ErrorIs/ErrorAs) if that is why err is set, otherwise err.
+*/
+func singleLockErr(result *LockResult, err error) (e error) {
+
+	e = err
+
+	if result != nil && len(result.Failed) == 1 {
+		e = result.Failed[0]
+	}
+
+	return
+}
@@ -0,0 +1,86 @@
+package gosecret_test
+
+import (
+	"errors"
+	"testing"
+
+	"r00t2.io/gosecret"
+	"r00t2.io/gosecret/gosecrettest"
+)
+
+/*
+	TestService_LockResult tests the LockResult returned by the following via nested calls:
+
+		Service.Lock
+		Service.Unlock
+			Service.setLocked
+		Collection.Unlock
+			singleLockErr
+
+*/
+func TestService_LockResult(t *testing.T) {
+
+	var err error
+	var h *gosecrettest.Harness = gosecrettest.NewT(t)
+	var first *gosecret.Collection
+	var second *gosecret.Collection
+	var result *gosecret.LockResult
+	var failure *gosecret.LockFailure
+
+	if first, err = h.Service.GetCollection(gosecrettest.DefaultAlias); err != nil {
+		t.Fatalf("could not get default collection: %v", err.Error())
+	}
+	if second, err = h.Service.CreateCollection("LockResult"); err != nil {
+		t.Fatalf("could not create collection: %v", err.Error())
+	}
+
+	if result, err = h.Service.Lock(first, second); err != nil {
+		t.Fatalf("Service.Lock failed: %v", err.Error())
+	}
+	if len(result.Immediate) != 2 || len(result.Prompted) != 0 || len(result.Failed) != 0 {
+		t.Errorf("unexpected LockResult for Service.Lock: %#v", result)
+	}
+	if !first.Info().Locked || !second.Info().Locked {
+		t.Errorf("Service.Lock did not refresh the lock state")
+	}
+
+	// first is unlocked without a prompt; second needs one.
+	if err = first.Unlock(); err != nil {
+		t.Fatalf("could not unlock default collection: %v", err.Error())
+	}
+	h.SetPromptAction(gosecrettest.PromptAccept, 0)
+	if result, err = h.Service.Unlock(first, second); err != nil {
+		t.Fatalf("Service.Unlock failed: %v", err.Error())
+	}
+	if len(result.Immediate) != 1 || result.Immediate[0] != first ||
+		len(result.Prompted) != 1 || result.Prompted[0] != second ||
+		len(result.PromptAffected) != 1 || result.PromptAffected[0] != second || len(result.Failed) != 0 {
+		t.Errorf("unexpected LockResult for Service.Unlock with an accepted prompt: %#v", result)
+	}
+	if second.Info().Locked {
+		t.Errorf("Service.Unlock did not refresh the lock state")
+	}
+
+	// Dismissed.
+	if err = second.Lock(); err != nil {
+		t.Fatalf("could not lock collection: %v", err.Error())
+	}
+	h.SetPromptAction(gosecrettest.PromptDismiss, 0)
+	if result, err = h.Service.Unlock(first, second); !gosecret.ErrorIs(err, gosecret.ErrPromptDismissed) {
+		t.Fatalf("Service.Unlock with a dismissed prompt returned '%v', expected ErrPromptDismissed", err)
+	}
+	if !gosecret.ErrorAs(err, &failure) || failure.Object != second {
+		t.Errorf("Service.Unlock error does not contain a LockFailure for the prompted collection: %v", err)
+	}
+	if result == nil || len(result.Immediate) != 1 || len(result.Prompted) != 1 ||
+		len(result.PromptAffected) != 0 || len(result.Failed) != 1 || result.Failed[0].Object != second {
+		t.Errorf("unexpected LockResult for Service.Unlock with a dismissed prompt: %#v", result)
+	}
+
+	// A single object's failure is returned directly, so errors.Is/errors.As work.
+	failure = nil
+	if err = second.Unlock(); !errors.Is(err, gosecret.ErrPromptDismissed) || !errors.As(err, &failure) ||
+		failure.Object != second {
+		t.Errorf("Collection.Unlock with a dismissed prompt returned '%v', expected its LockFailure", err)
+	}
+}
@@ -0,0 +1,105 @@
+package gosecret
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/internal/testbus"
+	"r00t2.io/gosecret/server"
+)
+
+/*
+	TestMain runs the tests against an in-memory SecretService provider on a private session bus,
+	so they never touch (or leave test collections behind in) the developer's keyring.
+
+	The live session bus is used instead if envLiveTests is set or dbus-daemon is not installed.
+*/
+func TestMain(m *testing.M) {
+
+	var err error
+	var code int
+	var cleanup func()
+
+	if os.Getenv(envLiveTests) == "" {
+		if cleanup, err = startTestProvider(); err != nil {
+			if !errors.Is(err, testbus.ErrNoDaemon) {
+				fmt.Fprintf(os.Stderr, "could not start test SecretService: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "%v; running tests against the live session bus\n", err)
+		}
+	}
+
+	code = m.Run()
+
+	if cleanup != nil {
+		cleanup()
+	}
+
+	os.Exit(code)
+}
+
+/*
+	startTestProvider starts a private session bus running a server.Server (with a "login" collection aliased to
+	"default", and prompting on collection creation as gnome-keyring does) and points DBUS_SESSION_BUS_ADDRESS at it.
+*/
+func startTestProvider() (cleanup func(), err error) {
+
+	var bus *testbus.Bus
+	var conn *dbus.Conn
+	var srv *server.Server
+	var storage *server.MemoryStorage = server.NewMemoryStorage()
+	var now time.Time = time.Now()
+
+	if err = storage.PutCollection(&server.CollectionRecord{
+		ID:       defaultCollection,
+		Label:    defaultCollectionLabel,
+		Created:  now,
+		Modified: now,
+	}); err != nil {
+		return
+	}
+	if err = storage.SetAlias(defaultCollectionAlias, defaultCollection); err != nil {
+		return
+	}
+
+	if bus, err = testbus.Start(); err != nil {
+		return
+	}
+	if conn, err = dbus.Connect(bus.Address); err != nil {
+		_ = bus.Close()
+		return
+	}
+	if srv, err = server.New(conn, storage); err != nil {
+		_ = conn.Close()
+		_ = bus.Close()
+		return
+	}
+	srv.PromptOnCreate = true
+
+	if err = srv.RequestName(server.DbusService); err != nil {
+		_ = srv.Close()
+		_ = conn.Close()
+		_ = bus.Close()
+		return
+	}
+
+	if err = os.Setenv("DBUS_SESSION_BUS_ADDRESS", bus.Address); err != nil {
+		_ = srv.Close()
+		_ = conn.Close()
+		_ = bus.Close()
+		return
+	}
+
+	cleanup = func() {
+		_ = srv.Close()
+		_ = conn.Close()
+		_ = bus.Close()
+	}
+
+	return
+}
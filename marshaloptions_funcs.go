@@ -0,0 +1,114 @@
+package gosecret
+
+import (
+	`encoding/json`
+	`unicode/utf8`
+)
+
+/*
+	Marshal returns the JSON encoding of v. If MarshalOptions.Reveal is false, this is the same as json.Marshal
+	(or json.MarshalIndent) and Secret values are redacted (see RedactedSecret).
+
+	If MarshalOptions.Reveal is true, the actual Secret values are included. v must then be one of:
+
+		SecretValue, *SecretValue
+		Secret, *Secret
+		*Item, []*Item
+
+	otherwise ErrRevealUnsupported is returned. (ItemInfo is not supported, as it never holds a Secret's Value.)
+
+	A JSON string can only hold valid UTF-8, so values that are not (e.g. binary keys) are marshaled as their
+	standard base64 encoding instead; a revealed Secret then has a "value_encoding" of ValueEncodingBase64.
+*/
+func (m MarshalOptions) Marshal(v interface{}) (b []byte, err error) {
+
+	if m.Reveal {
+		if v, err = revealable(v); err != nil {
+			return
+		}
+	}
+
+	if m.Prefix == "" && m.Indent == "" {
+		b, err = json.Marshal(v)
+	} else {
+		b, err = json.MarshalIndent(v, m.Prefix, m.Indent)
+	}
+
+	return
+}
+
+// revealable wraps v in the types below so that json.Marshal includes its Secret values.
+func revealable(v interface{}) (wrapped interface{}, err error) {
+
+	var items []revealedItem
+
+	switch t := v.(type) {
+	case SecretValue:
+		wrapped = revealedValue(t)
+	case *SecretValue:
+		if t != nil {
+			wrapped = revealedValue(*t)
+		}
+	case Secret:
+		wrapped = revealSecret(&t)
+	case *Secret:
+		wrapped = revealSecret(t)
+	case *Item:
+		wrapped = revealedItem{item: t}
+	case []*Item:
+		items = make([]revealedItem, len(t))
+		for idx, i := range t {
+			items[idx] = revealedItem{item: i}
+		}
+		wrapped = items
+	default:
+		err = ErrRevealUnsupported
+	}
+
+	return
+}
+
+// revealSecret returns secret wrapped for revealing (or nil if secret is nil).
+func revealSecret(secret *Secret) (revealed *revealedSecret) {
+
+	if secret == nil {
+		return
+	}
+
+	revealed = &revealedSecret{
+		Secret: secret,
+		Value:  revealedValue(secret.Value),
+	}
+	if !utf8.Valid(secret.Value) {
+		revealed.Encoding = ValueEncodingBase64
+	}
+
+	return
+}
+
+// MarshalJSON marshals the actual value (see SecretValue.marshalRevealed).
+func (r revealedValue) MarshalJSON() (b []byte, err error) {
+
+	b, err = SecretValue(r).marshalRevealed()
+
+	return
+}
+
+// MarshalJSON marshals the Item (under its lock) with its Secret value revealed.
+func (r revealedItem) MarshalJSON() (b []byte, err error) {
+
+	if r.item == nil {
+		b = []byte("null")
+		return
+	}
+
+	r.item.lock.RLock()
+	defer r.item.lock.RUnlock()
+
+	b, err = json.Marshal(revealedItemFields{
+		Item:   r.item,
+		Secret: revealSecret(r.item.Secret),
+	})
+
+	return
+}
@@ -0,0 +1,105 @@
+//go:build linux
+// +build linux
+
+package gosecret
+
+import (
+	`fmt`
+	`sync`
+	`syscall`
+	`unsafe`
+)
+
+const (
+	// lockedMemorySupported is true if allocLocked is implemented for this platform.
+	lockedMemorySupported bool = true
+	// madvDontDump is MADV_DONTDUMP (see madvise(2)); package syscall does not define it.
+	madvDontDump int = 0x10
+)
+
+var (
+	// lockedMaps maps the address of each buffer returned by allocLocked to its full mapping (for freeLocked).
+	lockedMaps     map[uintptr][]byte = make(map[uintptr][]byte)
+	lockedMapsLock sync.Mutex
+)
+
+/*
+	allocLocked returns an n-byte buffer in its own anonymous mapping which is locked into RAM (mlock(2); it will not be
+	swapped out), excluded from core dumps (MADV_DONTDUMP) and surrounded by inaccessible guard pages.
+	The buffer is placed at the end of its pages so that overruns fault on the trailing guard page.
+	It must be released with freeLocked.
+*/
+func allocLocked(n int) (b []byte, err error) {
+
+	var pageSize int = syscall.Getpagesize()
+	var dataLen int
+	var mapping []byte
+	var data []byte
+
+	if n <= 0 {
+		b = []byte{}
+		return
+	}
+
+	dataLen = ((n + pageSize - 1) / pageSize) * pageSize
+
+	if mapping, err = syscall.Mmap(
+		-1, 0, dataLen+(2*pageSize), syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE|syscall.MAP_ANONYMOUS,
+	); err != nil {
+		err = fmt.Errorf("%w: mmap: %v", ErrLockedMemory, err)
+		return
+	}
+	data = mapping[pageSize : pageSize+dataLen]
+
+	if err = syscall.Mprotect(mapping[:pageSize], syscall.PROT_NONE); err == nil {
+		err = syscall.Mprotect(mapping[pageSize+dataLen:], syscall.PROT_NONE)
+	}
+	if err != nil {
+		_ = syscall.Munmap(mapping)
+		err = fmt.Errorf("%w: mprotect: %v", ErrLockedMemory, err)
+		return
+	}
+	if err = syscall.Mlock(data); err != nil {
+		_ = syscall.Munmap(mapping)
+		err = fmt.Errorf("%w: mlock (check RLIMIT_MEMLOCK): %v", ErrLockedMemory, err)
+		return
+	}
+	// Older kernels do not support MADV_DONTDUMP; the memory is still locked, so this is not fatal.
+	_ = syscall.Madvise(data, madvDontDump)
+
+	b = data[dataLen-n : dataLen : dataLen]
+
+	lockedMapsLock.Lock()
+	lockedMaps[uintptr(unsafe.Pointer(&b[0]))] = mapping
+	lockedMapsLock.Unlock()
+
+	return
+}
+
+/*
+	freeLocked unmaps b if it was returned by allocLocked (and has not already been freed), returning true if so.
+	b should be zeroed first. b must not be used afterwards.
+*/
+func freeLocked(b []byte) (freed bool) {
+
+	var ok bool
+	var mapping []byte
+
+	if len(b) == 0 {
+		return
+	}
+
+	lockedMapsLock.Lock()
+	if mapping, ok = lockedMaps[uintptr(unsafe.Pointer(&b[0]))]; ok {
+		delete(lockedMaps, uintptr(unsafe.Pointer(&b[0])))
+	}
+	lockedMapsLock.Unlock()
+
+	if !ok {
+		return
+	}
+
+	freed = syscall.Munmap(mapping) == nil
+
+	return
+}
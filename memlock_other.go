@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package gosecret
+
+// lockedMemorySupported is true if allocLocked is implemented for this platform.
+const lockedMemorySupported bool = false
+
+// allocLocked is only implemented on Linux; see WithLockedMemory.
+func allocLocked(n int) (b []byte, err error) {
+
+	err = ErrLockedMemory
+
+	return
+}
+
+// freeLocked is only implemented on Linux; see WithLockedMemory.
+func freeLocked(b []byte) (freed bool) {
+
+	return
+}
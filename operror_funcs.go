@@ -0,0 +1,93 @@
+package gosecret
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	newOpError wraps err (returned by performing Dbus method/property op on path) in an *OpError,
+	translating it to a SecretServiceError if possible.
+	If err is nil, nil is returned. If err is already an *OpError, it is returned as-is.
+*/
+func newOpError(op string, path dbus.ObjectPath, err error) (opErr error) {
+
+	var ok bool
+	var ssErr SecretServiceError
+	var existing *OpError
+	var e OpError = OpError{
+		Op:   op,
+		Path: path,
+		Err:  err,
+	}
+
+	if err == nil {
+		return
+	}
+	if errors.As(err, &existing) {
+		opErr = err
+		return
+	}
+
+	switch d := err.(type) {
+	case dbus.Error:
+		e.DbusErr = &d
+	case *dbus.Error:
+		e.DbusErr = d
+	}
+
+	if ok, ssErr = TranslateDbusError(err); ok {
+		e.Err = ssErr
+	}
+
+	opErr = &e
+
+	return
+}
+
+// Error returns the string format of the error.
+func (e *OpError) Error() (errStr string) {
+
+	var translated bool
+
+	if _, translated = e.Err.(SecretServiceError); translated && e.DbusErr != nil {
+		errStr = fmt.Sprintf("%v (%v): %v (%v: %v)", e.Op, e.Path, e.Err.Error(), e.DbusErr.Name, e.DbusErr.Error())
+		return
+	}
+
+	errStr = fmt.Sprintf("%v (%v): %v", e.Op, e.Path, e.Err.Error())
+
+	return
+}
+
+// Unwrap returns OpError.Err (for errors.Is/errors.As).
+func (e *OpError) Unwrap() (err error) {
+
+	err = e.Err
+
+	return
+}
+
+/*
+	As allows errors.As to find the original Dbus error (OpError.DbusErr) as either a dbus.Error or a *dbus.Error,
+	even if OpError.Err is a translated SecretServiceError.
+*/
+func (e *OpError) As(target interface{}) (ok bool) {
+
+	if e.DbusErr == nil {
+		return
+	}
+
+	switch t := target.(type) {
+	case *dbus.Error:
+		*t = *e.DbusErr
+		ok = true
+	case **dbus.Error:
+		*t = e.DbusErr
+		ok = true
+	}
+
+	return
+}
@@ -0,0 +1,145 @@
+package gosecret
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/goutils/multierr"
+)
+
+/*
+	TestOpError tests the following internal functions/methods via nested calls:
+
+		newOpError
+			TranslateDbusError
+		OpError.Error
+		OpError.Unwrap
+		OpError.As
+		ErrorIs
+		ErrorAs
+		CheckErrIsFromLegacy
+
+*/
+func TestOpError(t *testing.T) {
+
+	var err error
+	var opErr *OpError
+	var dbusErr dbus.Error
+	var dbusErrPtr *dbus.Error
+	var multi *multierr.MultiError = multierr.NewMultiError()
+	var isLegacy bool
+	var parsed bool
+	var translated bool
+	var path dbus.ObjectPath = dbus.ObjectPath(dbusDefaultCollectionPath + "/1")
+
+	err = newOpError(DbusItemGetSecret, path, dbus.Error{Name: DbusErrIsLocked, Body: []interface{}{"locked"}})
+
+	if !errors.Is(err, ErrSecretServiceLocked) {
+		t.Errorf("errors.Is(err, ErrSecretServiceLocked) is false for '%v'", err.Error())
+	}
+	if !errors.As(err, &opErr) || opErr.Op != DbusItemGetSecret || opErr.Path != path {
+		t.Errorf("could not find the *OpError for '%v'", err.Error())
+	}
+	if !errors.As(err, &dbusErr) || dbusErr.Name != DbusErrIsLocked {
+		t.Errorf("could not find the original dbus.Error for '%v'", err.Error())
+	}
+	if !errors.As(err, &dbusErrPtr) || dbusErrPtr.Name != DbusErrIsLocked {
+		t.Errorf("could not find the original *dbus.Error for '%v'", err.Error())
+	}
+	if !strings.Contains(err.Error(), DbusItemGetSecret) || !strings.Contains(err.Error(), string(path)) {
+		t.Errorf("error string '%v' does not contain the op and path", err.Error())
+	}
+
+	// Already-wrapped errors are not re-wrapped.
+	if newOpError(DbusServiceGetSecrets, dbus.ObjectPath(DbusPath), err) != err {
+		t.Errorf("an *OpError was wrapped again")
+	}
+
+	// Untranslatable errors are still wrapped.
+	err = newOpError(DbusItemDelete, path, dbus.Error{Name: "org.example.Error.Other", Body: []interface{}{"other"}})
+	if translated, _ = TranslateDbusError(err); translated {
+		t.Errorf("an unknown Dbus error was translated")
+	}
+	if !errors.As(err, &opErr) || opErr.DbusErr == nil || opErr.DbusErr.Name != "org.example.Error.Other" {
+		t.Errorf("unknown Dbus error was not wrapped correctly: %#v", err)
+	}
+
+	multi.AddError(ErrMissingAttrs)
+	multi.AddError(newOpError(DbusItemType, path, dbus.Error{Name: DbusErrUnknownProperty, Body: []interface{}{"no Type"}}))
+
+	if !ErrorIs(multi, ErrSecretServiceUnknownProperty) || !ErrorIs(multi, ErrMissingAttrs) {
+		t.Errorf("ErrorIs failed to match errors contained in a *multierr.MultiError")
+	}
+	if ErrorIs(multi, ErrSecretServiceLocked) {
+		t.Errorf("ErrorIs matched an error not contained in a *multierr.MultiError")
+	}
+	if !ErrorAs(multi, &opErr) || opErr.Op != DbusItemType {
+		t.Errorf("ErrorAs failed to find an *OpError contained in a *multierr.MultiError")
+	}
+	if !ErrorIs(fmt.Errorf("wrapped: %w", multi), ErrMissingAttrs) {
+		t.Errorf("ErrorIs failed to match errors contained in a wrapped *multierr.MultiError")
+	}
+	if isLegacy, parsed = CheckErrIsFromLegacy(multi); !isLegacy || !parsed {
+		t.Errorf("CheckErrIsFromLegacy returned isLegacy %v, parsed %v for a missing Type property", isLegacy, parsed)
+	}
+}
+
+/*
+	TestOpError_Locked tests that errors from the SecretService are translated, via:
+
+		(all calls in TestCollection_Items)
+		Collection.Lock
+		Item.GetSecret
+		Collection.Unlock
+
+*/
+func TestOpError_Locked(t *testing.T) {
+
+	var svc *Service
+	var collection *Collection
+	var item *Item
+	var secret *Secret
+	var opErr *OpError
+	var err error
+
+	if svc, err = NewService(); err != nil {
+		t.Fatalf("NewService failed: %v", err.Error())
+	}
+
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	secret = NewSecret(svc.Session, []byte{}, []byte(testSecretContent), "text/plain")
+
+	if item, err = collection.CreateItem(testItemLabel, itemAttrs, secret, true); err != nil {
+		t.Fatalf("could not create item '%v' in collection '%v': %v", testItemLabel, defaultCollection, err.Error())
+	}
+
+	if err = collection.Lock(); err != nil {
+		t.Fatalf("could not lock collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	if _, err = item.GetSecret(svc.Session); err == nil {
+		t.Errorf("fetched secret from an item in a locked collection")
+	} else if !errors.Is(err, ErrSecretServiceLocked) {
+		t.Errorf("fetching secret from a locked item returned '%v', expected ErrSecretServiceLocked", err.Error())
+	} else if !errors.As(err, &opErr) || opErr.Path != item.Dbus.Path() {
+		t.Errorf("error '%v' does not have the item path", err.Error())
+	}
+
+	if err = collection.Unlock(); err != nil {
+		t.Errorf("could not unlock collection '%v': %v", defaultCollection, err.Error())
+	}
+
+	if err = item.Delete(); err != nil {
+		t.Errorf("failed to delete created item '%v': %v", string(item.Dbus.Path()), err.Error())
+	}
+
+	if err = svc.Close(); err != nil {
+		t.Errorf("could not close Service.Session: %v", err.Error())
+	}
+}
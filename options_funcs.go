@@ -0,0 +1,235 @@
+package gosecret
+
+import (
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	WithConn makes NewService use an existing Dbus connection instead of opening its own.
+	The connection is NOT closed by Service.Close; the caller retains ownership of it.
+*/
+func WithConn(conn *dbus.Conn) (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		if conn == nil {
+			err = ErrNoDbusConn
+			return
+		}
+		opts.conn = conn
+		opts.busAddress = ""
+		return
+	}
+
+	return
+}
+
+/*
+	WithBusAddress makes NewService connect to the bus at address (e.g. "unix:path=/run/user/1000/bus")
+	instead of the session bus. The connection is closed by Service.Close.
+*/
+func WithBusAddress(address string) (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		if strings.TrimSpace(address) == "" {
+			err = ErrBadOption
+			return
+		}
+		opts.busAddress = address
+		opts.conn = nil
+		return
+	}
+
+	return
+}
+
+/*
+	WithDestination makes NewService talk to the SecretService at bus name destination instead of DbusService
+	(e.g. for a test provider, or an implementation that does not claim org.freedesktop.secrets).
+	Collection, Item, Session and Prompt objects obtained via the Service use the same destination.
+*/
+func WithDestination(destination string) (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		if strings.TrimSpace(destination) == "" {
+			err = ErrBadOption
+			return
+		}
+		opts.destination = destination
+		return
+	}
+
+	return
+}
+
+/*
+	WithLegacy sets Service.Legacy (see its documentation).
+	Capability detection (see Service.DetectCapabilities) is skipped.
+*/
+func WithLegacy() (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		opts.legacy = true
+		return
+	}
+
+	return
+}
+
+/*
+	WithSessionAlgorithm makes NewService open the default Session (Service.Session) with algo
+	(e.g. SessionAlgoPlain) rather than negotiating one via Service.GetSession.
+*/
+func WithSessionAlgorithm(algo string) (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		switch algo {
+		case SessionAlgoPlain, SessionAlgoDH:
+			opts.sessionAlgo = algo
+		default:
+			err = ErrBadOption
+		}
+		return
+	}
+
+	return
+}
+
+/*
+	WithoutDefaultSession makes NewService skip opening the default Session; Service.Session will be nil.
+	Use Service.GetSession or Service.OpenSession to open one when needed.
+*/
+func WithoutDefaultSession() (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		opts.noSession = true
+		return
+	}
+
+	return
+}
+
+/*
+	WithoutCapabilityDetection makes NewService skip Service.DetectCapabilities;
+	Service.Capabilities will be nil and Service.Legacy will be false (unless WithLegacy is used).
+*/
+func WithoutCapabilityDetection() (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		opts.noDetect = true
+		return
+	}
+
+	return
+}
+
+// WithParallelism sets Service.Parallelism (see its documentation). n must be at least 1.
+func WithParallelism(n int) (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		if n < 1 {
+			err = ErrBadOption
+			return
+		}
+		opts.parallelism = n
+		return
+	}
+
+	return
+}
+
+/*
+	WithWaitForProvider makes NewService ensure the SecretService is running before opening the default Session,
+	e.g. for programs started at login alongside the keyring daemon.
+
+	The bus is asked to start (activate) it via StartServiceByName; if that isn't possible (e.g. there is no .service file
+	for it), NewService waits for the bus name to acquire an owner. If timeout is greater than 0 it limits the wait;
+	otherwise only the context passed to NewServiceContext does. ErrProviderUnavailable is returned if it never appears.
+*/
+func WithWaitForProvider(timeout time.Duration) (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		if timeout < 0 {
+			err = ErrBadOption
+			return
+		}
+		opts.waitProvider = true
+		opts.waitTimeout = timeout
+		return
+	}
+
+	return
+}
+
+/*
+	WithLockedMemory makes the Service store the Values of Secrets it fetches (e.g. via Item.GetSecret, Item.WithSecret
+	or Service.GetSecrets) in memory that is locked into RAM (so it is never written to swap), excluded from core dumps
+	and surrounded by guard pages.
+	The memory is released by SecretValue.Wipe (and so Item.ForgetSecret and Item.WithSecret); Values that are
+	never wiped stay allocated. Each Value uses at least one page of locked memory, which is limited by RLIMIT_MEMLOCK;
+	fetching a Secret fails with ErrLockedMemory if it is exhausted.
+
+	This is only supported on Linux; ErrLockedMemory is returned on other platforms.
+*/
+func WithLockedMemory() (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		if !lockedMemorySupported {
+			err = ErrLockedMemory
+			return
+		}
+		opts.lockedMemory = true
+		return
+	}
+
+	return
+}
+
+/*
+	WithReconnect makes the Service survive SecretService restarts (e.g. gnome-keyring-daemon being restarted).
+
+	The Service watches its destination's bus name (NameOwnerChanged) and, when the SecretService comes back,
+	re-opens Service.Session with the same algorithm (re-running the key exchange for SessionAlgoDH).
+	Idempotent calls (e.g. Item.GetSecret, Item.SetSecret, Item.Refresh, Service.GetSecrets, Service.SearchItems)
+	that fail because the SecretService or Session went away are retried once after re-opening the Session.
+	Secret objects tied to the old Session are transparently sent/decoded with the new one.
+
+	Collection and Item objects obtained before a reconnect report Collection.Stale/Item.Stale.
+	handler, if not nil, is called (in its own goroutine) after each re-establishment attempt.
+*/
+func WithReconnect(handler ReconnectHandler) (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		opts.reconnect = true
+		opts.reconnectHandler = handler
+		return
+	}
+
+	return
+}
+
+// WithFlags applies ServiceInitFlag flags (e.g. FlagServiceLoadCollections) to NewService.
+func WithFlags(flags ...ServiceInitFlag) (opt Option) {
+
+	opt = func(opts *serviceOpts) (err error) {
+		opts.flags = append(opts.flags, flags...)
+		return
+	}
+
+	return
+}
+
+// hasFlag returns true if flag was specified via WithFlags.
+func (o *serviceOpts) hasFlag(flag ServiceInitFlag) (ok bool) {
+
+	for _, f := range o.flags {
+		if f == flag {
+			ok = true
+			return
+		}
+	}
+
+	return
+}
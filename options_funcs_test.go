@@ -0,0 +1,94 @@
+package gosecret
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	TestNewService_Options tests the following internal functions/methods via nested calls:
+
+		NewService
+			WithConn
+			WithBusAddress
+			WithDestination
+			WithLegacy
+			WithSessionAlgorithm
+			WithoutDefaultSession
+			WithParallelism
+			WithFlags
+		Service.Close
+
+*/
+func TestNewService_Options(t *testing.T) {
+
+	var svc *Service
+	var conn *dbus.Conn
+	var err error
+
+	// An existing connection is not closed by Service.Close.
+	if conn, err = dbus.ConnectSessionBus(); err != nil {
+		t.Fatalf("could not connect to session bus: %v", err.Error())
+	}
+	if svc, err = NewService(WithConn(conn), WithDestination(DbusService), WithLegacy()); err != nil {
+		t.Fatalf("NewService with WithConn failed: %v", err.Error())
+	}
+	if !svc.Legacy {
+		t.Errorf("WithLegacy did not set Service.Legacy")
+	}
+	if err = svc.Close(); err != nil {
+		t.Errorf("could not close Service: %v", err.Error())
+	}
+	if !conn.Connected() {
+		t.Errorf("Service.Close closed a connection passed via WithConn")
+	}
+	_ = conn.Close()
+
+	// A private connection (the default) is.
+	if svc, err = NewService(
+		WithBusAddress(os.Getenv("DBUS_SESSION_BUS_ADDRESS")),
+		WithSessionAlgorithm(SessionAlgoPlain),
+		WithFlags(FlagServiceLoadCollections),
+	); err != nil {
+		t.Fatalf("NewService with WithBusAddress failed: %v", err.Error())
+	}
+	if svc.Session == nil || svc.Session.Algorithm != SessionAlgoPlain {
+		t.Errorf("WithSessionAlgorithm(SessionAlgoPlain) did not open a plain Session: %#v", svc.Session)
+	}
+	if len(svc.LoadedCollections) == 0 {
+		t.Errorf("FlagServiceLoadCollections did not load any collections")
+	}
+	conn = svc.Conn
+	if err = svc.Close(); err != nil {
+		t.Errorf("could not close Service: %v", err.Error())
+	}
+	if conn.Connected() {
+		t.Errorf("Service.Close did not close its own connection")
+	}
+
+	if svc, err = NewService(WithoutDefaultSession(), WithParallelism(1)); err != nil {
+		t.Fatalf("NewService with WithoutDefaultSession failed: %v", err.Error())
+	}
+	if svc.Session != nil {
+		t.Errorf("WithoutDefaultSession opened a Session")
+	}
+	if svc.Parallelism != 1 {
+		t.Errorf("WithParallelism did not set Service.Parallelism")
+	}
+	if err = svc.Close(); err != nil {
+		t.Errorf("could not close Service: %v", err.Error())
+	}
+
+	if _, err = NewService(WithDestination("org.example.gosecret.NoSuchService")); err == nil {
+		t.Errorf("NewService succeeded for a nonexistent destination")
+	}
+	if _, err = NewService(WithSessionAlgorithm("rot13")); !errors.Is(err, ErrBadOption) {
+		t.Errorf("NewService with an unknown session algorithm returned '%v', expected ErrBadOption", err)
+	}
+	if _, err = NewService(WithParallelism(0)); !errors.Is(err, ErrBadOption) {
+		t.Errorf("NewService with WithParallelism(0) returned '%v', expected ErrBadOption", err)
+	}
+}
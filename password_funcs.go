@@ -0,0 +1,428 @@
+package gosecret
+
+import (
+	`context`
+
+	`r00t2.io/goutils/multierr`
+)
+
+/*
+	PasswordStore is a libsecret-style (secret_password_store) shorthand for storing a password:
+	it stores password (as ContentTypeText) in the Item labeled label in the Collection named collection
+	(a name, alias or label; CollectionDefault if empty), with attrs (validated against and converted per schema)
+	plus the SchemaNameAttr attribute.
+
+	An Item with the same attributes is replaced. The Collection is unlocked first if needed (which may Prompt).
+	attrs values must match their SchemaAttrType (e.g. an int for SchemaAttrInteger); otherwise err will be
+	ErrSchemaAttr or ErrSchemaAttrType.
+*/
+func (s *Service) PasswordStore(
+	schema *Schema, collection, label, password string, attrs map[string]interface{},
+) (err error) {
+
+	err = s.PasswordStoreContext(context.Background(), schema, collection, label, password, attrs)
+
+	return
+}
+
+// PasswordStoreContext is like Service.PasswordStore but uses ctx for the Dbus call(s).
+func (s *Service) PasswordStoreContext(
+	ctx context.Context, schema *Schema, collection, label, password string, attrs map[string]interface{},
+) (err error) {
+
+	err = s.passwordStore(ctx, schema, collection, label, []byte(password), ContentTypeText, attrs)
+
+	return
+}
+
+// PasswordStoreBinary is like Service.PasswordStore, but stores password as-is (as ContentTypeBinary).
+func (s *Service) PasswordStoreBinary(
+	schema *Schema, collection, label string, password []byte, attrs map[string]interface{},
+) (err error) {
+
+	err = s.PasswordStoreBinaryContext(context.Background(), schema, collection, label, password, attrs)
+
+	return
+}
+
+// PasswordStoreBinaryContext is like Service.PasswordStoreBinary but uses ctx for the Dbus call(s).
+func (s *Service) PasswordStoreBinaryContext(
+	ctx context.Context, schema *Schema, collection, label string, password []byte, attrs map[string]interface{},
+) (err error) {
+
+	err = s.passwordStore(ctx, schema, collection, label, append([]byte{}, password...), ContentTypeBinary, attrs)
+
+	return
+}
+
+/*
+	PasswordLookup is a libsecret-style (secret_password_lookup) shorthand for fetching a password:
+	it returns the Secret value of the first Item matching attrs (and, unless schema has FlagSchemaDontMatchName,
+	schema's SchemaNameAttr) as a string.
+
+	If only locked Items match, the first is unlocked (which may Prompt).
+	If no Item matches, err will be ErrDoesNotExist.
+*/
+func (s *Service) PasswordLookup(schema *Schema, attrs map[string]interface{}) (password string, err error) {
+
+	password, err = s.PasswordLookupContext(context.Background(), schema, attrs)
+
+	return
+}
+
+// PasswordLookupContext is like Service.PasswordLookup but uses ctx for the Dbus call(s).
+func (s *Service) PasswordLookupContext(
+	ctx context.Context, schema *Schema, attrs map[string]interface{},
+) (password string, err error) {
+
+	var value []byte
+
+	if value, err = s.passwordLookup(ctx, schema, attrs); err != nil {
+		return
+	}
+	defer wipeBytes(value)
+
+	password = string(value)
+
+	return
+}
+
+// PasswordLookupBinary is like Service.PasswordLookup, but returns the Secret value as-is.
+func (s *Service) PasswordLookupBinary(schema *Schema, attrs map[string]interface{}) (password []byte, err error) {
+
+	password, err = s.PasswordLookupBinaryContext(context.Background(), schema, attrs)
+
+	return
+}
+
+// PasswordLookupBinaryContext is like Service.PasswordLookupBinary but uses ctx for the Dbus call(s).
+func (s *Service) PasswordLookupBinaryContext(
+	ctx context.Context, schema *Schema, attrs map[string]interface{},
+) (password []byte, err error) {
+
+	password, err = s.passwordLookup(ctx, schema, attrs)
+
+	return
+}
+
+/*
+	PasswordClear is a libsecret-style (secret_password_clear) shorthand for deleting passwords:
+	it deletes every Item matching attrs (and, unless schema has FlagSchemaDontMatchName,
+	schema's SchemaNameAttr). removed is true if any Item was deleted.
+
+	err MAY be a *multierr.MultiError.
+*/
+func (s *Service) PasswordClear(schema *Schema, attrs map[string]interface{}) (removed bool, err error) {
+
+	removed, err = s.PasswordClearContext(context.Background(), schema, attrs)
+
+	return
+}
+
+// PasswordClearContext is like Service.PasswordClear but uses ctx for the Dbus call(s).
+func (s *Service) PasswordClearContext(
+	ctx context.Context, schema *Schema, attrs map[string]interface{},
+) (removed bool, err error) {
+
+	var e error
+	var match map[string]string
+	var unlocked []*Item
+	var locked []*Item
+	var errs *multierr.MultiError = multierr.NewMultiError()
+
+	if match, err = schema.attributes(attrs, schema.matchName()); err != nil {
+		return
+	}
+	if unlocked, locked, err = s.SearchItemsContext(ctx, match); err != nil {
+		return
+	}
+
+	for _, item := range append(unlocked, locked...) {
+		if e = item.DeleteContext(ctx); e != nil {
+			errs.AddError(e)
+			continue
+		}
+		removed = true
+	}
+
+	if !errs.IsEmpty() {
+		err = errs
+	}
+
+	return
+}
+
+/*
+	PasswordSearch is a libsecret-style (secret_password_search) search for Items matching attrs (and,
+	unless schema has FlagSchemaDontMatchName, schema's SchemaNameAttr).
+	Unlocked Items are returned before locked ones, each ordered by Dbus path.
+
+	By default at most one Item is returned, locked Items are left locked and no Secrets are fetched;
+	see FlagServiceSearchAll, FlagServiceSearchUnlock and FlagServiceSearchLoadSecrets.
+
+	(Unlike the other Password* methods, there is no package-level equivalent, as the Items need a live Service.)
+*/
+func (s *Service) PasswordSearch(
+	schema *Schema, attrs map[string]interface{}, flags ...ServiceSearchFlag,
+) (items []*Item, err error) {
+
+	items, err = s.PasswordSearchContext(context.Background(), schema, attrs, flags...)
+
+	return
+}
+
+// PasswordSearchContext is like Service.PasswordSearch but uses ctx for the Dbus call(s).
+func (s *Service) PasswordSearchContext(
+	ctx context.Context, schema *Schema, attrs map[string]interface{}, flags ...ServiceSearchFlag,
+) (items []*Item, err error) {
+
+	var match map[string]string
+	var unlocked []*Item
+	var locked []*Item
+	var toUnlock []LockableObject
+
+	if match, err = schema.attributes(attrs, schema.matchName()); err != nil {
+		return
+	}
+	if unlocked, locked, err = s.SearchItemsContext(ctx, match); err != nil {
+		return
+	}
+
+	items = append(unlocked, locked...)
+	if !hasSearchFlag(flags, FlagServiceSearchAll) && len(items) > 1 {
+		items = items[:1]
+	}
+
+	if hasSearchFlag(flags, FlagServiceSearchUnlock) {
+		for _, item := range items {
+			if item.Info().Locked {
+				toUnlock = append(toUnlock, item)
+			}
+		}
+		if len(toUnlock) > 0 {
+			if _, err = s.UnlockContext(ctx, toUnlock...); err != nil {
+				return
+			}
+		}
+	}
+
+	if hasSearchFlag(flags, FlagServiceSearchLoadSecrets) {
+		for _, item := range items {
+			if item.Info().Locked {
+				continue
+			}
+			if _, err = item.GetSecretContext(ctx, s.CurrentSession()); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// passwordStore implements Service.PasswordStoreContext and Service.PasswordStoreBinaryContext.
+func (s *Service) passwordStore(
+	ctx context.Context, schema *Schema, collection, label string, value []byte, contentType string,
+	attrs map[string]interface{},
+) (err error) {
+
+	var c *Collection
+	var ssn *Session
+	var stored map[string]string
+
+	if stored, err = schema.attributes(attrs, true); err != nil {
+		return
+	}
+	if ssn = s.CurrentSession(); ssn == nil {
+		err = ErrNoDbusConn
+		return
+	}
+
+	if collection == "" {
+		collection = CollectionDefault
+	}
+	if c, err = s.GetCollectionContext(ctx, collection); err != nil {
+		return
+	}
+	if err = c.UnlockContext(ctx); err != nil {
+		return
+	}
+
+	// Like libsecret, the Item's Type is the Schema name.
+	if _, err = c.CreateItemContext(
+		ctx, label, stored, NewSecret(ssn, []byte{}, value, contentType), true, schema.Name,
+	); err != nil {
+		return
+	}
+
+	return
+}
+
+/*
+	passwordLookup implements Service.PasswordLookupContext and Service.PasswordLookupBinaryContext.
+	value is a copy, which callers may wipe.
+*/
+func (s *Service) passwordLookup(
+	ctx context.Context, schema *Schema, attrs map[string]interface{},
+) (value []byte, err error) {
+
+	var item *Item
+	var match map[string]string
+	var unlocked []*Item
+	var locked []*Item
+
+	if match, err = schema.attributes(attrs, schema.matchName()); err != nil {
+		return
+	}
+	if unlocked, locked, err = s.SearchItemsContext(ctx, match); err != nil {
+		return
+	}
+
+	switch {
+	case len(unlocked) > 0:
+		item = unlocked[0]
+	case len(locked) > 0:
+		item = locked[0]
+		if _, err = s.UnlockContext(ctx, item); err != nil {
+			return
+		}
+	default:
+		err = ErrDoesNotExist
+		return
+	}
+
+	err = item.WithSecretContext(ctx, func(v SecretValue) (err error) {
+		value = append([]byte{}, v...)
+		return
+	})
+
+	return
+}
+
+/*
+	PasswordStore is like Service.PasswordStore, but uses a new Service (via NewService with its defaults)
+	for the duration of the call.
+*/
+func PasswordStore(schema *Schema, collection, label, password string, attrs map[string]interface{}) (err error) {
+
+	err = PasswordStoreContext(context.Background(), schema, collection, label, password, attrs)
+
+	return
+}
+
+// PasswordStoreContext is like PasswordStore but uses ctx for the Dbus call(s).
+func PasswordStoreContext(
+	ctx context.Context, schema *Schema, collection, label, password string, attrs map[string]interface{},
+) (err error) {
+
+	err = withDefaultService(ctx, func(s *Service) (err error) {
+		err = s.PasswordStoreContext(ctx, schema, collection, label, password, attrs)
+		return
+	})
+
+	return
+}
+
+// PasswordStoreBinary is like Service.PasswordStoreBinary, but uses a new Service for the duration of the call.
+func PasswordStoreBinary(
+	schema *Schema, collection, label string, password []byte, attrs map[string]interface{},
+) (err error) {
+
+	err = PasswordStoreBinaryContext(context.Background(), schema, collection, label, password, attrs)
+
+	return
+}
+
+// PasswordStoreBinaryContext is like PasswordStoreBinary but uses ctx for the Dbus call(s).
+func PasswordStoreBinaryContext(
+	ctx context.Context, schema *Schema, collection, label string, password []byte, attrs map[string]interface{},
+) (err error) {
+
+	err = withDefaultService(ctx, func(s *Service) (err error) {
+		err = s.PasswordStoreBinaryContext(ctx, schema, collection, label, password, attrs)
+		return
+	})
+
+	return
+}
+
+// PasswordLookup is like Service.PasswordLookup, but uses a new Service for the duration of the call.
+func PasswordLookup(schema *Schema, attrs map[string]interface{}) (password string, err error) {
+
+	password, err = PasswordLookupContext(context.Background(), schema, attrs)
+
+	return
+}
+
+// PasswordLookupContext is like PasswordLookup but uses ctx for the Dbus call(s).
+func PasswordLookupContext(
+	ctx context.Context, schema *Schema, attrs map[string]interface{},
+) (password string, err error) {
+
+	err = withDefaultService(ctx, func(s *Service) (err error) {
+		password, err = s.PasswordLookupContext(ctx, schema, attrs)
+		return
+	})
+
+	return
+}
+
+// PasswordLookupBinary is like Service.PasswordLookupBinary, but uses a new Service for the duration of the call.
+func PasswordLookupBinary(schema *Schema, attrs map[string]interface{}) (password []byte, err error) {
+
+	password, err = PasswordLookupBinaryContext(context.Background(), schema, attrs)
+
+	return
+}
+
+// PasswordLookupBinaryContext is like PasswordLookupBinary but uses ctx for the Dbus call(s).
+func PasswordLookupBinaryContext(
+	ctx context.Context, schema *Schema, attrs map[string]interface{},
+) (password []byte, err error) {
+
+	err = withDefaultService(ctx, func(s *Service) (err error) {
+		password, err = s.PasswordLookupBinaryContext(ctx, schema, attrs)
+		return
+	})
+
+	return
+}
+
+// PasswordClear is like Service.PasswordClear, but uses a new Service for the duration of the call.
+func PasswordClear(schema *Schema, attrs map[string]interface{}) (removed bool, err error) {
+
+	removed, err = PasswordClearContext(context.Background(), schema, attrs)
+
+	return
+}
+
+// PasswordClearContext is like PasswordClear but uses ctx for the Dbus call(s).
+func PasswordClearContext(
+	ctx context.Context, schema *Schema, attrs map[string]interface{},
+) (removed bool, err error) {
+
+	err = withDefaultService(ctx, func(s *Service) (err error) {
+		removed, err = s.PasswordClearContext(ctx, schema, attrs)
+		return
+	})
+
+	return
+}
+
+/*
+	withDefaultService calls fn with a new Service (via NewServiceContext with its defaults),
+	closing it afterwards.
+*/
+func withDefaultService(ctx context.Context, fn func(s *Service) (err error)) (err error) {
+
+	var svc *Service
+
+	if svc, err = NewServiceContext(ctx); err != nil {
+		return
+	}
+	defer svc.Close()
+
+	err = fn(svc)
+
+	return
+}
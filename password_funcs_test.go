@@ -0,0 +1,159 @@
+package gosecret
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// testSchema is the Schema used by TestService_Password.
+var testSchema *Schema = &Schema{
+	Name: "io.r00t2.gosecret.Test",
+	Attributes: map[string]SchemaAttrType{
+		"user":    SchemaAttrString,
+		"port":    SchemaAttrInteger,
+		"enabled": SchemaAttrBoolean,
+	},
+}
+
+/*
+	TestService_Password tests the following internal functions/methods via nested calls:
+
+		Service.PasswordStore
+		Service.PasswordStoreBinary
+			Service.passwordStore
+				Schema.attributes
+					schemaAttrValue
+		Service.PasswordLookup
+		Service.PasswordLookupBinary
+			Service.passwordLookup
+				Schema.matchName
+		Service.PasswordSearch
+			hasSearchFlag
+		Service.PasswordClear
+		PasswordStore
+		PasswordLookup
+		PasswordClear
+			withDefaultService
+
+*/
+func TestService_Password(t *testing.T) {
+
+	var err error
+	var svc *Service
+	var password string
+	var binary []byte
+	var removed bool
+	var items []*Item
+	var collection *Collection
+	var attrs map[string]interface{} = map[string]interface{}{"user": "alice", "port": 5432, "enabled": true}
+	var binAttrs map[string]interface{} = map[string]interface{}{"user": "bob", "port": uint16(22)}
+	var binValue []byte = []byte{0x00, 0xff, 0x10}
+
+	if svc, err = NewService(); err != nil {
+		t.Fatalf("NewService failed: %v", err.Error())
+	}
+	defer svc.Close()
+
+	// Schema validation.
+	if err = svc.PasswordStore(nil, "", testItemLabel, testSecretContent, attrs); !errors.Is(err, ErrMissingSchema) {
+		t.Errorf("PasswordStore without a schema returned '%v'; expected ErrMissingSchema", err)
+	}
+	if err = svc.PasswordStore(
+		testSchema, "", testItemLabel, testSecretContent, map[string]interface{}{"host": "db1"},
+	); !errors.Is(err, ErrSchemaAttr) {
+		t.Errorf("PasswordStore with an undefined attribute returned '%v'; expected ErrSchemaAttr", err)
+	}
+	if err = svc.PasswordStore(
+		testSchema, "", testItemLabel, testSecretContent, map[string]interface{}{"port": "5432"},
+	); !errors.Is(err, ErrSchemaAttrType) {
+		t.Errorf("PasswordStore with a mistyped attribute returned '%v'; expected ErrSchemaAttrType", err)
+	}
+
+	if err = svc.PasswordStore(testSchema, "", testItemLabel, testSecretContent, attrs); err != nil {
+		t.Fatalf("Service.PasswordStore failed: %v", err.Error())
+	}
+	// Replaces rather than duplicates.
+	if err = svc.PasswordStore(testSchema, "", testItemLabel, testSecretContent+" (2)", attrs); err != nil {
+		t.Fatalf("Service.PasswordStore (replace) failed: %v", err.Error())
+	}
+	if err = svc.PasswordStoreBinary(testSchema, defaultCollection, testItemLabel, binValue, binAttrs); err != nil {
+		t.Fatalf("Service.PasswordStoreBinary failed: %v", err.Error())
+	}
+
+	if password, err = svc.PasswordLookup(testSchema, map[string]interface{}{"user": "alice"}); err != nil {
+		t.Fatalf("Service.PasswordLookup failed: %v", err.Error())
+	}
+	if password != testSecretContent+" (2)" {
+		t.Errorf("Service.PasswordLookup returned '%v'", password)
+	}
+	if binary, err = svc.PasswordLookupBinary(testSchema, map[string]interface{}{"port": 22}); err != nil {
+		t.Fatalf("Service.PasswordLookupBinary failed: %v", err.Error())
+	}
+	if !bytes.Equal(binary, binValue) {
+		t.Errorf("Service.PasswordLookupBinary returned %v; expected %v", binary, binValue)
+	}
+
+	// The raw attributes carry the schema name and stringified values.
+	if items, err = svc.PasswordSearch(
+		testSchema, nil, FlagServiceSearchAll, FlagServiceSearchLoadSecrets,
+	); err != nil {
+		t.Fatalf("Service.PasswordSearch failed: %v", err.Error())
+	}
+	if len(items) != 2 {
+		t.Fatalf("Service.PasswordSearch returned %d items; expected 2", len(items))
+	}
+	for _, i := range items {
+		if i.Info().Attributes[SchemaNameAttr] != testSchema.Name || i.Info().Secret == nil {
+			t.Errorf("unexpected Item from Service.PasswordSearch: %v", i)
+		}
+		if i.Info().Attributes["user"] == "alice" &&
+			(i.Info().Attributes["port"] != "5432" || i.Info().Attributes["enabled"] != "true") {
+			t.Errorf("unexpected attributes from Service.PasswordSearch: %v", i.Info().Attributes)
+		}
+	}
+	if items, err = svc.PasswordSearch(testSchema, nil); err != nil || len(items) != 1 {
+		t.Errorf("Service.PasswordSearch without FlagServiceSearchAll returned %d items (err: %v)", len(items), err)
+	}
+	if items, err = svc.PasswordSearch(&Schema{Name: "io.r00t2.gosecret.Other"}, nil); err != nil || len(items) != 0 {
+		t.Errorf("Service.PasswordSearch with another schema returned %d items (err: %v)", len(items), err)
+	}
+	if items, err = svc.PasswordSearch(
+		&Schema{Name: "io.r00t2.gosecret.Other", Flags: FlagSchemaDontMatchName, Attributes: testSchema.Attributes},
+		map[string]interface{}{"user": "bob"},
+	); err != nil || len(items) != 1 {
+		t.Errorf("Service.PasswordSearch with FlagSchemaDontMatchName returned %d items (err: %v)", len(items), err)
+	}
+
+	// A locked collection is unlocked for the lookup.
+	if collection, err = svc.GetCollection(defaultCollection); err != nil {
+		t.Fatalf("failed when fetching collection '%v': %v", defaultCollection, err.Error())
+	}
+	if err = collection.Lock(); err != nil {
+		t.Fatalf("could not lock collection: %v", err.Error())
+	}
+	if password, err = svc.PasswordLookup(testSchema, map[string]interface{}{"user": "alice"}); err != nil {
+		t.Errorf("Service.PasswordLookup of a locked Item failed: %v", err.Error())
+	}
+
+	// Package-level (one-shot) functions.
+	if err = PasswordStore(testSchema, CollectionDefault, testItemLabel, "one-shot", attrs); err != nil {
+		t.Fatalf("PasswordStore failed: %v", err.Error())
+	}
+	if password, err = PasswordLookup(testSchema, attrs); err != nil || password != "one-shot" {
+		t.Errorf("PasswordLookup returned '%v' (err: %v)", password, err)
+	}
+
+	if removed, err = PasswordClear(testSchema, attrs); err != nil || !removed {
+		t.Errorf("PasswordClear returned %v (err: %v)", removed, err)
+	}
+	if removed, err = svc.PasswordClear(testSchema, nil); err != nil || !removed {
+		t.Errorf("Service.PasswordClear returned %v (err: %v)", removed, err)
+	}
+	if removed, err = svc.PasswordClear(testSchema, nil); err != nil || removed {
+		t.Errorf("Service.PasswordClear of nothing returned %v (err: %v)", removed, err)
+	}
+	if _, err = svc.PasswordLookup(testSchema, attrs); !errors.Is(err, ErrDoesNotExist) {
+		t.Errorf("Service.PasswordLookup of a cleared password returned '%v'; expected ErrDoesNotExist", err)
+	}
+}
@@ -0,0 +1,93 @@
+package portal
+
+import (
+	"time"
+)
+
+// Dbus names and paths of the portal.
+const (
+	// DbusService is the bus name of xdg-desktop-portal.
+	DbusService string = "org.freedesktop.portal.Desktop"
+	// DbusPath is the path of the portal object.
+	DbusPath string = "/org/freedesktop/portal/desktop"
+	/*
+		DbusRequestPathPrefix is the prefix of Request object paths; it is followed by the caller's unique bus name
+		(without the leading ":" and with "." replaced by "_"), a "/" and the handle token.
+	*/
+	DbusRequestPathPrefix string = DbusPath + "/request/"
+)
+
+// Secret portal interface.
+const (
+	// DbusInterfaceSecret is the Secret portal interface.
+	DbusInterfaceSecret string = "org.freedesktop.portal.Secret"
+
+	// DbusSecretRetrieveSecret writes the application's secret to a file descriptor.
+	DbusSecretRetrieveSecret string = DbusInterfaceSecret + ".RetrieveSecret"
+
+	// DbusSecretVersionProperty is the property holding the version of the Secret portal interface.
+	DbusSecretVersionProperty string = "version"
+)
+
+// Request interface.
+const (
+	// DbusInterfaceRequest is the interface of the Request objects returned by portal methods.
+	DbusInterfaceRequest string = "org.freedesktop.portal.Request"
+
+	// DbusRequestClose closes (cancels) a Request.
+	DbusRequestClose string = DbusInterfaceRequest + ".Close"
+
+	// DbusRequestResponseMember is the signal (member) name a Request emits once it is done.
+	DbusRequestResponseMember string = "Response"
+
+	// DbusRequestResponse is the full name of the Response signal.
+	DbusRequestResponse string = DbusInterfaceRequest + "." + DbusRequestResponseMember
+)
+
+// Request responses (the first argument of the Response signal).
+const (
+	// ResponseSuccess indicates the request succeeded.
+	ResponseSuccess uint32 = iota
+	// ResponseCancelled indicates the user cancelled the request.
+	ResponseCancelled
+	// ResponseOther indicates the request failed in some other way.
+	ResponseOther
+)
+
+const (
+	// MasterKeySize is the size (in bytes) of the key returned by Portal.MasterKey (suitable for AES-256).
+	MasterKeySize int = 32
+	// MaxSecretSize is the maximum size of a secret read from the portal.
+	MaxSecretSize int64 = 64 * 1024
+	// handleTokenPrefix prefixes the (random) handle tokens of our Requests.
+	handleTokenPrefix string = "gosecret"
+	// flatpakInfo exists in the root of every Flatpak sandbox.
+	flatpakInfo string = "/.flatpak-info"
+	// masterKeyInfo prefixes the HKDF info for Portal.MasterKey, so its keys differ from any derived by DeriveKey.
+	masterKeyInfo string = "gosecret portal master key\x00"
+	// closeTimeout bounds the Request.Close sent once the caller's context is done (see Portal.closeRequest).
+	closeTimeout time.Duration = 3 * time.Second
+	/*
+		readTimeout bounds reading the secret once the portal has responded successfully (by which time it has
+		written it); see ErrReadTimeout.
+	*/
+	readTimeout time.Duration = 3 * time.Second
+)
+
+// dbusPropertiesGet is the standard Dbus method for reading a property.
+const dbusPropertiesGet string = "org.freedesktop.DBus.Properties.Get"
+
+// Message bus names, for looking up the portal's unique name.
+const (
+	dbusBusName      string = "org.freedesktop.DBus"
+	dbusBusPath      string = "/org/freedesktop/DBus"
+	dbusGetNameOwner string = dbusBusName + ".GetNameOwner"
+)
+
+// Dbus errors returned if the portal (or its Secret interface) is missing.
+const (
+	dbusErrServiceUnknown   string = "org.freedesktop.DBus.Error.ServiceUnknown"
+	dbusErrUnknownMethod    string = "org.freedesktop.DBus.Error.UnknownMethod"
+	dbusErrUnknownInterface string = "org.freedesktop.DBus.Error.UnknownInterface"
+	dbusErrUnknownObject    string = "org.freedesktop.DBus.Error.UnknownObject"
+)
@@ -0,0 +1,28 @@
+// See LICENSE in source root directory for copyright and licensing information.
+
+/*
+Package portal is a client for the xdg-desktop-portal Secret portal (org.freedesktop.portal.Secret).
+
+Sandboxed (e.g. Flatpak) applications usually cannot talk to org.freedesktop.secrets directly. Instead, the
+Secret portal hands each application a per-application secret (stored by the host's SecretService on its behalf),
+from which the application derives the key(s) for its own, local, encrypted store:
+
+	var p *portal.Portal
+	var key []byte
+	var err error
+
+	if p, err = portal.New(nil); err != nil {
+		log.Fatal(err)
+	}
+	defer p.Close()
+
+	if key, err = p.MasterKey("com.example.App store v1"); err != nil {
+		log.Fatal(err)
+	}
+	// Use key (e.g. with AES-GCM) to encrypt/decrypt the application's local store.
+
+The secret is always the same for the same application, so the derived keys are stable across runs.
+Use InSandbox to decide whether to use the portal or gosecret.NewService, or simply fall back to the
+portal if NewService fails.
+*/
+package portal
@@ -0,0 +1,29 @@
+package portal
+
+import (
+	"errors"
+)
+
+var (
+	// ErrUnsupported gets triggered if xdg-desktop-portal is not running or has no Secret portal.
+	ErrUnsupported error = errors.New("the Secret portal is not available")
+	// ErrNoFDPassing gets triggered if the Dbus connection cannot pass file descriptors (which the portal requires).
+	ErrNoFDPassing error = errors.New("the Dbus connection does not support passing file descriptors")
+	// ErrCancelled gets triggered if the user cancelled the request.
+	ErrCancelled error = errors.New("the portal request was cancelled")
+	// ErrFailed gets triggered if the portal reported that the request failed.
+	ErrFailed error = errors.New("the portal request failed")
+	// ErrEmptySecret gets triggered if the portal succeeded but did not write a secret.
+	ErrEmptySecret error = errors.New("the portal returned an empty secret")
+	// ErrSecretTooLarge gets triggered if the portal wrote more than MaxSecretSize bytes.
+	ErrSecretTooLarge error = errors.New("the portal returned a secret larger than MaxSecretSize")
+	/*
+		ErrReadTimeout gets triggered if the portal responded successfully but did not close its end of the pipe
+		(see Portal.RetrieveSecret) in time.
+	*/
+	ErrReadTimeout error = errors.New("timed out reading the secret from the portal")
+	// ErrProto gets triggered if the portal's Response signal is malformed.
+	ErrProto error = errors.New("malformed response from the portal")
+	// ErrKeySize gets triggered if DeriveKey is asked for an invalid key size.
+	ErrKeySize error = errors.New("invalid key size")
+)
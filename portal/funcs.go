@@ -0,0 +1,424 @@
+package portal
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"golang.org/x/crypto/hkdf"
+)
+
+/*
+	New returns a Portal using conn.
+	If conn is nil, a new private connection to the session bus is opened (and closed by Portal.Close).
+
+	New does not contact the portal; use Portal.Version to check that it is available.
+*/
+func New(conn *dbus.Conn) (portal *Portal, err error) {
+
+	var p Portal = Portal{
+		Conn: conn,
+	}
+
+	if p.Conn == nil {
+		if p.Conn, err = dbus.ConnectSessionBus(); err != nil {
+			return
+		}
+		p.ownsConn = true
+	}
+	p.Dbus = p.Conn.Object(DbusService, dbus.ObjectPath(DbusPath))
+
+	portal = &p
+
+	return
+}
+
+// Close closes the Dbus connection, if it was opened by New.
+func (p *Portal) Close() (err error) {
+
+	if p.ownsConn {
+		err = p.Conn.Close()
+	}
+
+	return
+}
+
+/*
+	Version returns the version of the Secret portal interface.
+	If the portal is not available, err will be ErrUnsupported.
+*/
+func (p *Portal) Version() (version uint32, err error) {
+
+	version, err = p.VersionContext(context.Background())
+
+	return
+}
+
+// VersionContext is like Portal.Version but uses ctx for the Dbus call.
+func (p *Portal) VersionContext(ctx context.Context) (version uint32, err error) {
+
+	var v dbus.Variant
+	var ok bool
+
+	if err = p.Dbus.CallWithContext(
+		ctx, dbusPropertiesGet, 0, DbusInterfaceSecret, DbusSecretVersionProperty,
+	).Store(&v); err != nil {
+		err = translateErr(err)
+		return
+	}
+	if version, ok = v.Value().(uint32); !ok {
+		err = ErrProto
+		return
+	}
+
+	return
+}
+
+/*
+	RetrieveSecret retrieves the application's secret from the portal.
+	The portal identifies the application itself (e.g. from its Flatpak ID), so every call returns the same secret.
+
+	Callers should wipe secret when done with it; Portal.MasterKey does so itself.
+
+	If the user cancels the request, err will be ErrCancelled.
+	If the portal is not available, err will be ErrUnsupported.
+	If the portal responds but does not finish writing the secret within a few seconds, err will be ErrReadTimeout.
+*/
+func (p *Portal) RetrieveSecret() (secret []byte, err error) {
+
+	secret, err = p.RetrieveSecretContext(context.Background())
+
+	return
+}
+
+/*
+	RetrieveSecretContext is like Portal.RetrieveSecret, but stops waiting for the portal if ctx is cancelled.
+	If ctx is cancelled, the Request is closed and err will be ctx.Err().
+*/
+func (p *Portal) RetrieveSecretContext(ctx context.Context) (secret []byte, err error) {
+
+	var token string
+	var owner string
+	var expected dbus.ObjectPath
+	var handle dbus.ObjectPath
+	var r *os.File
+	var w *os.File
+	var response uint32
+	var res readResult
+	var timer *time.Timer
+	var c chan *dbus.Signal
+	var readChan chan readResult = make(chan readResult, 1)
+	var matchOpts []dbus.MatchOption = []dbus.MatchOption{
+		dbus.WithMatchSender(DbusService),
+		dbus.WithMatchInterface(DbusInterfaceRequest),
+		dbus.WithMatchMember(DbusRequestResponseMember),
+	}
+
+	if !p.Conn.SupportsUnixFDs() {
+		err = ErrNoFDPassing
+		return
+	}
+
+	if token, err = newToken(); err != nil {
+		return
+	}
+	if expected, err = p.requestPath(token); err != nil {
+		return
+	}
+
+	/*
+		Subscribe before calling, as the Response may be emitted before the call returns.
+		The match isn't restricted to the expected path, as older portals may return a different handle.
+	*/
+	if err = p.Conn.AddMatchSignalContext(ctx, matchOpts...); err != nil {
+		return
+	}
+	defer p.Conn.RemoveMatchSignal(matchOpts...)
+
+	// c is never closed by us; see gosecret's Prompt.PromptContext.
+	c = make(chan *dbus.Signal, 10)
+
+	p.Conn.Signal(c)
+	defer p.Conn.RemoveSignal(c)
+
+	if r, w, err = os.Pipe(); err != nil {
+		return
+	}
+	// Closing r also stops the reader goroutine, if the portal never closes its end.
+	defer r.Close()
+
+	go func() {
+		var rr readResult
+		rr.data, rr.err = io.ReadAll(io.LimitReader(r, MaxSecretSize+1))
+		readChan <- rr
+	}()
+
+	err = p.Dbus.CallWithContext(
+		ctx, DbusSecretRetrieveSecret, 0,
+		dbus.UnixFD(w.Fd()), map[string]dbus.Variant{"handle_token": dbus.MakeVariant(token)},
+	).Store(&handle)
+	// The portal has its own copy of the write end (if the call got that far); ours must be closed to see EOF.
+	_ = w.Close()
+	if err != nil {
+		err = translateErr(err)
+		return
+	}
+
+	/*
+		Looked up only now (rather than before subscribing) so that the portal may still be started by the call above;
+		any Response received in the meantime is held in c.
+	*/
+	if err = p.Conn.BusObject().CallWithContext(ctx, dbusGetNameOwner, 0, DbusService).Store(&owner); err != nil {
+		err = translateErr(err)
+		return
+	}
+
+	if response, err = p.waitResponse(ctx, c, owner, handle, expected); err != nil {
+		return
+	}
+
+	switch response {
+	case ResponseSuccess:
+	case ResponseCancelled:
+		err = ErrCancelled
+		return
+	default:
+		err = ErrFailed
+		return
+	}
+
+	/*
+		Callers without a deadline (e.g. Portal.RetrieveSecret) would otherwise wait forever on a portal that never
+		closes its copy of the write end.
+	*/
+	timer = time.NewTimer(readTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+		return
+	case <-timer.C:
+		err = ErrReadTimeout
+		return
+	case res = <-readChan:
+	}
+
+	if res.err != nil {
+		err = res.err
+		return
+	}
+	switch {
+	case len(res.data) == 0:
+		err = ErrEmptySecret
+		return
+	case int64(len(res.data)) > MaxSecretSize:
+		wipe(res.data)
+		err = ErrSecretTooLarge
+		return
+	}
+
+	secret = res.data
+
+	return
+}
+
+/*
+	MasterKey retrieves the application's secret (see Portal.RetrieveSecret) and derives a MasterKeySize-byte key
+	from it via DeriveKey. info identifies the key's purpose (e.g. "com.example.App store v1"), so that
+	different purposes get independent keys.
+
+	The secret itself is wiped before returning.
+*/
+func (p *Portal) MasterKey(info string) (key []byte, err error) {
+
+	key, err = p.MasterKeyContext(context.Background(), info)
+
+	return
+}
+
+// MasterKeyContext is like Portal.MasterKey but uses ctx for the Dbus call(s) (see Portal.RetrieveSecretContext).
+func (p *Portal) MasterKeyContext(ctx context.Context, info string) (key []byte, err error) {
+
+	var secret []byte
+
+	if secret, err = p.RetrieveSecretContext(ctx); err != nil {
+		return
+	}
+	defer wipe(secret)
+
+	key, err = DeriveKey(secret, masterKeyInfo+info, MasterKeySize)
+
+	return
+}
+
+/*
+	DeriveKey derives a size-byte key from secret (e.g. as returned by Portal.RetrieveSecret) and info
+	via HKDF-SHA256 (RFC 5869). The same secret and info always produce the same key.
+
+	If size is not between 1 and 255*32, err will be ErrKeySize.
+*/
+func DeriveKey(secret []byte, info string, size int) (key []byte, err error) {
+
+	if size < 1 || size > 255*sha256.Size {
+		err = ErrKeySize
+		return
+	}
+
+	key = make([]byte, size)
+
+	if _, err = io.ReadFull(hkdf.New(sha256.New, secret, nil, []byte(info)), key); err != nil {
+		key = nil
+		return
+	}
+
+	return
+}
+
+/*
+	InSandbox returns true if the process runs in a Flatpak sandbox (where the SecretService is usually not
+	reachable and the portal should be used instead).
+*/
+func InSandbox() (sandboxed bool) {
+
+	var err error
+
+	if _, err = os.Stat(flatpakInfo); err == nil {
+		sandboxed = true
+	}
+
+	return
+}
+
+/*
+	waitResponse waits for the Response signal of the Request at handle (or expected) on c.
+	Signals not sent by owner (the portal's unique bus name) are ignored, as any peer could emit them.
+	If ctx is cancelled, the Request is closed (see Portal.closeRequest) and err will be ctx.Err().
+*/
+func (p *Portal) waitResponse(
+	ctx context.Context, c chan *dbus.Signal, owner string, handle, expected dbus.ObjectPath,
+) (response uint32, err error) {
+
+	var ok bool
+	var sig *dbus.Signal
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.closeRequest(handle)
+			err = ctx.Err()
+			return
+		case sig, ok = <-c:
+			if !ok {
+				err = dbus.ErrClosed
+				return
+			}
+			if sig.Sender != owner || (sig.Path != handle && sig.Path != expected) || sig.Name != DbusRequestResponse {
+				continue
+			}
+			response, err = parseResponse(sig)
+			return
+		}
+	}
+}
+
+/*
+	closeRequest asks the portal to close the Request at handle after the caller gave up on it.
+	The caller's context is done by then, so the call gets its own (closeTimeout) deadline instead;
+	an unresponsive portal must not keep the caller from returning.
+*/
+func (p *Portal) closeRequest(handle dbus.ObjectPath) {
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	ctx, cancel = context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	_ = p.Conn.Object(DbusService, handle).CallWithContext(ctx, DbusRequestClose, 0).Store()
+
+	return
+}
+
+// requestPath returns the path the portal will use for a Request with the given handle token.
+func (p *Portal) requestPath(token string) (path dbus.ObjectPath, err error) {
+
+	var names []string = p.Conn.Names()
+	var sender string
+
+	if len(names) == 0 {
+		err = dbus.ErrClosed
+		return
+	}
+
+	sender = strings.ReplaceAll(strings.TrimPrefix(names[0], ":"), ".", "_")
+	path = dbus.ObjectPath(DbusRequestPathPrefix + sender + "/" + token)
+
+	return
+}
+
+// newToken returns a new random handle token.
+func newToken() (token string, err error) {
+
+	var b []byte = make([]byte, 8)
+
+	if _, err = rand.Read(b); err != nil {
+		return
+	}
+
+	token = handleTokenPrefix + hex.EncodeToString(b)
+
+	return
+}
+
+/*
+	parseResponse parses the body of a Request's Response signal (signature "ua{sv}").
+	If the body is malformed, err will be ErrProto.
+*/
+func parseResponse(sig *dbus.Signal) (response uint32, err error) {
+
+	var ok bool
+
+	if len(sig.Body) < 1 {
+		err = ErrProto
+		return
+	}
+	if response, ok = sig.Body[0].(uint32); !ok {
+		err = ErrProto
+		return
+	}
+
+	return
+}
+
+// translateErr returns ErrUnsupported if err indicates the portal (or its Secret interface) is missing.
+func translateErr(err error) (translated error) {
+
+	var dbusErr dbus.Error
+
+	translated = err
+
+	if errors.As(err, &dbusErr) {
+		switch dbusErr.Name {
+		case dbusErrServiceUnknown, dbusErrUnknownMethod, dbusErrUnknownInterface, dbusErrUnknownObject:
+			translated = ErrUnsupported
+		}
+	}
+
+	return
+}
+
+// wipe zeroes b.
+func wipe(b []byte) {
+
+	for i := range b {
+		b[i] = 0
+	}
+}
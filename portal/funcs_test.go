@@ -0,0 +1,307 @@
+package portal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+	"r00t2.io/gosecret/internal/testbus"
+)
+
+// testSecret is the application secret handed out by fakePortal.
+var testSecret []byte = []byte("0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef")
+
+// fakePortal is a minimal Secret portal, handing out testSecret (or responding with response).
+type fakePortal struct {
+	conn     *dbus.Conn
+	lock     sync.Mutex
+	response uint32
+	// hang, if true, never responds (to test cancellation); closed records the Requests closed since.
+	hang   bool
+	closed []dbus.ObjectPath
+	// requests, if non-nil, receives the handle of each Request left hanging.
+	requests chan dbus.ObjectPath
+	// closeRelease, if non-nil, makes Close block until it is closed (to test an unresponsive portal).
+	closeRelease chan struct{}
+	// pipeRelease, if non-nil, keeps the secret's file descriptor open (after responding) until it is closed.
+	pipeRelease chan struct{}
+}
+
+// RetrieveSecret implements org.freedesktop.portal.Secret.RetrieveSecret.
+func (f *fakePortal) RetrieveSecret(
+	sender dbus.Sender, fd dbus.UnixFD, opts map[string]dbus.Variant,
+) (handle dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var token string
+	var response uint32
+	var hang bool
+	var requests chan dbus.ObjectPath
+	var pipeRelease chan struct{}
+	var file *os.File = os.NewFile(uintptr(fd), "secret")
+
+	f.lock.Lock()
+	response = f.response
+	hang = f.hang
+	requests = f.requests
+	pipeRelease = f.pipeRelease
+	f.lock.Unlock()
+
+	_ = opts["handle_token"].Store(&token)
+	handle = dbus.ObjectPath(
+		DbusRequestPathPrefix + strings.ReplaceAll(strings.TrimPrefix(string(sender), ":"), ".", "_") + "/" + token,
+	)
+
+	if hang {
+		_ = f.conn.Export(f, handle, DbusInterfaceRequest)
+		_ = file.Close()
+		if requests != nil {
+			requests <- handle
+		}
+		return
+	}
+
+	if response == ResponseSuccess {
+		_, _ = file.Write(testSecret)
+	}
+	if pipeRelease != nil {
+		go func() {
+			<-pipeRelease
+			_ = file.Close()
+		}()
+	} else {
+		_ = file.Close()
+	}
+
+	go func() {
+		_ = f.conn.Emit(handle, DbusRequestResponse, response, map[string]dbus.Variant{})
+	}()
+
+	return
+}
+
+// Close implements org.freedesktop.portal.Request.Close.
+func (f *fakePortal) Close(msg dbus.Message) (dbusErr *dbus.Error) {
+
+	var path dbus.ObjectPath
+	var release chan struct{}
+
+	_ = msg.Headers[dbus.FieldPath].Store(&path)
+
+	f.lock.Lock()
+	f.closed = append(f.closed, path)
+	release = f.closeRelease
+	f.lock.Unlock()
+
+	if release != nil {
+		<-release
+	}
+
+	return
+}
+
+/*
+	TestPortal tests the following internal functions/methods via nested calls:
+
+		New
+		Portal.Version
+		Portal.RetrieveSecret
+			newToken
+			Portal.requestPath
+			Portal.waitResponse
+				parseResponse
+				Portal.closeRequest
+		Portal.MasterKey
+			DeriveKey
+		Portal.Close
+
+	It starts a private session bus (skipping the test if dbus-daemon is not installed) running a fake portal.
+*/
+func TestPortal(t *testing.T) {
+
+	var err error
+	var bus *testbus.Bus
+	var srvConn *dbus.Conn
+	var conn *dbus.Conn
+	var p *Portal
+	var fake *fakePortal
+	var version uint32
+	var secret []byte
+	var key []byte
+	var other []byte
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var spoofer *dbus.Conn
+	var requests chan dbus.ObjectPath
+	var start time.Time
+	var release chan struct{} = make(chan struct{})
+	var pipeRelease chan struct{} = make(chan struct{})
+
+	if bus, err = testbus.Start(); err != nil {
+		if errors.Is(err, testbus.ErrNoDaemon) {
+			t.Skip(err.Error())
+		}
+		t.Fatalf("could not start private bus: %v", err.Error())
+	}
+	defer bus.Close()
+
+	if conn, err = dbus.Connect(bus.Address); err != nil {
+		t.Fatalf("could not connect to private bus: %v", err.Error())
+	}
+	if p, err = New(conn); err != nil {
+		t.Fatalf("New failed: %v", err.Error())
+	}
+	defer p.Close()
+	defer conn.Close()
+
+	// No portal yet.
+	if _, err = p.Version(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Portal.Version without a portal returned '%v'; expected ErrUnsupported", err)
+	}
+	if _, err = p.RetrieveSecret(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Portal.RetrieveSecret without a portal returned '%v'; expected ErrUnsupported", err)
+	}
+
+	if srvConn, err = dbus.Connect(bus.Address); err != nil {
+		t.Fatalf("could not connect to private bus: %v", err.Error())
+	}
+	defer srvConn.Close()
+	fake = &fakePortal{conn: srvConn}
+	if err = srvConn.Export(fake, dbus.ObjectPath(DbusPath), DbusInterfaceSecret); err != nil {
+		t.Fatalf("could not export fake portal: %v", err.Error())
+	}
+	if _, err = prop.Export(srvConn, dbus.ObjectPath(DbusPath), map[string]map[string]*prop.Prop{
+		DbusInterfaceSecret: {DbusSecretVersionProperty: {Value: uint32(1)}},
+	}); err != nil {
+		t.Fatalf("could not export fake portal properties: %v", err.Error())
+	}
+	if _, err = srvConn.RequestName(DbusService, dbus.NameFlagDoNotQueue); err != nil {
+		t.Fatalf("could not request portal name: %v", err.Error())
+	}
+
+	if version, err = p.Version(); err != nil {
+		t.Errorf("Portal.Version failed: %v", err.Error())
+	} else if version != 1 {
+		t.Errorf("Portal.Version returned %v; expected 1", version)
+	}
+
+	if secret, err = p.RetrieveSecret(); err != nil {
+		t.Fatalf("Portal.RetrieveSecret failed: %v", err.Error())
+	}
+	if !bytes.Equal(secret, testSecret) {
+		t.Errorf("Portal.RetrieveSecret returned '%s'; expected '%s'", secret, testSecret)
+	}
+
+	if key, err = p.MasterKey("test store"); err != nil {
+		t.Fatalf("Portal.MasterKey failed: %v", err.Error())
+	}
+	if len(key) != MasterKeySize {
+		t.Errorf("Portal.MasterKey returned a %d-byte key; expected %d", len(key), MasterKeySize)
+	}
+	if other, err = p.MasterKey("test store"); err != nil || !bytes.Equal(key, other) {
+		t.Errorf("Portal.MasterKey is not stable (err: %v)", err)
+	}
+	if other, err = p.MasterKey("other store"); err != nil || bytes.Equal(key, other) {
+		t.Errorf("Portal.MasterKey returned the same key for different info (err: %v)", err)
+	}
+	if other, err = DeriveKey(testSecret, "test store", MasterKeySize); err != nil || bytes.Equal(key, other) {
+		t.Errorf("DeriveKey returned Portal.MasterKey's key (err: %v)", err)
+	}
+	if _, err = DeriveKey(testSecret, "", 0); !errors.Is(err, ErrKeySize) {
+		t.Errorf("DeriveKey with size 0 returned '%v'; expected ErrKeySize", err)
+	}
+
+	// The portal responds but never closes its end of the pipe.
+	fake.lock.Lock()
+	fake.pipeRelease = pipeRelease
+	fake.lock.Unlock()
+	start = time.Now()
+	if _, err = p.RetrieveSecret(); !errors.Is(err, ErrReadTimeout) {
+		t.Errorf("Portal.RetrieveSecret with a pipe left open returned '%v'; expected ErrReadTimeout", err)
+	}
+	if time.Since(start) > readTimeout+time.Second {
+		t.Errorf("Portal.RetrieveSecret took %v to return with a pipe left open", time.Since(start))
+	}
+	close(pipeRelease)
+	fake.lock.Lock()
+	fake.pipeRelease = nil
+	fake.lock.Unlock()
+
+	// Cancelled by the user.
+	fake.lock.Lock()
+	fake.response = ResponseCancelled
+	fake.lock.Unlock()
+	if _, err = p.RetrieveSecret(); !errors.Is(err, ErrCancelled) {
+		t.Errorf("Portal.RetrieveSecret returned '%v'; expected ErrCancelled", err)
+	}
+
+	// Failed.
+	fake.lock.Lock()
+	fake.response = ResponseOther
+	fake.lock.Unlock()
+	if _, err = p.RetrieveSecret(); !errors.Is(err, ErrFailed) {
+		t.Errorf("Portal.RetrieveSecret returned '%v'; expected ErrFailed", err)
+	}
+
+	// Cancelled by the caller; the Request should be closed.
+	fake.lock.Lock()
+	fake.hang = true
+	fake.lock.Unlock()
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_, err = p.RetrieveSecretContext(ctx)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Portal.RetrieveSecretContext returned '%v'; expected context.DeadlineExceeded", err)
+	}
+	fake.lock.Lock()
+	if len(fake.closed) != 1 {
+		t.Errorf("Portal.RetrieveSecretContext closed %d Requests; expected 1", len(fake.closed))
+	}
+	fake.lock.Unlock()
+
+	// A Response from a peer other than the portal is ignored.
+	if spoofer, err = dbus.Connect(bus.Address); err != nil {
+		t.Fatalf("could not connect to private bus: %v", err.Error())
+	}
+	defer spoofer.Close()
+	requests = make(chan dbus.ObjectPath, 1)
+	fake.lock.Lock()
+	fake.requests = requests
+	fake.lock.Unlock()
+	go func() {
+		select {
+		case handle := <-requests:
+			_ = spoofer.Emit(handle, DbusRequestResponse, ResponseSuccess, map[string]dbus.Variant{})
+		case <-time.After(time.Second):
+		}
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	_, err = p.RetrieveSecretContext(ctx)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Portal.RetrieveSecretContext accepted a Response from another peer (returned '%v')", err)
+	}
+
+	// An unresponsive portal does not keep a cancelled caller waiting (beyond closeTimeout).
+	defer close(release)
+	fake.lock.Lock()
+	fake.requests = nil
+	fake.closeRelease = release
+	fake.lock.Unlock()
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	start = time.Now()
+	_, err = p.RetrieveSecretContext(ctx)
+	cancel()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Portal.RetrieveSecretContext returned '%v'; expected context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > 100*time.Millisecond+closeTimeout+time.Second {
+		t.Errorf("Portal.RetrieveSecretContext took %v to return with an unresponsive portal", time.Since(start))
+	}
+}
@@ -0,0 +1,21 @@
+package portal
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// Portal is a connection to the xdg-desktop-portal Secret portal.
+type Portal struct {
+	// Conn is the Dbus connection used.
+	Conn *dbus.Conn
+	// Dbus is the portal object (DbusService at DbusPath).
+	Dbus dbus.BusObject
+	// ownsConn is true if Conn was opened by New (and so is closed by Portal.Close).
+	ownsConn bool
+}
+
+// readResult is the outcome of reading the secret from the pipe.
+type readResult struct {
+	data []byte
+	err  error
+}
@@ -1,6 +1,9 @@
 package gosecret
 
 import (
+	`context`
+	`fmt`
+
 	`github.com/godbus/dbus/v5`
 )
 
@@ -17,31 +20,196 @@ func NewPrompt(conn *dbus.Conn, path dbus.ObjectPath) (prompt *Prompt) {
 	return
 }
 
-// Prompt issues/waits for a prompt for unlocking a Locked Collection or Secret / Item.
+/*
+	X11WindowID returns a window ID suitable for Prompt.WindowID/Service.WindowID from an X11 window ID (XID).
+
+	The format is that of the xdg-desktop-portal "Window Identifiers" convention ("x11:<XID in hex>").
+	Note that some (older) prompters instead expect the bare XID in decimal;
+	in that case, use e.g. strconv.FormatUint(uint64(xid), 10) instead.
+*/
+func X11WindowID(xid uint32) (windowID string) {
+
+	windowID = fmt.Sprintf("x11:%x", xid)
+
+	return
+}
+
+/*
+	WaylandWindowID returns a window ID suitable for Prompt.WindowID/Service.WindowID from a Wayland
+	xdg-foreign (zxdg_exporter_v2/zxdg_exported_v2) exported surface handle.
+
+	The format is that of the xdg-desktop-portal "Window Identifiers" convention ("wayland:<handle>").
+*/
+func WaylandWindowID(handle string) (windowID string) {
+
+	windowID = "wayland:" + handle
+
+	return
+}
+
+/*
+	Dismiss dismisses (cancels) a Prompt.
+	The SecretService will emit the Completed signal for the Prompt (with dismissed set to true).
+*/
+func (p *Prompt) Dismiss() (err error) {
+
+	err = p.DismissContext(context.Background())
+
+	return
+}
+
+// DismissContext is like Prompt.Dismiss but uses ctx for the Dbus call.
+func (p *Prompt) DismissContext(ctx context.Context) (err error) {
+
+	if err = callContext(
+		ctx, p.Dbus, DbusPromptDismiss,
+	).Store(); err != nil {
+		return
+	}
+
+	return
+}
+
+/*
+	Prompt issues/waits for a prompt for unlocking a Locked Collection or Secret / Item.
+
+	Prompt.WindowID is passed to the SecretService as the parent window for the prompt.
+
+	If the user dismisses (cancels) the prompt, err will be ErrPromptDismissed.
+	Otherwise promptValue is the (operation-dependent) result of the Prompt; e.g. for a Collection creation
+	it contains the dbus.ObjectPath of the new Collection, and for an Unlock it contains
+	a []dbus.ObjectPath of the objects that were unlocked.
+*/
 func (p *Prompt) Prompt() (promptValue *dbus.Variant, err error) {
 
+	promptValue, err = p.PromptContext(context.Background())
+
+	return
+}
+
+/*
+	PromptContext is like Prompt.Prompt, but stops waiting for the Prompt to complete if ctx is cancelled.
+	If ctx is cancelled, the Prompt is dismissed (via Prompt.DismissContext, waiting at most a few seconds
+	for the SecretService to respond) and err will be ctx.Err().
+*/
+func (p *Prompt) PromptContext(ctx context.Context) (promptValue *dbus.Variant, err error) {
+
+	var ok bool
+	var dismissed bool
+	var owner string
 	var c chan *dbus.Signal
 	var result *dbus.Signal
+	var matchOpts []dbus.MatchOption
 
-	promptValue = new(dbus.Variant)
+	/*
+		Only the SecretService itself may complete the Prompt; signals are matched (and checked) against the unique name
+		owning its bus name, as any other peer could otherwise emit a Completed signal for a guessable Prompt path.
+	*/
+	if owner, err = nameOwner(ctx, p.Conn, p.Dbus.Destination()); err != nil {
+		return
+	}
+	matchOpts = []dbus.MatchOption{
+		dbus.WithMatchSender(owner),
+		dbus.WithMatchObjectPath(p.path()),
+		dbus.WithMatchInterface(DbusInterfacePrompt),
+		dbus.WithMatchMember(DbusPromptCompletedMember),
+	}
 
 	// Prompts are asynchronous; we connect to the signal and block with a channel until we get a response.
+	if err = p.Conn.AddMatchSignalContext(ctx, matchOpts...); err != nil {
+		return
+	}
+	defer p.Conn.RemoveMatchSignal(matchOpts...)
+
+	/*
+		c is deliberately never closed by us; the dbus.Conn owns writing to it (and closes it itself if the
+		connection is terminated). Removing it from the dbus.Conn is enough to stop delivery.
+	*/
 	c = make(chan *dbus.Signal, 10)
-	defer close(c)
 
 	p.Conn.Signal(c)
 	defer p.Conn.RemoveSignal(c)
 
-	if err = p.Dbus.Call(
-		DbusPrompterInterface, 0, "GoSecret.Prompt", // TODO: This last argument, the string, is for "window ID". I'm unclear what for.
+	if err = callContext(
+		ctx, p.Dbus, DbusPrompterInterface, p.WindowID,
 	).Store(); err != nil {
 		return
 	}
 
 	for {
-		if result = <-c; result.Path == p.Dbus.Path() {
-			*promptValue = result.Body[1].(dbus.Variant)
+		select {
+		case <-ctx.Done():
+			p.dismissAfterCancel()
+			err = ctx.Err()
+			return
+		case result, ok = <-c:
+			if !ok {
+				err = ErrNoDbusConn
+				return
+			}
+			if result.Sender != owner || result.Path != p.path() || result.Name != DbusPromptCompleted {
+				continue
+			}
+			if dismissed, promptValue, err = parsePromptCompleted(result); err != nil {
+				return
+			}
+			if dismissed {
+				err = ErrPromptDismissed
+			}
 			return
 		}
 	}
 }
+
+/*
+	dismissAfterCancel dismisses the Prompt once PromptContext's context is done.
+	That context can't be used for the dismissal itself, so a fresh one bounded by promptDismissTimeout is used instead.
+*/
+func (p *Prompt) dismissAfterCancel() {
+
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	ctx, cancel = context.WithTimeout(context.Background(), promptDismissTimeout)
+	defer cancel()
+
+	_ = p.DismissContext(ctx)
+
+	return
+}
+
+// path is a *very* thin wrapper around Prompt.Dbus.Path().
+func (p *Prompt) path() (dbusPath dbus.ObjectPath) {
+
+	dbusPath = p.Dbus.Path()
+
+	return
+}
+
+/*
+	parsePromptCompleted parses the body of a Prompt's Completed signal (signature "bv").
+	If the body is malformed, err will be ErrSecretServiceProto.
+*/
+func parsePromptCompleted(sig *dbus.Signal) (dismissed bool, result *dbus.Variant, err error) {
+
+	var ok bool
+	var v dbus.Variant
+
+	if sig.Body == nil || len(sig.Body) < 2 {
+		err = ErrSecretServiceProto
+		return
+	}
+
+	if dismissed, ok = sig.Body[0].(bool); !ok {
+		err = ErrSecretServiceProto
+		return
+	}
+	if v, ok = sig.Body[1].(dbus.Variant); !ok {
+		err = ErrSecretServiceProto
+		return
+	}
+
+	result = &v
+
+	return
+}
@@ -0,0 +1,146 @@
+package gosecret
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	// testHungPromptPath is the path of the Prompt exported by newHungPrompt.
+	testHungPromptPath dbus.ObjectPath = dbus.ObjectPath(DbusPromptPrefix + "hung")
+)
+
+/*
+	TestPrompt_PromptContext_Hung tests the following internal functions/methods via nested calls:
+
+		Prompt.PromptContext
+			Prompt.dismissAfterCancel
+				Prompt.DismissContext
+
+	against a provider that neither completes nor answers the dismissal of a Prompt.
+*/
+func TestPrompt_PromptContext_Hung(t *testing.T) {
+
+	var err error
+	var prompt *Prompt
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var start time.Time
+	var elapsed time.Duration
+	var cancelAfter time.Duration = 100 * time.Millisecond
+	var release chan struct{} = make(chan struct{})
+
+	defer close(release)
+
+	prompt, _ = newHungPrompt(t, release)
+
+	ctx, cancel = context.WithTimeout(context.Background(), cancelAfter)
+	defer cancel()
+
+	start = time.Now()
+	_, err = prompt.PromptContext(ctx)
+	elapsed = time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PromptContext for a hung prompt returned '%v', expected context.DeadlineExceeded", err)
+	}
+	if elapsed > cancelAfter+promptDismissTimeout+time.Second {
+		t.Errorf("PromptContext for a hung prompt took %v to return after its context was cancelled", elapsed)
+	}
+}
+
+/*
+	TestPrompt_PromptContext_Sender tests that Prompt.PromptContext ignores a Completed signal
+	for its Prompt from a peer other than the SecretService.
+*/
+func TestPrompt_PromptContext_Sender(t *testing.T) {
+
+	var err error
+	var prompt *Prompt
+	var prompted <-chan struct{}
+	var spoofer *dbus.Conn
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var release chan struct{} = make(chan struct{})
+
+	defer close(release)
+
+	prompt, prompted = newHungPrompt(t, release)
+
+	if spoofer, err = dbus.ConnectSessionBus(); err != nil {
+		t.Fatalf("could not connect to session bus: %v", err.Error())
+	}
+	defer spoofer.Close()
+
+	go func() {
+		select {
+		case <-prompted:
+			_ = spoofer.Emit(testHungPromptPath, DbusPromptCompleted, false, dbus.MakeVariant(""))
+		case <-time.After(time.Second):
+		}
+	}()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	if _, err = prompt.PromptContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PromptContext accepted a Completed signal from another peer (returned '%v')", err)
+	}
+}
+
+/*
+	newHungPrompt returns a Prompt (on its own connection) for an object on a separate connection
+	whose Prompt method never completes and whose Dismiss method blocks until release is closed.
+	prompted receives a value once the Prompt has been shown.
+*/
+func newHungPrompt(t *testing.T, release chan struct{}) (prompt *Prompt, prompted <-chan struct{}) {
+
+	var err error
+	var provider *dbus.Conn
+	var client *dbus.Conn
+	var shown chan struct{} = make(chan struct{}, 1)
+
+	t.Helper()
+
+	if provider, err = dbus.ConnectSessionBus(); err != nil {
+		t.Fatalf("could not connect to session bus: %v", err.Error())
+	}
+	t.Cleanup(func() { _ = provider.Close() })
+	if client, err = dbus.ConnectSessionBus(); err != nil {
+		t.Fatalf("could not connect to session bus: %v", err.Error())
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	if err = provider.ExportMethodTable(
+		map[string]interface{}{
+			"Prompt": func(windowID string) (dbusErr *dbus.Error) {
+				select {
+				case shown <- struct{}{}:
+				default:
+				}
+				return
+			},
+			"Dismiss": func() (dbusErr *dbus.Error) {
+				<-release
+				return
+			},
+		},
+		testHungPromptPath, DbusInterfacePrompt,
+	); err != nil {
+		t.Fatalf("could not export hung prompt: %v", err.Error())
+	}
+
+	prompt = &Prompt{
+		DbusObject: &DbusObject{
+			Conn: client,
+			Dbus: client.Object(provider.Names()[0], testHungPromptPath),
+		},
+	}
+	prompted = shown
+
+	return
+}
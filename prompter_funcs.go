@@ -0,0 +1,59 @@
+package gosecret
+
+import (
+	`context`
+	`fmt`
+
+	`github.com/godbus/dbus/v5`
+)
+
+// HandlePrompt shows prompt and waits for it to complete (or for ctx to be cancelled, in which case prompt is dismissed).
+func (i InteractivePrompter) HandlePrompt(ctx context.Context, prompt *Prompt) (result *dbus.Variant, err error) {
+
+	result, err = prompt.PromptContext(ctx)
+
+	return
+}
+
+// HandlePrompt never shows prompt; it always returns a *PromptRequiredError.
+func (f FailFastPrompter) HandlePrompt(ctx context.Context, prompt *Prompt) (result *dbus.Variant, err error) {
+
+	err = NewPromptRequiredError(prompt)
+
+	return
+}
+
+// HandlePrompt calls the PromptFunc itself.
+func (p PromptFunc) HandlePrompt(ctx context.Context, prompt *Prompt) (result *dbus.Variant, err error) {
+
+	result, err = p(ctx, prompt)
+
+	return
+}
+
+// NewPromptRequiredError returns a *PromptRequiredError for prompt (as an error).
+func NewPromptRequiredError(prompt *Prompt) (err error) {
+
+	err = &PromptRequiredError{
+		Path:   prompt.path(),
+		Prompt: prompt,
+	}
+
+	return
+}
+
+// Error returns the string format of the error; this is necessary to be considered a valid error interface.
+func (e *PromptRequiredError) Error() (errStr string) {
+
+	errStr = fmt.Sprintf("%v: %v", ErrPromptRequired.Error(), string(e.Path))
+
+	return
+}
+
+// Is allows errors.Is(err, ErrPromptRequired) to match a *PromptRequiredError.
+func (e *PromptRequiredError) Is(target error) (is bool) {
+
+	is = target == ErrPromptRequired
+
+	return
+}
@@ -0,0 +1,57 @@
+package gosecret
+
+import (
+	`context`
+	`errors`
+	`testing`
+
+	`github.com/godbus/dbus/v5`
+)
+
+/*
+	TestPrompter tests the following internal functions/methods via nested calls:
+
+		FailFastPrompter.HandlePrompt
+			NewPromptRequiredError
+		PromptFunc.HandlePrompt
+		PromptRequiredError.Is
+
+	InteractivePrompter is covered by the Service tests.
+*/
+func TestPrompter(t *testing.T) {
+
+	var err error
+	var result *dbus.Variant
+	var reqErr *PromptRequiredError
+	var called bool
+	var promptPath dbus.ObjectPath = dbus.ObjectPath(DbusPromptPrefix + "p1")
+	var prompt *Prompt = NewPrompt(nil, promptPath)
+	var prompter Prompter
+
+	prompter = FailFastPrompter{}
+	if result, err = prompter.HandlePrompt(context.Background(), prompt); err == nil {
+		t.Fatalf("FailFastPrompter returned a nil error (result: %#v)", result)
+	}
+	if !errors.Is(err, ErrPromptRequired) {
+		t.Errorf("FailFastPrompter error '%v' is not ErrPromptRequired", err.Error())
+	}
+	if !errors.As(err, &reqErr) {
+		t.Errorf("FailFastPrompter error '%v' is not a *PromptRequiredError", err.Error())
+	} else if reqErr.Path != promptPath {
+		t.Errorf("PromptRequiredError path '%v' does not match prompt path '%v'", string(reqErr.Path), string(promptPath))
+	}
+
+	prompter = PromptFunc(func(ctx context.Context, p *Prompt) (v *dbus.Variant, err error) {
+		var variant dbus.Variant = dbus.MakeVariant(p.path())
+		called = true
+		v = &variant
+		return
+	})
+	if result, err = prompter.HandlePrompt(context.Background(), prompt); err != nil {
+		t.Errorf("PromptFunc returned an error: %v", err.Error())
+	} else if !called {
+		t.Errorf("PromptFunc was not called")
+	} else if result.Value().(dbus.ObjectPath) != promptPath {
+		t.Errorf("PromptFunc result '%v' does not match prompt path '%v'", result.Value(), string(promptPath))
+	}
+}
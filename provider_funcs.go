@@ -0,0 +1,85 @@
+package gosecret
+
+import (
+	"context"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	waitForProvider asks the bus to activate name (via StartServiceByName) and waits for it to have an owner.
+	If timeout is greater than 0, it limits the wait in addition to ctx.
+	err is ErrProviderUnavailable if name has no owner when the wait ends.
+*/
+func waitForProvider(ctx context.Context, conn *dbus.Conn, name string, timeout time.Duration) (err error) {
+
+	var hasOwner bool
+	var c chan *dbus.Signal
+	var sig *dbus.Signal
+	var ok bool
+	var sigName string
+	var owner string
+	var cancel context.CancelFunc
+	var bus dbus.BusObject = conn.Object(DbusBusName, dbus.ObjectPath(DbusBusPath))
+	var match []dbus.MatchOption = []dbus.MatchOption{
+		dbus.WithMatchSender(DbusBusName),
+		dbus.WithMatchInterface(DbusBusName),
+		dbus.WithMatchMember(DbusNameOwnerChanged),
+		dbus.WithMatchArg(0, name),
+	}
+
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// Subscribe before checking, so the owner appearing in between is not missed.
+	if err = conn.AddMatchSignalContext(ctx, match...); err != nil {
+		return
+	}
+	defer conn.RemoveMatchSignal(match...)
+
+	// As with watchSignals, c is owned by conn and never closed by us.
+	c = make(chan *dbus.Signal, 8)
+	conn.Signal(c)
+	defer conn.RemoveSignal(c)
+
+	/*
+		This fails (e.g. with DbusErrServiceUnknown) if name isn't activatable, or if activation itself failed;
+		either way the provider may still be started by other means (e.g. a systemd unit), so keep waiting.
+	*/
+	_ = callContext(ctx, bus, DbusStartServiceByName, name, uint32(0)).Err
+
+	if err = callContext(ctx, bus, DbusNameHasOwner, name).Store(&hasOwner); err != nil {
+		if ctx.Err() != nil {
+			err = ErrProviderUnavailable
+		}
+		return
+	}
+	if hasOwner {
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			err = ErrProviderUnavailable
+			return
+		case sig, ok = <-c:
+			if !ok {
+				err = ErrProviderUnavailable
+				return
+			}
+			if sig.Name != DbusBusName+"."+DbusNameOwnerChanged || len(sig.Body) != 3 {
+				continue
+			}
+			if sigName, _ = sig.Body[0].(string); sigName != name {
+				continue
+			}
+			if owner, _ = sig.Body[2].(string); owner != "" {
+				return
+			}
+		}
+	}
+}
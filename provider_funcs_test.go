@@ -0,0 +1,81 @@
+package gosecret
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/server"
+)
+
+/*
+	TestNewService_WaitForProvider tests the following internal functions/methods via nested calls:
+
+		NewService
+			WithWaitForProvider
+				waitForProvider
+
+	It requires the private test bus (see TestMain), as it starts a provider on it after NewService is called.
+*/
+func TestNewService_WaitForProvider(t *testing.T) {
+
+	var err error
+	var svc *Service
+	var conn *dbus.Conn
+	var srv *server.Server
+	var start time.Time
+	var done chan error = make(chan error, 1)
+	var name string = "org.example.gosecret.Late"
+
+	if os.Getenv(envLiveTests) != "" {
+		t.Skip("waiting for a provider requires the private test bus")
+	}
+
+	// Nothing ever provides the name.
+	start = time.Now()
+	if _, err = NewService(
+		WithDestination("org.example.gosecret.Never"), WithWaitForProvider(200*time.Millisecond),
+	); !errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("NewService for a provider that never appears returned '%v', expected ErrProviderUnavailable", err)
+	}
+	if time.Since(start) < 200*time.Millisecond {
+		t.Errorf("NewService returned before the WithWaitForProvider timeout")
+	}
+
+	// The provider appears while NewService is waiting.
+	go func() {
+		var err error
+		svc, err = NewService(WithDestination(name), WithWaitForProvider(5*time.Second))
+		done <- err
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	if conn, err = dbus.ConnectSessionBus(); err != nil {
+		t.Fatalf("could not connect to session bus: %v", err.Error())
+	}
+	defer conn.Close()
+	if srv, err = server.New(conn, server.NewMemoryStorage()); err != nil {
+		t.Fatalf("could not start provider: %v", err.Error())
+	}
+	defer srv.Close()
+	if err = srv.RequestName(name); err != nil {
+		t.Fatalf("could not request name for provider: %v", err.Error())
+	}
+
+	if err = <-done; err != nil {
+		t.Fatalf("NewService did not wait for the provider: %v", err.Error())
+	}
+	if svc.Session == nil {
+		t.Errorf("NewService did not open a Session after the provider appeared")
+	}
+	if err = svc.Close(); err != nil {
+		t.Errorf("could not close Service: %v", err.Error())
+	}
+
+	if _, err = NewService(WithWaitForProvider(-1)); !errors.Is(err, ErrBadOption) {
+		t.Errorf("NewService with a negative WithWaitForProvider timeout returned '%v', expected ErrBadOption", err)
+	}
+}
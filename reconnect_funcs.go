@@ -0,0 +1,237 @@
+package gosecret
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	Stale returns true if the Collection's cached fields were loaded before the Service last re-established
+	its Session because the SecretService restarted (see WithReconnect). Use Collection.Refresh to update them,
+	or fetch the Collection again (e.g. via Service.GetCollection) if the SecretService may have changed its paths.
+*/
+func (c *Collection) Stale() (stale bool) {
+
+	c.lock.RLock()
+	stale = c.generation != c.service.generation()
+	c.lock.RUnlock()
+
+	return
+}
+
+// Stale is like Collection.Stale, but for an Item. Use Item.Refresh to update it.
+func (i *Item) Stale() (stale bool) {
+
+	i.lock.RLock()
+	stale = i.generation != i.collection.service.generation()
+	i.lock.RUnlock()
+
+	return
+}
+
+// startReconnect starts watching the SecretService's bus name for owner changes (see WithReconnect).
+func (s *Service) startReconnect(handler ReconnectHandler) (err error) {
+
+	var ctx context.Context
+	var c chan *dbus.Signal
+	var r *reconnector = &reconnector{
+		handler: handler,
+		done:    make(chan struct{}),
+	}
+	var match []dbus.MatchOption = []dbus.MatchOption{
+		dbus.WithMatchSender(DbusBusName),
+		dbus.WithMatchInterface(DbusBusName),
+		dbus.WithMatchMember(DbusNameOwnerChanged),
+		dbus.WithMatchArg(0, s.Dbus.Destination()),
+	}
+
+	if err = s.Conn.AddMatchSignal(match...); err != nil {
+		return
+	}
+
+	// The current owner (if any); only a change from it is a restart.
+	r.owner, _ = nameOwner(context.Background(), s.Conn, s.Dbus.Destination())
+
+	// As with watchSignals, c is owned by the connection and never closed by us.
+	c = make(chan *dbus.Signal, 8)
+	s.Conn.Signal(c)
+
+	ctx, r.cancel = context.WithCancel(context.Background())
+	s.reconnect = r
+
+	go func() {
+
+		var ok bool
+		var sig *dbus.Signal
+		var name string
+		var owner string
+
+		defer close(r.done)
+		defer func() {
+			s.Conn.RemoveSignal(c)
+			_ = s.Conn.RemoveMatchSignal(match...)
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig, ok = <-c:
+				if !ok {
+					return
+				}
+				if sig.Name != DbusBusName+"."+DbusNameOwnerChanged || len(sig.Body) != 3 {
+					continue
+				}
+				name, _ = sig.Body[0].(string)
+				owner, _ = sig.Body[2].(string)
+				// An empty new owner means the SecretService went away; wait for it to come back (or be activated).
+				if name != s.Dbus.Destination() || owner == "" || owner == r.owner {
+					continue
+				}
+				r.owner = owner
+				_ = s.reestablish(ctx, s.generation(), owner)
+			}
+		}
+	}()
+
+	return
+}
+
+// stopReconnect stops the watcher started by startReconnect and waits for it to exit.
+func (s *Service) stopReconnect() {
+
+	if s.reconnect == nil {
+		return
+	}
+
+	s.reconnect.cancel()
+	<-s.reconnect.done
+
+	return
+}
+
+/*
+	reestablish opens a new Session (with the same algorithm as the current one), replaces Service.Session with it,
+	and increments the reconnect generation. seen is the generation the caller observed before failing;
+	if another goroutine has re-established the Session since then, this is a no-op.
+	owner is the new SecretService owner, if known.
+*/
+func (s *Service) reestablish(ctx context.Context, seen uint64, owner string) (err error) {
+
+	var old *Session
+	var ssn *Session
+	var gen uint64
+	var r *reconnector = s.reconnect
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if atomic.LoadUint64(&r.generation) != seen {
+		return
+	}
+
+	if old = s.CurrentSession(); old != nil {
+		if ssn, _, err = s.OpenSessionContext(ctx, old.Algorithm, ""); err != nil {
+			if r.handler != nil {
+				go r.handler(&ReconnectEvent{Owner: owner, Generation: seen, Err: err})
+			}
+			return
+		}
+		atomic.StoreUint32(&old.invalid, 1)
+		s.lock.Lock()
+		s.Session = ssn
+		s.lock.Unlock()
+		/*
+			The old Session is deliberately not closed; it no longer exists in the restarted SecretService,
+			and its path may already have been reused for the new Session.
+		*/
+	}
+
+	gen = atomic.AddUint64(&r.generation, 1)
+
+	if r.handler != nil {
+		go r.handler(&ReconnectEvent{Owner: owner, Generation: gen, Session: ssn})
+	}
+
+	return
+}
+
+/*
+	withRetry calls fn and, if reconnecting is enabled (see WithReconnect) and fn failed because the SecretService
+	or the Session went away (see isProviderGone), re-establishes the Session and calls fn once more.
+	fn must be idempotent and must look up the Session (e.g. via Service.CurrentSession) on every call.
+*/
+func (s *Service) withRetry(ctx context.Context, fn func() (err error)) (err error) {
+
+	var gen uint64
+
+	if s.reconnect == nil {
+		err = fn()
+		return
+	}
+
+	gen = s.generation()
+
+	if err = fn(); err == nil || !isProviderGone(err) || ctx.Err() != nil {
+		return
+	}
+
+	// If the Session can't be re-established, the original error is more useful.
+	if s.reestablish(ctx, gen, "") != nil {
+		return
+	}
+
+	err = fn()
+
+	return
+}
+
+// generation returns the reconnect generation (0 if reconnecting is disabled).
+func (s *Service) generation() (gen uint64) {
+
+	if s.reconnect == nil {
+		return
+	}
+
+	gen = atomic.LoadUint64(&s.reconnect.generation)
+
+	return
+}
+
+/*
+	current returns the Session that should be used in place of s: s itself,
+	or its Service's current Session if s was replaced after the SecretService restarted.
+*/
+func (s *Session) current() (ssn *Session) {
+
+	ssn = s
+
+	if atomic.LoadUint32(&s.invalid) == 1 && s.service != nil {
+		if ssn = s.service.CurrentSession(); ssn == nil {
+			ssn = s
+		}
+	}
+
+	return
+}
+
+// isProviderGone returns true if err indicates the SecretService (or the Session used) no longer exists.
+func isProviderGone(err error) (gone bool) {
+
+	var dbusErr *dbus.Error
+
+	if !errors.As(err, &dbusErr) {
+		return
+	}
+
+	switch dbusErr.Name {
+	case DbusErrServiceUnknown, DbusErrNameHasNoOwner, DbusErrNoReply, DbusErrDisconnected, DbusErrNoSession:
+		gone = true
+	}
+
+	return
+}
@@ -0,0 +1,146 @@
+package gosecret
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/server"
+)
+
+/*
+	TestService_Reconnect tests the following internal functions/methods via nested calls:
+
+		NewService
+			WithReconnect
+		Service.startReconnect
+		Service.reestablish
+		Service.withRetry
+		Session.current
+		Item.GetSecret
+		Item.Stale
+		Collection.Stale
+
+	It requires the private test bus (see TestMain), as it restarts a second provider on it.
+*/
+func TestService_Reconnect(t *testing.T) {
+
+	var err error
+	var svc *Service
+	var conn *dbus.Conn
+	var srv *server.Server
+	var collection *Collection
+	var item *Item
+	var secret *Secret
+	var oldSession *Session
+	var evt *ReconnectEvent
+	var events chan *ReconnectEvent = make(chan *ReconnectEvent, 4)
+	var storage *server.MemoryStorage = server.NewMemoryStorage()
+	var now time.Time = time.Now()
+	var name string = "org.example.gosecret.Restart"
+	var collPath dbus.ObjectPath = dbus.ObjectPath(DbusNewCollectionPath + defaultCollection)
+
+	if os.Getenv(envLiveTests) != "" {
+		t.Skip("restarting a provider requires the private test bus")
+	}
+
+	if err = storage.PutCollection(&server.CollectionRecord{
+		ID: defaultCollection, Label: defaultCollectionLabel, Created: now, Modified: now,
+	}); err != nil {
+		t.Fatalf("could not create collection: %v", err.Error())
+	}
+	if err = storage.PutItem(defaultCollection, &server.ItemRecord{
+		ID: "1", Label: testItemLabel, Attributes: map[string]string{"foo": "bar"}, Secret: []byte(testSecretContent),
+		ContentType: "text/plain", Created: now, Modified: now,
+	}); err != nil {
+		t.Fatalf("could not create item: %v", err.Error())
+	}
+
+	// start (re)starts the provider on a fresh connection.
+	start := func() {
+		if srv != nil {
+			_ = srv.Close()
+			_ = conn.Close()
+		}
+		if conn, err = dbus.ConnectSessionBus(); err != nil {
+			t.Fatalf("could not connect to session bus: %v", err.Error())
+		}
+		if srv, err = server.New(conn, storage); err != nil {
+			t.Fatalf("could not start provider: %v", err.Error())
+		}
+		if err = srv.RequestName(name); err != nil {
+			t.Fatalf("could not request name for provider: %v", err.Error())
+		}
+	}
+	start()
+	defer func() {
+		_ = srv.Close()
+		_ = conn.Close()
+	}()
+
+	if svc, err = NewService(
+		WithDestination(name),
+		WithSessionAlgorithm(SessionAlgoDH),
+		WithReconnect(func(evt *ReconnectEvent) {
+			events <- evt
+		}),
+	); err != nil {
+		t.Fatalf("could not get new Service via NewService: %v", err.Error())
+	}
+	defer svc.Close()
+
+	if collection, err = NewCollection(svc, collPath); err != nil {
+		t.Fatalf("NewCollection failed: %v", err.Error())
+	}
+	if item, err = NewItem(collection, collPath+"/1"); err != nil {
+		t.Fatalf("NewItem failed: %v", err.Error())
+	}
+	oldSession = svc.CurrentSession()
+
+	// A restart is picked up via NameOwnerChanged.
+	start()
+
+	select {
+	case evt = <-events:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no ReconnectEvent after the provider restarted")
+	}
+	if evt.Err != nil || evt.Session == nil || evt.Generation != 1 || evt.Owner == "" {
+		t.Errorf("unexpected ReconnectEvent: %#v", evt)
+	}
+	if svc.CurrentSession() == oldSession || svc.CurrentSession().Algorithm != SessionAlgoDH {
+		t.Errorf("Service.Session was not replaced with a new %v Session", SessionAlgoDH)
+	}
+	if !item.Stale() || !collection.Stale() {
+		t.Errorf("objects loaded before the restart are not stale")
+	}
+
+	// The old Session is transparently replaced.
+	if secret, err = item.GetSecret(oldSession); err != nil {
+		t.Fatalf("Item.GetSecret after restart failed: %v", err.Error())
+	}
+	if string(secret.Value) != testSecretContent {
+		t.Errorf("Item.GetSecret returned '%v' after restart", string(secret.Value))
+	}
+	if err = item.Refresh(); err != nil {
+		t.Errorf("Item.Refresh failed: %v", err.Error())
+	}
+	if item.Stale() {
+		t.Errorf("Item is still stale after Item.Refresh")
+	}
+
+	// A vanished Session is re-established and the call retried.
+	if err = svc.CurrentSession().Close(); err != nil {
+		t.Fatalf("could not close Session: %v", err.Error())
+	}
+	if err = item.SetSecret(NewSecret(oldSession, []byte{}, []byte(testSecretContent+"!"), "text/plain")); err != nil {
+		t.Fatalf("Item.SetSecret with a vanished Session failed: %v", err.Error())
+	}
+	if secret, err = item.GetSecret(svc.CurrentSession()); err != nil || string(secret.Value) != testSecretContent+"!" {
+		t.Errorf("Item.GetSecret after retried Item.SetSecret returned %#v, %v", secret, err)
+	}
+	if svc.generation() != 2 {
+		t.Errorf("reconnect generation is %v, expected 2", svc.generation())
+	}
+}
@@ -0,0 +1,97 @@
+package gosecret
+
+import (
+	`fmt`
+	`strconv`
+)
+
+/*
+	attributes validates attrs against the Schema and converts their values to strings
+	(see SchemaAttrType), adding SchemaNameAttr if withName is true.
+
+	err will be ErrMissingSchema, ErrSchemaAttr or ErrSchemaAttrType if invalid.
+*/
+func (s *Schema) attributes(attrs map[string]interface{}, withName bool) (converted map[string]string, err error) {
+
+	var ok bool
+	var attrType SchemaAttrType
+	var value string
+
+	if s == nil || s.Name == "" {
+		err = ErrMissingSchema
+		return
+	}
+
+	converted = make(map[string]string, len(attrs)+1)
+
+	for k, v := range attrs {
+		if attrType, ok = s.Attributes[k]; !ok {
+			err = fmt.Errorf("%w: %q (schema %v)", ErrSchemaAttr, k, s.Name)
+			converted = nil
+			return
+		}
+		if value, ok = schemaAttrValue(attrType, v); !ok {
+			err = fmt.Errorf("%w: %q is %T (schema %v)", ErrSchemaAttrType, k, v, s.Name)
+			converted = nil
+			return
+		}
+		converted[k] = value
+	}
+
+	if withName {
+		converted[SchemaNameAttr] = s.Name
+	}
+
+	return
+}
+
+// matchName returns true if lookups/searches with the Schema should be restricted to its SchemaNameAttr.
+func (s *Schema) matchName() (match bool) {
+
+	match = s == nil || s.Flags != FlagSchemaDontMatchName
+
+	return
+}
+
+// schemaAttrValue converts v to its string form for attrType; ok is false if v is not of attrType.
+func schemaAttrValue(attrType SchemaAttrType, v interface{}) (value string, ok bool) {
+
+	var b bool
+
+	switch attrType {
+	case SchemaAttrString:
+		value, ok = v.(string)
+	case SchemaAttrBoolean:
+		if b, ok = v.(bool); ok {
+			value = strconv.FormatBool(b)
+		}
+	case SchemaAttrInteger:
+		ok = true
+		switch n := v.(type) {
+		case int:
+			value = strconv.FormatInt(int64(n), 10)
+		case int8:
+			value = strconv.FormatInt(int64(n), 10)
+		case int16:
+			value = strconv.FormatInt(int64(n), 10)
+		case int32:
+			value = strconv.FormatInt(int64(n), 10)
+		case int64:
+			value = strconv.FormatInt(n, 10)
+		case uint:
+			value = strconv.FormatUint(uint64(n), 10)
+		case uint8:
+			value = strconv.FormatUint(uint64(n), 10)
+		case uint16:
+			value = strconv.FormatUint(uint64(n), 10)
+		case uint32:
+			value = strconv.FormatUint(uint64(n), 10)
+		case uint64:
+			value = strconv.FormatUint(n, 10)
+		default:
+			ok = false
+		}
+	}
+
+	return
+}
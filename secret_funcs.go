@@ -1,6 +1,14 @@
 package gosecret
 
-// NewSecret returns a pointer to a new Secret based on a Session, parameters, (likely an empty byte slice), a value, and the MIME content type.
+import (
+	`fmt`
+)
+
+/*
+	NewSecret returns a pointer to a new Secret based on a Session, parameters, (likely an empty byte slice), a value, and the MIME content type.
+	value should be the unencrypted secret content; if session is encrypted, it will be encrypted when it is sent to the SecretService
+	(e.g. via Collection.CreateItem or Item.SetSecret).
+*/
 func NewSecret(session *Session, params []byte, value []byte, contentType string) (secret *Secret) {
 
 	secret = &Secret{
@@ -8,7 +16,87 @@ func NewSecret(session *Session, params []byte, value []byte, contentType string
 		Parameters:  params,
 		Value:       value,
 		ContentType: contentType,
+		session:     session,
+	}
+
+	return
+}
+
+/*
+	Copy returns a deep copy of the Secret (Parameters and Value are copied).
+	It returns nil if s is nil.
+*/
+func (s *Secret) Copy() (secret *Secret) {
+
+	if s == nil {
+		return
+	}
+
+	secret = &Secret{
+		Session:     s.Session,
+		Parameters:  append([]byte(nil), s.Parameters...),
+		Value:       append(SecretValue(nil), s.Value...),
+		ContentType: s.ContentType,
+		item:        s.item,
+		session:     s.session,
+	}
+
+	return
+}
+
+/*
+	wireSession returns the Session that should be used to send a Secret over Dbus.
+	This is the Session the Secret was created/fetched with if known, otherwise fallback.
+*/
+func (s *Secret) wireSession(fallback *Session) (session *Session) {
+
+	if s.session != nil {
+		session = s.session.current()
+		return
 	}
 
+	session = fallback
+
+	return
+}
+
+// String returns a summary of the Secret with its Value redacted (see RedactedSecret).
+func (s Secret) String() (str string) {
+
+	str = fmt.Sprintf("%v", s)
+
+	return
+}
+
+// GoString returns a Go-syntax representation of the Secret with its Value redacted (for fmt's %#v).
+func (s Secret) GoString() (str string) {
+
+	str = fmt.Sprintf(
+		"gosecret.Secret{Session:%#v, Parameters:%#v, Value:%#v, ContentType:%#v}",
+		s.Session, s.Parameters, s.Value, s.ContentType,
+	)
+
 	return
 }
+
+/*
+	Format implements fmt.Formatter. The Secret is printed as a struct of its exported fields
+	(as fmt would by default), but with its Value redacted (see SecretValue.Format).
+*/
+func (s Secret) Format(f fmt.State, verb rune) {
+
+	if verb == 'v' && f.Flag('#') {
+		_, _ = fmt.Fprint(f, s.GoString())
+		return
+	}
+
+	_, _ = fmt.Fprintf(
+		f, formatDirective(f, verb),
+		redactedSecret{
+			Session:     s.Session,
+			Parameters:  s.Parameters,
+			Value:       s.Value,
+			ContentType: s.ContentType,
+		},
+	)
+}
@@ -1,17 +1,120 @@
 package gosecret
 
 import (
+	`bytes`
+	`encoding/base64`
 	`fmt`
+	`io`
+	`strconv`
+	`unicode/utf8`
 )
 
 /*
 	MarshalJSON converts a SecretValue to a JSON representation.
 	For compat reasons, the MarshalText is left "unmolested" (i.e. renders to a Base64 value).
 	I don't bother with an UnmarshalJSON because it makes exactly 0 sense to unmarshal due to runtime and unexported fields in Secret.
+
+	The value is redacted (i.e. it is always marshaled as RedactedSecret); use MarshalOptions to include it.
+*/
+func (s SecretValue) MarshalJSON() (b []byte, err error) {
+
+	b = []byte(strconv.Quote(RedactedSecret))
+
+	return
+}
+
+/*
+	marshalRevealed converts a SecretValue to a JSON string containing the actual value (see MarshalOptions).
+	If the value is not valid UTF-8 (which a JSON string can not hold losslessly), the string is its standard base64
+	encoding instead (see ValueEncodingBase64).
+
+	The value is written directly into b (rather than via an intermediate string, which could not be wiped);
+	b holds a copy of the secret, so callers handling it carefully should zero it when done.
+*/
+func (s SecretValue) marshalRevealed() (b []byte, err error) {
+
+	var buf bytes.Buffer
+
+	if !utf8.Valid(s) {
+		b = make([]byte, base64.StdEncoding.EncodedLen(len(s))+2)
+		b[0] = '"'
+		base64.StdEncoding.Encode(b[1:len(b)-1], s)
+		b[len(b)-1] = '"'
+		return
+	}
+
+	buf.Grow(len(s) + 2)
+	// Only what JSON requires is escaped.
+	buf.WriteByte('"')
+	for _, c := range s {
+		switch {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c < 0x20:
+			buf.WriteString(fmt.Sprintf(`\u%04x`, c))
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+
+	b = buf.Bytes()
+
+	return
+}
+
+/*
+	Wipe overwrites the SecretValue's content with zeroes and sets it to nil.
+	If it was allocated in locked memory (see WithLockedMemory), that memory is released.
+
+	Only this SecretValue's underlying buffer is wiped; copies (e.g. from Secret.Copy or MarshalOptions.Marshal) are not.
 */
-func (s *SecretValue) MarshalJSON() (b []byte, err error) {
+func (s *SecretValue) Wipe() {
 
-	b = []byte(fmt.Sprintf("\"%v\"", string(*s)))
+	if s == nil || *s == nil {
+		return
+	}
+
+	wipeBytes(*s)
+	freeLocked(*s)
+	*s = nil
+}
+
+// String returns RedactedSecret; use string(s) to get the actual value.
+func (s SecretValue) String() (str string) {
+
+	str = RedactedSecret
 
 	return
 }
+
+// GoString returns a redacted Go-syntax representation (for fmt's %#v).
+func (s SecretValue) GoString() (str string) {
+
+	str = "gosecret.SecretValue(" + strconv.Quote(RedactedSecret) + ")"
+
+	return
+}
+
+/*
+	Format implements fmt.Formatter so that every verb (including %x, %q and %#v) prints RedactedSecret
+	instead of the value.
+*/
+func (s SecretValue) Format(f fmt.State, verb rune) {
+
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		_, _ = io.WriteString(f, s.GoString())
+	case verb == 'q':
+		_, _ = io.WriteString(f, strconv.Quote(RedactedSecret))
+	default:
+		_, _ = io.WriteString(f, RedactedSecret)
+	}
+}
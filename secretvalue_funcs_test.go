@@ -0,0 +1,191 @@
+package gosecret
+
+import (
+	`bytes`
+	`encoding/base64`
+	`encoding/json`
+	`errors`
+	`fmt`
+	`strings`
+	`testing`
+)
+
+/*
+	TestSecretValue tests the following internal functions/methods via nested calls:
+
+		SecretValue.MarshalJSON
+		SecretValue.Wipe
+			wipeBytes
+			freeLocked
+		allocLocked
+
+*/
+func TestSecretValue(t *testing.T) {
+
+	var b []byte
+	var backing []byte
+	var value SecretValue
+	var err error
+
+	value = SecretValue(testSecretContent)
+	if b, err = value.MarshalJSON(); err != nil {
+		t.Fatalf("SecretValue.MarshalJSON failed: %v", err.Error())
+	}
+	if string(b) != "\""+RedactedSecret+"\"" {
+		t.Errorf("SecretValue.MarshalJSON returned '%v'", string(b))
+	}
+
+	backing = []byte(testSecretContent)
+	value = SecretValue(backing)
+	value.Wipe()
+	if value != nil {
+		t.Errorf("SecretValue.Wipe did not reset the value")
+	}
+	for _, c := range backing {
+		if c != 0 {
+			t.Fatalf("SecretValue.Wipe did not zero the buffer: %v", backing)
+		}
+	}
+	// Wiping again (or a nil value) is a no-op.
+	value.Wipe()
+
+	if b, err = allocLocked(len(testSecretContent)); err != nil {
+		if !lockedMemorySupported || errors.Is(err, ErrLockedMemory) {
+			t.Skipf("locked memory not available: %v", err)
+		}
+		t.Fatalf("allocLocked failed: %v", err.Error())
+	}
+	if len(b) != len(testSecretContent) || cap(b) != len(testSecretContent) {
+		t.Errorf("allocLocked returned a buffer of len %v/cap %v; expected %v", len(b), cap(b), len(testSecretContent))
+	}
+	copy(b, testSecretContent)
+	value = SecretValue(b)
+	if string(value) != testSecretContent {
+		t.Errorf("locked buffer does not hold its value")
+	}
+	value.Wipe()
+	if freeLocked(b) {
+		t.Errorf("freeLocked released an already-released buffer")
+	}
+}
+
+/*
+	TestRedaction tests the following internal functions/methods via nested calls:
+
+		SecretValue.String
+		SecretValue.GoString
+		SecretValue.Format
+		SecretValue.MarshalJSON
+		Secret.String
+		Secret.Format
+		Item.String
+		Item.Format
+		MarshalOptions.Marshal
+			revealable
+			SecretValue.marshalRevealed
+
+*/
+func TestRedaction(t *testing.T) {
+
+	var b []byte
+	var out string
+	var secret *Secret
+	var item *Item
+	var ok bool
+	var decoded map[string]interface{}
+	var decodedSecret map[string]interface{}
+	var value string = testSecretContent + " \"quoted\"\n"
+	var binaryValue []byte = []byte{0x00, 0xff, 0xfe, '"', 0xc3, 0x28, 0x80}
+	var backing []byte
+	var roundTrip struct {
+		Value    string `json:"value"`
+		Encoding string `json:"value_encoding"`
+	}
+	var err error
+
+	secret = &Secret{
+		Session:     "/org/freedesktop/secrets/session/1",
+		Parameters:  []byte{},
+		Value:       SecretValue(value),
+		ContentType: "text/plain",
+	}
+	item = &Item{
+		Secret:    secret,
+		LabelName: testItemLabel,
+		Attrs:     map[string]string{"foo": "bar"},
+	}
+
+	for _, f := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%X", "%10.3v"} {
+		for _, obj := range []interface{}{secret.Value, secret, *secret, item} {
+			out = fmt.Sprintf(f, obj)
+			if strings.Contains(out, testSecretContent) || strings.Contains(out, fmt.Sprintf("%x", testSecretContent)) ||
+				strings.Contains(out, fmt.Sprintf("%X", testSecretContent)) {
+				t.Errorf("%T formatted with '%v' contains the secret: %v", obj, f, out)
+			}
+		}
+	}
+	if out = secret.String(); !strings.Contains(out, RedactedSecret) || !strings.Contains(out, "text/plain") {
+		t.Errorf("Secret.String did not redact (only) the value: %v", out)
+	}
+	if out = item.String(); !strings.Contains(out, RedactedSecret) || !strings.Contains(out, testItemLabel) {
+		t.Errorf("Item.String did not redact (only) the value: %v", out)
+	}
+
+	for _, obj := range []interface{}{secret.Value, secret, item, item.Info(), []*Item{item}} {
+		if b, err = json.Marshal(obj); err != nil {
+			t.Fatalf("json.Marshal(%T) failed: %v", obj, err.Error())
+		}
+		if strings.Contains(string(b), testSecretContent) {
+			t.Errorf("json.Marshal(%T) contains the secret: %v", obj, string(b))
+		}
+		if b, err = (MarshalOptions{}).Marshal(obj); err != nil {
+			t.Fatalf("MarshalOptions.Marshal(%T) failed: %v", obj, err.Error())
+		}
+		if strings.Contains(string(b), testSecretContent) {
+			t.Errorf("MarshalOptions.Marshal(%T) without Reveal contains the secret: %v", obj, string(b))
+		}
+	}
+
+	if b, err = (MarshalOptions{Reveal: true, Indent: "  "}).Marshal(item); err != nil {
+		t.Fatalf("MarshalOptions.Marshal with Reveal failed: %v", err.Error())
+	}
+	if err = json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("MarshalOptions.Marshal with Reveal returned invalid JSON: %v\n%v", err.Error(), string(b))
+	}
+	if decodedSecret, ok = decoded["secret"].(map[string]interface{}); !ok ||
+		decodedSecret["value"] != value || decodedSecret["content_type"] != "text/plain" {
+		t.Errorf("MarshalOptions.Marshal with Reveal did not include the secret value: %v", string(b))
+	}
+	if decoded["label"] != testItemLabel {
+		t.Errorf("MarshalOptions.Marshal with Reveal did not include the item's fields: %v", string(b))
+	}
+	for _, obj := range []interface{}{secret.Value, secret, *secret, []*Item{item}} {
+		if b, err = (MarshalOptions{Reveal: true}).Marshal(obj); err != nil {
+			t.Errorf("MarshalOptions.Marshal(%T) with Reveal failed: %v", obj, err.Error())
+		} else if !strings.Contains(string(b), testSecretContent) {
+			t.Errorf("MarshalOptions.Marshal(%T) with Reveal did not include the secret value: %v", obj, string(b))
+		}
+	}
+	for _, obj := range []interface{}{struct{}{}, item.Info(), []ItemInfo{item.Info()}} {
+		if _, err = (MarshalOptions{Reveal: true}).Marshal(obj); !errors.Is(err, ErrRevealUnsupported) {
+			t.Errorf("MarshalOptions.Marshal(%T) with Reveal returned %v; expected %v", obj, err, ErrRevealUnsupported)
+		}
+	}
+	// Values that are not valid UTF-8 round-trip (as base64).
+	secret.Value = SecretValue(binaryValue)
+	if b, err = (MarshalOptions{Reveal: true}).Marshal(secret); err != nil {
+		t.Fatalf("MarshalOptions.Marshal with Reveal failed for a binary value: %v", err.Error())
+	}
+	if err = json.Unmarshal(b, &roundTrip); err != nil {
+		t.Fatalf("MarshalOptions.Marshal with Reveal returned invalid JSON for a binary value: %v\n%v", err.Error(), string(b))
+	}
+	if roundTrip.Encoding != ValueEncodingBase64 {
+		t.Errorf("MarshalOptions.Marshal with Reveal did not mark a binary value as %v: %v", ValueEncodingBase64, string(b))
+	}
+	if backing, err = base64.StdEncoding.DecodeString(roundTrip.Value); err != nil || !bytes.Equal(backing, binaryValue) {
+		t.Errorf("MarshalOptions.Marshal with Reveal did not round-trip a binary value: %v", string(b))
+	}
+	if b, err = (MarshalOptions{Reveal: true}).Marshal(secret.Value); err != nil || !json.Valid(b) {
+		t.Errorf("MarshalOptions.Marshal(SecretValue) with Reveal returned invalid JSON for a binary value: %v", string(b))
+	}
+}
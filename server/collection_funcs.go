@@ -0,0 +1,233 @@
+package server
+
+import (
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	Delete implements org.freedesktop.Secret.Collection.Delete.
+	The collection, all of its items and any aliases pointing to it are removed; no prompt is required.
+*/
+func (o *collectionObj) Delete() (promptPath dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var items []*ItemRecord
+	var path dbus.ObjectPath = collectionPath(o.id)
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	promptPath = DbusNoPrompt
+
+	if items, err = o.srv.Storage.Items(o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+	if err = o.srv.Storage.DeleteCollection(o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+	delete(o.srv.passwords, o.id)
+
+	for _, i := range items {
+		o.srv.unexport(itemPath(o.id, i.ID))
+	}
+	o.srv.unexport(path)
+
+	o.srv.emit(dbus.ObjectPath(DbusPath), DbusInterfaceService, "CollectionDeleted", path)
+
+	return
+}
+
+// SearchItems implements org.freedesktop.Secret.Collection.SearchItems.
+func (o *collectionObj) SearchItems(attributes map[string]string) (results []dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var items []*ItemRecord
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if items, err = o.srv.Storage.Items(o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	results = make([]dbus.ObjectPath, 0)
+	for _, i := range items {
+		if attrsMatch(i.Attributes, attributes) {
+			results = append(results, itemPath(o.id, i.ID))
+		}
+	}
+
+	return
+}
+
+/*
+	CreateItem implements org.freedesktop.Secret.Collection.CreateItem.
+	If replace is true and an item with exactly the same attributes exists, it is updated instead.
+	The collection must be unlocked.
+*/
+func (o *collectionObj) CreateItem(
+	sender dbus.Sender, properties map[string]dbus.Variant, secret wireSecret, replace bool,
+) (item dbus.ObjectPath, promptPath dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var ssn *session
+	var value []byte
+	var rec *ItemRecord
+	var existing []*ItemRecord
+	var created bool = true
+	var now time.Time = time.Now()
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	item = DbusNoPrompt
+	promptPath = DbusNoPrompt
+
+	if o.srv.collectionLocked(o.id) {
+		dbusErr = newDbusError(DbusErrIsLocked, "the collection is locked")
+		return
+	}
+	if ssn, dbusErr = o.srv.getSession(sender, secret.Session); dbusErr != nil {
+		return
+	}
+	if value, err = ssn.decodeSecret(secret); err != nil {
+		dbusErr = newDbusError(DbusErrInvalidArgs, err.Error())
+		return
+	}
+
+	rec = &ItemRecord{
+		Type:        DbusDefaultItemType,
+		Attributes:  make(map[string]string),
+		Secret:      value,
+		ContentType: secret.ContentType,
+		Created:     now,
+		Modified:    now,
+	}
+	if dbusErr = applyItemProperties(rec, properties); dbusErr != nil {
+		return
+	}
+
+	if replace {
+		if existing, err = o.srv.Storage.Items(o.id); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+		for _, i := range existing {
+			if len(i.Attributes) == len(rec.Attributes) && attrsMatch(i.Attributes, rec.Attributes) {
+				rec.ID = i.ID
+				rec.Created = i.Created
+				created = false
+				break
+			}
+		}
+	}
+
+	if created {
+		if rec.ID, err = o.srv.newItemID(o.id); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+	}
+
+	if err = o.srv.Storage.PutItem(o.id, rec); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+	if err = o.srv.touchCollection(o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	item = itemPath(o.id, rec.ID)
+
+	if created {
+		if err = o.srv.exportItem(o.id, rec.ID); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+		o.srv.emit(collectionPath(o.id), DbusInterfaceCollection, "ItemCreated", item)
+	} else {
+		o.srv.emit(collectionPath(o.id), DbusInterfaceCollection, "ItemChanged", item)
+	}
+
+	return
+}
+
+// properties implements propertyHolder.
+func (o *collectionObj) properties(iface string) (props map[string]dbus.Variant, dbusErr *dbus.Error) {
+
+	var err error
+	var c *CollectionRecord
+	var items []*ItemRecord
+	var paths []dbus.ObjectPath
+
+	if iface != DbusInterfaceCollection {
+		dbusErr = newDbusError(DbusErrInvalidArgs, "no such interface: "+iface)
+		return
+	}
+
+	if c, err = o.srv.Storage.GetCollection(o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+	if items, err = o.srv.Storage.Items(o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	paths = make([]dbus.ObjectPath, 0, len(items))
+	for _, i := range items {
+		paths = append(paths, itemPath(o.id, i.ID))
+	}
+
+	props = map[string]dbus.Variant{
+		"Items":    dbus.MakeVariant(paths),
+		"Label":    dbus.MakeVariant(c.Label),
+		"Locked":   dbus.MakeVariant(c.Locked),
+		"Created":  dbus.MakeVariant(uint64(c.Created.Unix())),
+		"Modified": dbus.MakeVariant(uint64(c.Modified.Unix())),
+	}
+
+	return
+}
+
+// setProperty implements propertyHolder. Only Label is writable.
+func (o *collectionObj) setProperty(iface, name string, value dbus.Variant) (dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var c *CollectionRecord
+	var label string
+
+	if iface != DbusInterfaceCollection || name != "Label" {
+		dbusErr = readOnlyOrUnknown(o, iface, name)
+		return
+	}
+
+	if label, ok = value.Value().(string); !ok {
+		dbusErr = newDbusError(DbusErrInvalidArgs, "Label must be a string")
+		return
+	}
+
+	if c, err = o.srv.Storage.GetCollection(o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	c.Label = label
+	c.Modified = time.Now()
+
+	if err = o.srv.Storage.PutCollection(c); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	o.srv.emit(dbus.ObjectPath(DbusPath), DbusInterfaceService, "CollectionChanged", collectionPath(o.id))
+
+	return
+}
@@ -0,0 +1,287 @@
+package server
+
+import (
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+/*
+	Libsecret/SecretService Dbus names.
+	These mirror the constants in the gosecret package; they are duplicated here so that gosecret's own tests
+	may use this package without an import cycle.
+*/
+const (
+	// DbusService is the well-known bus name a SecretService provider owns.
+	DbusService string = "org.freedesktop.secrets"
+	// DbusServiceBase is the base identifier used by interfaces.
+	DbusServiceBase string = "org.freedesktop.Secret"
+
+	// DbusInterfaceService is the Dbus interface exported at DbusPath.
+	DbusInterfaceService string = DbusServiceBase + ".Service"
+	// DbusInterfaceCollection is the Dbus interface exported for each collection.
+	DbusInterfaceCollection string = DbusServiceBase + ".Collection"
+	// DbusInterfaceItem is the Dbus interface exported for each item.
+	DbusInterfaceItem string = DbusServiceBase + ".Item"
+	// DbusInterfaceSession is the Dbus interface exported for each session.
+	DbusInterfaceSession string = DbusServiceBase + ".Session"
+	// DbusInterfacePrompt is the Dbus interface exported for each prompt.
+	DbusInterfacePrompt string = DbusServiceBase + ".Prompt"
+
+	// DbusInterfaceProperties is the standard Dbus interface for fetching/setting object properties.
+	DbusInterfaceProperties string = "org.freedesktop.DBus.Properties"
+	// DbusInterfaceIntrospectable is the standard Dbus introspection interface.
+	DbusInterfaceIntrospectable string = "org.freedesktop.DBus.Introspectable"
+
+	/*
+		DbusInterfaceGnomeKeyring is gnome-keyring's (unsupported) interface for unlocking/creating/changing
+		collections with a master password without a prompt. See Server.MasterPassword.
+	*/
+	DbusInterfaceGnomeKeyring string = "org.gnome.keyring.InternalUnsupportedGuiltRiddenInterface"
+
+	// DbusDefaultItemType is the item type used if a client does not specify one.
+	DbusDefaultItemType string = DbusServiceBase + ".Generic"
+)
+
+// Message bus names.
+const (
+	// DbusBusName is the bus name (and interface) of the message bus itself.
+	DbusBusName string = "org.freedesktop.DBus"
+	// DbusNameOwnerChanged is the signal emitted by the bus when a bus name changes owner (e.g. a client disconnects).
+	DbusNameOwnerChanged string = "NameOwnerChanged"
+)
+
+// Dbus paths.
+const (
+	// DbusPath is the path the Service object is exported at.
+	DbusPath string = "/org/freedesktop/secrets"
+	// DbusCollectionPrefix is the path prefix for collections; the collection ID is appended.
+	DbusCollectionPrefix string = DbusPath + "/collection/"
+	// DbusSessionPrefix is the path prefix for sessions.
+	DbusSessionPrefix string = DbusPath + "/session/"
+	// DbusPromptPrefix is the path prefix for prompts.
+	DbusPromptPrefix string = DbusPath + "/prompt/"
+)
+
+// Special values.
+var (
+	// DbusNoPrompt is returned in place of a prompt path if no prompt is necessary.
+	DbusNoPrompt dbus.ObjectPath = dbus.ObjectPath("/")
+)
+
+// Session algorithms.
+const (
+	// SessionAlgoPlain transfers secret values unencrypted.
+	SessionAlgoPlain string = "plain"
+	// SessionAlgoDH negotiates an AES-128 key via a 1024-bit MODP Diffie-Hellman exchange and HKDF-SHA256.
+	SessionAlgoDH string = "dh-ietf1024-sha256-aes128-cbc-pkcs7"
+)
+
+/*
+	Dbus error names returned to clients.
+	See https://specifications.freedesktop.org/secret-service/latest/ch15.html
+*/
+const (
+	DbusErrIsLocked        string = DbusServiceBase + ".Error.IsLocked"
+	DbusErrNoSession       string = DbusServiceBase + ".Error.NoSession"
+	DbusErrNoSuchObject    string = DbusServiceBase + ".Error.NoSuchObject"
+	DbusErrAlreadyExists   string = DbusServiceBase + ".Error.AlreadyExists"
+	DbusErrNotSupported    string = "org.freedesktop.DBus.Error.NotSupported"
+	DbusErrInvalidArgs     string = "org.freedesktop.DBus.Error.InvalidArgs"
+	DbusErrUnknownProperty string = "org.freedesktop.DBus.Error.UnknownProperty"
+	DbusErrPropReadOnly    string = "org.freedesktop.DBus.Error.PropertyReadOnly"
+	DbusErrFailed          string = "org.freedesktop.DBus.Error.Failed"
+	DbusErrAccessDenied    string = "org.freedesktop.DBus.Error.AccessDenied"
+	DbusErrUnknownMethod   string = "org.freedesktop.DBus.Error.UnknownMethod"
+)
+
+// PROMPTS
+
+// PromptKind is the operation a prompt was issued for (see PromptRequest).
+type PromptKind int
+
+const (
+	// PromptUnlock is issued by Service.Unlock for locked collections/items.
+	PromptUnlock PromptKind = iota
+	// PromptCreateCollection is issued by Service.CreateCollection if Server.PromptOnCreate is true.
+	PromptCreateCollection
+)
+
+// INTROSPECTION
+
+// Introspection data for the exported interfaces.
+var (
+	introspectService introspect.Interface = introspect.Interface{
+		Name: DbusInterfaceService,
+		Methods: []introspect.Method{
+			{Name: "OpenSession", Args: []introspect.Arg{
+				{Name: "algorithm", Type: "s", Direction: "in"},
+				{Name: "input", Type: "v", Direction: "in"},
+				{Name: "output", Type: "v", Direction: "out"},
+				{Name: "result", Type: "o", Direction: "out"},
+			}},
+			{Name: "CreateCollection", Args: []introspect.Arg{
+				{Name: "properties", Type: "a{sv}", Direction: "in"},
+				{Name: "alias", Type: "s", Direction: "in"},
+				{Name: "collection", Type: "o", Direction: "out"},
+				{Name: "prompt", Type: "o", Direction: "out"},
+			}},
+			{Name: "SearchItems", Args: []introspect.Arg{
+				{Name: "attributes", Type: "a{ss}", Direction: "in"},
+				{Name: "unlocked", Type: "ao", Direction: "out"},
+				{Name: "locked", Type: "ao", Direction: "out"},
+			}},
+			{Name: "Unlock", Args: []introspect.Arg{
+				{Name: "objects", Type: "ao", Direction: "in"},
+				{Name: "unlocked", Type: "ao", Direction: "out"},
+				{Name: "prompt", Type: "o", Direction: "out"},
+			}},
+			{Name: "Lock", Args: []introspect.Arg{
+				{Name: "objects", Type: "ao", Direction: "in"},
+				{Name: "locked", Type: "ao", Direction: "out"},
+				{Name: "Prompt", Type: "o", Direction: "out"},
+			}},
+			{Name: "GetSecrets", Args: []introspect.Arg{
+				{Name: "items", Type: "ao", Direction: "in"},
+				{Name: "session", Type: "o", Direction: "in"},
+				{Name: "secrets", Type: "a{o(oayays)}", Direction: "out"},
+			}},
+			{Name: "ReadAlias", Args: []introspect.Arg{
+				{Name: "name", Type: "s", Direction: "in"},
+				{Name: "collection", Type: "o", Direction: "out"},
+			}},
+			{Name: "SetAlias", Args: []introspect.Arg{
+				{Name: "name", Type: "s", Direction: "in"},
+				{Name: "collection", Type: "o", Direction: "in"},
+			}},
+		},
+		Signals: []introspect.Signal{
+			{Name: "CollectionCreated", Args: []introspect.Arg{{Name: "collection", Type: "o"}}},
+			{Name: "CollectionDeleted", Args: []introspect.Arg{{Name: "collection", Type: "o"}}},
+			{Name: "CollectionChanged", Args: []introspect.Arg{{Name: "collection", Type: "o"}}},
+		},
+		Properties: []introspect.Property{
+			{Name: "Collections", Type: "ao", Access: "read"},
+		},
+	}
+	introspectCollection introspect.Interface = introspect.Interface{
+		Name: DbusInterfaceCollection,
+		Methods: []introspect.Method{
+			{Name: "Delete", Args: []introspect.Arg{
+				{Name: "prompt", Type: "o", Direction: "out"},
+			}},
+			{Name: "SearchItems", Args: []introspect.Arg{
+				{Name: "attributes", Type: "a{ss}", Direction: "in"},
+				{Name: "results", Type: "ao", Direction: "out"},
+			}},
+			{Name: "CreateItem", Args: []introspect.Arg{
+				{Name: "properties", Type: "a{sv}", Direction: "in"},
+				{Name: "secret", Type: "(oayays)", Direction: "in"},
+				{Name: "replace", Type: "b", Direction: "in"},
+				{Name: "item", Type: "o", Direction: "out"},
+				{Name: "prompt", Type: "o", Direction: "out"},
+			}},
+		},
+		Signals: []introspect.Signal{
+			{Name: "ItemCreated", Args: []introspect.Arg{{Name: "item", Type: "o"}}},
+			{Name: "ItemDeleted", Args: []introspect.Arg{{Name: "item", Type: "o"}}},
+			{Name: "ItemChanged", Args: []introspect.Arg{{Name: "item", Type: "o"}}},
+		},
+		Properties: []introspect.Property{
+			{Name: "Items", Type: "ao", Access: "read"},
+			{Name: "Label", Type: "s", Access: "readwrite"},
+			{Name: "Locked", Type: "b", Access: "read"},
+			{Name: "Created", Type: "t", Access: "read"},
+			{Name: "Modified", Type: "t", Access: "read"},
+		},
+	}
+	introspectItemMethods []introspect.Method = []introspect.Method{
+		{Name: "Delete", Args: []introspect.Arg{
+			{Name: "Prompt", Type: "o", Direction: "out"},
+		}},
+		{Name: "GetSecret", Args: []introspect.Arg{
+			{Name: "session", Type: "o", Direction: "in"},
+			{Name: "secret", Type: "(oayays)", Direction: "out"},
+		}},
+		{Name: "SetSecret", Args: []introspect.Arg{
+			{Name: "secret", Type: "(oayays)", Direction: "in"},
+		}},
+	}
+	introspectItemLegacy introspect.Interface = introspect.Interface{
+		Name:    DbusInterfaceItem,
+		Methods: introspectItemMethods,
+		Properties: []introspect.Property{
+			{Name: "Locked", Type: "b", Access: "read"},
+			{Name: "Attributes", Type: "a{ss}", Access: "readwrite"},
+			{Name: "Label", Type: "s", Access: "readwrite"},
+			{Name: "Created", Type: "t", Access: "read"},
+			{Name: "Modified", Type: "t", Access: "read"},
+		},
+	}
+	introspectItem introspect.Interface = introspect.Interface{
+		Name:    DbusInterfaceItem,
+		Methods: introspectItemMethods,
+		Properties: append(
+			[]introspect.Property{{Name: "Type", Type: "s", Access: "readwrite"}},
+			introspectItemLegacy.Properties...,
+		),
+	}
+	introspectGnomeKeyring introspect.Interface = introspect.Interface{
+		Name: DbusInterfaceGnomeKeyring,
+		Methods: []introspect.Method{
+			{Name: "ChangeWithMasterPassword", Args: []introspect.Arg{
+				{Name: "collection", Type: "o", Direction: "in"},
+				{Name: "original", Type: "(oayays)", Direction: "in"},
+				{Name: "master", Type: "(oayays)", Direction: "in"},
+			}},
+			{Name: "CreateWithMasterPassword", Args: []introspect.Arg{
+				{Name: "attributes", Type: "a{sv}", Direction: "in"},
+				{Name: "master", Type: "(oayays)", Direction: "in"},
+				{Name: "collection", Type: "o", Direction: "out"},
+			}},
+			{Name: "UnlockWithMasterPassword", Args: []introspect.Arg{
+				{Name: "collection", Type: "o", Direction: "in"},
+				{Name: "master", Type: "(oayays)", Direction: "in"},
+			}},
+		},
+	}
+	introspectSession introspect.Interface = introspect.Interface{
+		Name: DbusInterfaceSession,
+		Methods: []introspect.Method{
+			{Name: "Close"},
+		},
+	}
+	introspectPrompt introspect.Interface = introspect.Interface{
+		Name: DbusInterfacePrompt,
+		Methods: []introspect.Method{
+			{Name: "Prompt", Args: []introspect.Arg{
+				{Name: "window-id", Type: "s", Direction: "in"},
+			}},
+			{Name: "Dismiss"},
+		},
+		Signals: []introspect.Signal{
+			{Name: "Completed", Args: []introspect.Arg{
+				{Name: "dismissed", Type: "b"},
+				{Name: "result", Type: "v"},
+			}},
+		},
+	}
+	introspectProperties introspect.Interface = introspect.Interface{
+		Name: DbusInterfaceProperties,
+		Methods: []introspect.Method{
+			{Name: "Get", Args: []introspect.Arg{
+				{Name: "interface", Type: "s", Direction: "in"},
+				{Name: "property", Type: "s", Direction: "in"},
+				{Name: "value", Type: "v", Direction: "out"},
+			}},
+			{Name: "GetAll", Args: []introspect.Arg{
+				{Name: "interface", Type: "s", Direction: "in"},
+				{Name: "props", Type: "a{sv}", Direction: "out"},
+			}},
+			{Name: "Set", Args: []introspect.Arg{
+				{Name: "interface", Type: "s", Direction: "in"},
+				{Name: "property", Type: "s", Direction: "in"},
+				{Name: "value", Type: "v", Direction: "in"},
+			}},
+		},
+	}
+)
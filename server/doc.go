@@ -0,0 +1,50 @@
+// See LICENSE in source root directory for copyright and licensing information.
+
+/*
+Package server is an in-process SecretService (org.freedesktop.secrets) provider.
+
+It exports the org.freedesktop.Secret.Service, Collection, Item, Session and Prompt interfaces
+(as well as org.freedesktop.DBus.Properties and org.freedesktop.DBus.Introspectable) on any dbus.Conn,
+backed by a pluggable Storage. A MemoryStorage is provided.
+
+It is primarily meant to be run on a private dbus-daemon for testing SecretService clients (such as gosecret itself)
+without a desktop keyring, but may also serve as a lightweight secrets daemon on minimal systems.
+
+Usage
+
+	var err error
+	var conn *dbus.Conn
+	var srv *server.Server
+
+	if conn, err = dbus.ConnectSessionBus(); err != nil {
+		// ...
+	}
+	if srv, err = server.New(conn, server.NewMemoryStorage()); err != nil {
+		// ...
+	}
+	defer srv.Close()
+
+	// Optional; decide the outcome of (e.g. unlock) prompts.
+	srv.PromptHandler = func(ctx context.Context, req *server.PromptRequest) (accept bool) {
+		return true
+	}
+
+	if err = srv.RequestName(server.DbusService); err != nil {
+		// ...
+	}
+
+Both the "plain" and "dh-ietf1024-sha256-aes128-cbc-pkcs7" session algorithms are supported.
+A client's sessions are closed (and its outstanding prompts dismissed) when it disconnects from the bus.
+
+Prompts
+
+Unlocking a locked collection (or an item in one) always requires a prompt, as does creating a collection if
+Server.PromptOnCreate is true. Each prompt is decided by Server.PromptHandler once the client calls Prompt.Prompt;
+if no PromptHandler is set, every prompt is accepted. Locking and deleting never require a prompt.
+
+Note that the Server performs no authentication of its own and Storage implementations are responsible for protecting
+stored secrets at rest; MemoryStorage does neither.
+
+This package deliberately does not import gosecret, so that gosecret's own tests may use it.
+*/
+package server
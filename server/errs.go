@@ -0,0 +1,26 @@
+package server
+
+import (
+	"errors"
+
+	"r00t2.io/gosecret/internal/sscrypto"
+)
+
+var (
+	// ErrNoConn gets triggered if a Server is created without a Dbus connection.
+	ErrNoConn error = errors.New("no valid dbus connection")
+	// ErrNoStorage gets triggered if a Server is created without a Storage.
+	ErrNoStorage error = errors.New("no storage backend specified")
+	// ErrNameTaken gets triggered if Server.RequestName can not become the primary owner of the bus name.
+	ErrNameTaken error = errors.New("the requested bus name is already owned")
+	// ErrClosed gets triggered if a closed Server is used.
+	ErrClosed error = errors.New("the server has been closed")
+	// ErrNotFound is returned by a Storage if the requested collection/item does not exist.
+	ErrNotFound error = errors.New("no such collection or item in storage")
+	// ErrBadPubKey gets triggered if a client's DH public key is missing or invalid.
+	ErrBadPubKey error = sscrypto.ErrBadPubKey
+	// ErrBadIV gets triggered if an encrypted secret's parameters do not contain a valid AES IV.
+	ErrBadIV error = sscrypto.ErrBadIV
+	// ErrBadPadding gets triggered if a decrypted secret value does not have valid PKCS#7 padding.
+	ErrBadPadding error = sscrypto.ErrBadPadding
+)
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"crypto/subtle"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	UnlockWithMasterPassword implements gnome-keyring's DbusInterfaceGnomeKeyring.UnlockWithMasterPassword.
+	It unlocks the collection without a prompt if master is its password.
+*/
+func (o *gnomeKeyringObj) UnlockWithMasterPassword(
+	sender dbus.Sender, collection dbus.ObjectPath, master wireSecret,
+) (dbusErr *dbus.Error) {
+
+	var err error
+	var collectionID string
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if collectionID, dbusErr = o.srv.checkMasterPassword(sender, collection, master); dbusErr != nil {
+		return
+	}
+
+	if err = o.srv.setCollectionLocked(collectionID, false); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	return
+}
+
+/*
+	CreateWithMasterPassword implements gnome-keyring's DbusInterfaceGnomeKeyring.CreateWithMasterPassword.
+	It creates an (unlocked) collection protected by master without a prompt.
+*/
+func (o *gnomeKeyringObj) CreateWithMasterPassword(
+	sender dbus.Sender, attributes map[string]dbus.Variant, master wireSecret,
+) (collection dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var label string
+	var password []byte
+	var collectionID string
+	var ssn *session
+	var v dbus.Variant
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	collection = DbusNoPrompt
+
+	if !o.srv.MasterPassword {
+		dbusErr = newDbusError(DbusErrUnknownMethod, "master passwords are not enabled")
+		return
+	}
+	if ssn, dbusErr = o.srv.getSession(sender, master.Session); dbusErr != nil {
+		return
+	}
+	if password, err = ssn.decodeSecret(master); err != nil {
+		dbusErr = newDbusError(DbusErrInvalidArgs, err.Error())
+		return
+	}
+
+	if v, ok = attributes[DbusInterfaceCollection+".Label"]; ok {
+		if label, ok = v.Value().(string); !ok {
+			dbusErr = newDbusError(DbusErrInvalidArgs, "the collection label must be a string")
+			return
+		}
+	}
+
+	if collection, dbusErr = o.srv.createCollection(label, ""); dbusErr != nil {
+		collection = DbusNoPrompt
+		return
+	}
+	collectionID, _, _ = o.srv.resolve(collection)
+	o.srv.passwords[collectionID] = password
+
+	return
+}
+
+/*
+	ChangeWithMasterPassword implements gnome-keyring's DbusInterfaceGnomeKeyring.ChangeWithMasterPassword.
+	It changes the collection's password from original to master without a prompt.
+*/
+func (o *gnomeKeyringObj) ChangeWithMasterPassword(
+	sender dbus.Sender, collection dbus.ObjectPath, original, master wireSecret,
+) (dbusErr *dbus.Error) {
+
+	var err error
+	var password []byte
+	var collectionID string
+	var ssn *session
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if collectionID, dbusErr = o.srv.checkMasterPassword(sender, collection, original); dbusErr != nil {
+		return
+	}
+	if ssn, dbusErr = o.srv.getSession(sender, master.Session); dbusErr != nil {
+		return
+	}
+	if password, err = ssn.decodeSecret(master); err != nil {
+		dbusErr = newDbusError(DbusErrInvalidArgs, err.Error())
+		return
+	}
+
+	o.srv.passwords[collectionID] = password
+
+	if err = o.srv.touchCollection(collectionID); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	return
+}
+
+/*
+	checkMasterPassword resolves collection and checks that password (decoded via its session) is its master password.
+	Collections without a master password accept any password. s.lock must be held.
+*/
+func (s *Server) checkMasterPassword(
+	sender dbus.Sender, collection dbus.ObjectPath, password wireSecret,
+) (collectionID string, dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var itemID string
+	var have []byte
+	var given []byte
+	var ssn *session
+
+	if !s.MasterPassword {
+		dbusErr = newDbusError(DbusErrUnknownMethod, "master passwords are not enabled")
+		return
+	}
+	if collectionID, itemID, ok = s.resolve(collection); !ok || itemID != "" {
+		dbusErr = newDbusError(DbusErrNoSuchObject, "no such collection: "+string(collection))
+		return
+	}
+	if ssn, dbusErr = s.getSession(sender, password.Session); dbusErr != nil {
+		return
+	}
+	if given, err = ssn.decodeSecret(password); err != nil {
+		dbusErr = newDbusError(DbusErrInvalidArgs, err.Error())
+		return
+	}
+
+	if have, ok = s.passwords[collectionID]; ok && subtle.ConstantTimeCompare(have, given) != 1 {
+		dbusErr = newDbusError(DbusErrAccessDenied, "the password was invalid")
+		return
+	}
+
+	return
+}
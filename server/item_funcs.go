@@ -0,0 +1,216 @@
+package server
+
+import (
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Delete implements org.freedesktop.Secret.Item.Delete. No prompt is required.
+func (o *itemObj) Delete() (promptPath dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var path dbus.ObjectPath = itemPath(o.collectionID, o.id)
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	promptPath = DbusNoPrompt
+
+	if err = o.srv.Storage.DeleteItem(o.collectionID, o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+	if err = o.srv.touchCollection(o.collectionID); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	o.srv.unexport(path)
+
+	o.srv.emit(collectionPath(o.collectionID), DbusInterfaceCollection, "ItemDeleted", path)
+
+	return
+}
+
+// GetSecret implements org.freedesktop.Secret.Item.GetSecret.
+func (o *itemObj) GetSecret(sender dbus.Sender, sessionPath dbus.ObjectPath) (secret wireSecret, dbusErr *dbus.Error) {
+
+	var err error
+	var ssn *session
+	var rec *ItemRecord
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if ssn, dbusErr = o.srv.getSession(sender, sessionPath); dbusErr != nil {
+		return
+	}
+	if o.srv.collectionLocked(o.collectionID) {
+		dbusErr = newDbusError(DbusErrIsLocked, "the item is locked")
+		return
+	}
+	if rec, err = o.srv.Storage.GetItem(o.collectionID, o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	if secret, err = ssn.encodeSecret(rec.Secret, rec.ContentType); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	return
+}
+
+// SetSecret implements org.freedesktop.Secret.Item.SetSecret.
+func (o *itemObj) SetSecret(sender dbus.Sender, secret wireSecret) (dbusErr *dbus.Error) {
+
+	var err error
+	var ssn *session
+	var rec *ItemRecord
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if ssn, dbusErr = o.srv.getSession(sender, secret.Session); dbusErr != nil {
+		return
+	}
+	if o.srv.collectionLocked(o.collectionID) {
+		dbusErr = newDbusError(DbusErrIsLocked, "the item is locked")
+		return
+	}
+	if rec, err = o.srv.Storage.GetItem(o.collectionID, o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	if rec.Secret, err = ssn.decodeSecret(secret); err != nil {
+		dbusErr = newDbusError(DbusErrInvalidArgs, err.Error())
+		return
+	}
+	rec.ContentType = secret.ContentType
+
+	dbusErr = o.save(rec)
+
+	return
+}
+
+// properties implements propertyHolder.
+func (o *itemObj) properties(iface string) (props map[string]dbus.Variant, dbusErr *dbus.Error) {
+
+	var err error
+	var rec *ItemRecord
+
+	if iface != DbusInterfaceItem {
+		dbusErr = newDbusError(DbusErrInvalidArgs, "no such interface: "+iface)
+		return
+	}
+
+	if rec, err = o.srv.Storage.GetItem(o.collectionID, o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	props = map[string]dbus.Variant{
+		"Locked":     dbus.MakeVariant(o.srv.collectionLocked(o.collectionID)),
+		"Attributes": dbus.MakeVariant(rec.Attributes),
+		"Label":      dbus.MakeVariant(rec.Label),
+		"Created":    dbus.MakeVariant(uint64(rec.Created.Unix())),
+		"Modified":   dbus.MakeVariant(uint64(rec.Modified.Unix())),
+	}
+	if !o.srv.Legacy {
+		props["Type"] = dbus.MakeVariant(rec.Type)
+	}
+
+	return
+}
+
+// setProperty implements propertyHolder. Attributes, Label and (if not Server.Legacy) Type are writable.
+func (o *itemObj) setProperty(iface, name string, value dbus.Variant) (dbusErr *dbus.Error) {
+
+	var err error
+	var rec *ItemRecord
+
+	if iface != DbusInterfaceItem ||
+		(name != "Attributes" && name != "Label" && (name != "Type" || o.srv.Legacy)) {
+		dbusErr = readOnlyOrUnknown(o, iface, name)
+		return
+	}
+
+	if o.srv.collectionLocked(o.collectionID) {
+		dbusErr = newDbusError(DbusErrIsLocked, "the item is locked")
+		return
+	}
+	if rec, err = o.srv.Storage.GetItem(o.collectionID, o.id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	if dbusErr = applyItemProperties(rec, map[string]dbus.Variant{DbusInterfaceItem + "." + name: value}); dbusErr != nil {
+		return
+	}
+
+	dbusErr = o.save(rec)
+
+	return
+}
+
+// save stores rec (updating its Modified time) and emits ItemChanged. s.lock must be held.
+func (o *itemObj) save(rec *ItemRecord) (dbusErr *dbus.Error) {
+
+	var err error
+	var path dbus.ObjectPath = itemPath(o.collectionID, o.id)
+
+	rec.Modified = time.Now()
+
+	if err = o.srv.Storage.PutItem(o.collectionID, rec); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+	if err = o.srv.touchCollection(o.collectionID); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	o.srv.emit(collectionPath(o.collectionID), DbusInterfaceCollection, "ItemChanged", path)
+
+	return
+}
+
+/*
+	applyItemProperties sets rec's Label, Attributes and/or Type from properties
+	(keyed by fully-qualified property name, as in Collection.CreateItem).
+	Unknown properties are ignored.
+*/
+func applyItemProperties(rec *ItemRecord, properties map[string]dbus.Variant) (dbusErr *dbus.Error) {
+
+	var ok bool
+	var v dbus.Variant
+	var attrs map[string]string
+
+	if v, ok = properties[DbusInterfaceItem+".Label"]; ok {
+		if rec.Label, ok = v.Value().(string); !ok {
+			dbusErr = newDbusError(DbusErrInvalidArgs, "Label must be a string")
+			return
+		}
+	}
+	if v, ok = properties[DbusInterfaceItem+".Type"]; ok {
+		if rec.Type, ok = v.Value().(string); !ok {
+			dbusErr = newDbusError(DbusErrInvalidArgs, "Type must be a string")
+			return
+		}
+	}
+	if v, ok = properties[DbusInterfaceItem+".Attributes"]; ok {
+		if attrs, ok = v.Value().(map[string]string); !ok {
+			dbusErr = newDbusError(DbusErrInvalidArgs, "Attributes must be a string dictionary")
+			return
+		}
+		rec.Attributes = make(map[string]string, len(attrs))
+		for k, a := range attrs {
+			rec.Attributes[k] = a
+		}
+	}
+
+	return
+}
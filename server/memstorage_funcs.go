@@ -0,0 +1,259 @@
+package server
+
+import (
+	"sort"
+	"strconv"
+)
+
+// NewMemoryStorage returns a new, empty MemoryStorage.
+func NewMemoryStorage() (storage *MemoryStorage) {
+
+	storage = &MemoryStorage{
+		collections: make(map[string]*CollectionRecord),
+		items:       make(map[string]map[string]*ItemRecord),
+		aliases:     make(map[string]string),
+	}
+
+	return
+}
+
+// Collections returns all collections, sorted by ID.
+func (m *MemoryStorage) Collections() (collections []*CollectionRecord, err error) {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	collections = make([]*CollectionRecord, 0, len(m.collections))
+	for _, c := range m.collections {
+		collections = append(collections, c.clone())
+	}
+
+	sort.Slice(collections, func(i, j int) bool { return collections[i].ID < collections[j].ID })
+
+	return
+}
+
+// GetCollection returns the collection with ID id.
+func (m *MemoryStorage) GetCollection(id string) (collection *CollectionRecord, err error) {
+
+	var ok bool
+	var c *CollectionRecord
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if c, ok = m.collections[id]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	collection = c.clone()
+
+	return
+}
+
+// PutCollection creates or replaces a collection.
+func (m *MemoryStorage) PutCollection(collection *CollectionRecord) (err error) {
+
+	var ok bool
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.collections[collection.ID] = collection.clone()
+	if _, ok = m.items[collection.ID]; !ok {
+		m.items[collection.ID] = make(map[string]*ItemRecord)
+	}
+
+	return
+}
+
+// DeleteCollection removes a collection, its items and any aliases pointing to it.
+func (m *MemoryStorage) DeleteCollection(id string) (err error) {
+
+	var ok bool
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok = m.collections[id]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	delete(m.collections, id)
+	delete(m.items, id)
+
+	for alias, target := range m.aliases {
+		if target == id {
+			delete(m.aliases, alias)
+		}
+	}
+
+	return
+}
+
+// Items returns all items in a collection, sorted by ID (numerically, where possible).
+func (m *MemoryStorage) Items(collectionID string) (items []*ItemRecord, err error) {
+
+	var ok bool
+	var coll map[string]*ItemRecord
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if coll, ok = m.items[collectionID]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	items = make([]*ItemRecord, 0, len(coll))
+	for _, i := range coll {
+		items = append(items, i.clone())
+	}
+
+	sort.Slice(items, func(i, j int) bool { return idLess(items[i].ID, items[j].ID) })
+
+	return
+}
+
+// GetItem returns a single item.
+func (m *MemoryStorage) GetItem(collectionID, itemID string) (item *ItemRecord, err error) {
+
+	var ok bool
+	var i *ItemRecord
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if i, ok = m.items[collectionID][itemID]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	item = i.clone()
+
+	return
+}
+
+// PutItem creates or replaces an item in an existing collection.
+func (m *MemoryStorage) PutItem(collectionID string, item *ItemRecord) (err error) {
+
+	var ok bool
+	var coll map[string]*ItemRecord
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if coll, ok = m.items[collectionID]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	coll[item.ID] = item.clone()
+
+	return
+}
+
+// DeleteItem removes an item.
+func (m *MemoryStorage) DeleteItem(collectionID, itemID string) (err error) {
+
+	var ok bool
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok = m.items[collectionID][itemID]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	delete(m.items[collectionID], itemID)
+
+	return
+}
+
+// Aliases returns a copy of the alias map.
+func (m *MemoryStorage) Aliases() (aliases map[string]string, err error) {
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	aliases = make(map[string]string, len(m.aliases))
+	for k, v := range m.aliases {
+		aliases[k] = v
+	}
+
+	return
+}
+
+// SetAlias points alias at a collection, or removes it if collectionID is empty.
+func (m *MemoryStorage) SetAlias(alias, collectionID string) (err error) {
+
+	var ok bool
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if collectionID == "" {
+		delete(m.aliases, alias)
+		return
+	}
+
+	if _, ok = m.collections[collectionID]; !ok {
+		err = ErrNotFound
+		return
+	}
+
+	m.aliases[alias] = collectionID
+
+	return
+}
+
+// clone returns a copy of c.
+func (c *CollectionRecord) clone() (out *CollectionRecord) {
+
+	var cp CollectionRecord = *c
+
+	out = &cp
+
+	return
+}
+
+// clone returns a deep copy of i.
+func (i *ItemRecord) clone() (out *ItemRecord) {
+
+	var cp ItemRecord = *i
+
+	if i.Attributes != nil {
+		cp.Attributes = make(map[string]string, len(i.Attributes))
+		for k, v := range i.Attributes {
+			cp.Attributes[k] = v
+		}
+	}
+	if i.Secret != nil {
+		cp.Secret = append([]byte{}, i.Secret...)
+	}
+
+	out = &cp
+
+	return
+}
+
+// idLess orders IDs numerically if both are numeric, and lexically otherwise.
+func idLess(a, b string) (less bool) {
+
+	var err error
+	var na, nb int
+
+	if na, err = strconv.Atoi(a); err == nil {
+		if nb, err = strconv.Atoi(b); err == nil {
+			less = na < nb
+			return
+		}
+	}
+
+	less = a < b
+
+	return
+}
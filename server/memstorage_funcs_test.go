@@ -0,0 +1,91 @@
+package server
+
+import (
+	"errors"
+	"testing"
+)
+
+/*
+	TestMemoryStorage tests the following internal functions/methods via nested calls:
+
+		NewMemoryStorage
+		MemoryStorage.PutCollection
+		MemoryStorage.GetCollection
+		MemoryStorage.PutItem
+		MemoryStorage.Items
+		MemoryStorage.GetItem
+		MemoryStorage.SetAlias
+		MemoryStorage.Aliases
+		MemoryStorage.DeleteItem
+		MemoryStorage.DeleteCollection
+
+*/
+func TestMemoryStorage(t *testing.T) {
+
+	var err error
+	var storage *MemoryStorage = NewMemoryStorage()
+	var coll *CollectionRecord
+	var item *ItemRecord
+	var items []*ItemRecord
+	var aliases map[string]string
+	var secret []byte = []byte("s3cr3t")
+
+	if err = storage.PutCollection(&CollectionRecord{ID: "login", Label: "Login"}); err != nil {
+		t.Fatalf("could not store collection: %v", err.Error())
+	}
+
+	for _, id := range []string{"10", "2", "1"} {
+		if err = storage.PutItem(
+			"login", &ItemRecord{ID: id, Attributes: map[string]string{"id": id}, Secret: secret},
+		); err != nil {
+			t.Fatalf("could not store item '%v': %v", id, err.Error())
+		}
+	}
+	if err = storage.PutItem("nonexistent", &ItemRecord{ID: "1"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("storing an item in a nonexistent collection returned '%v', expected ErrNotFound", err)
+	}
+
+	if items, err = storage.Items("login"); err != nil {
+		t.Fatalf("could not fetch items: %v", err.Error())
+	}
+	if len(items) != 3 || items[0].ID != "1" || items[1].ID != "2" || items[2].ID != "10" {
+		t.Errorf("items not returned in numeric ID order: %#v", items)
+	}
+
+	// Returned records must be copies.
+	secret[0] = 'X'
+	items[0].Attributes["id"] = "modified"
+	if item, err = storage.GetItem("login", "1"); err != nil {
+		t.Fatalf("could not fetch item: %v", err.Error())
+	}
+	if string(item.Secret) != "s3cr3t" || item.Attributes["id"] != "1" {
+		t.Errorf("stored item was modified via a caller's reference: %#v", item)
+	}
+
+	if err = storage.SetAlias("default", "login"); err != nil {
+		t.Fatalf("could not set alias: %v", err.Error())
+	}
+	if err = storage.SetAlias("other", "nonexistent"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("aliasing a nonexistent collection returned '%v', expected ErrNotFound", err)
+	}
+	if aliases, err = storage.Aliases(); err != nil || aliases["default"] != "login" {
+		t.Errorf("alias 'default' not set (err: %v): %#v", err, aliases)
+	}
+
+	if err = storage.DeleteItem("login", "2"); err != nil {
+		t.Errorf("could not delete item: %v", err.Error())
+	}
+	if _, err = storage.GetItem("login", "2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted item still exists (err: %v)", err)
+	}
+
+	if err = storage.DeleteCollection("login"); err != nil {
+		t.Fatalf("could not delete collection: %v", err.Error())
+	}
+	if coll, err = storage.GetCollection("login"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("deleted collection still exists: %#v (err: %v)", coll, err)
+	}
+	if aliases, err = storage.Aliases(); err != nil || len(aliases) != 0 {
+		t.Errorf("aliases for a deleted collection were not removed (err: %v): %#v", err, aliases)
+	}
+}
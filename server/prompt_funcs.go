@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	Prompt implements org.freedesktop.Secret.Prompt.Prompt.
+	The Server's PromptHandler is run in the background; the Completed signal is emitted once it returns.
+*/
+func (o *promptObj) Prompt(windowID string) (dbusErr *dbus.Error) {
+
+	var ok bool
+	var p *prompt
+	var ctx context.Context
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if p, ok = o.srv.prompts[o.path]; !ok {
+		dbusErr = newDbusError(DbusErrNoSuchObject, "no such prompt: "+string(o.path))
+		return
+	}
+	if p.started {
+		dbusErr = newDbusError(DbusErrFailed, "the prompt has already been started")
+		return
+	}
+
+	p.started = true
+	p.req.WindowID = windowID
+
+	ctx, p.cancel = context.WithCancel(context.Background())
+
+	go o.srv.runPrompt(ctx, p, o.srv.PromptHandler)
+
+	return
+}
+
+// Dismiss implements org.freedesktop.Secret.Prompt.Dismiss.
+func (o *promptObj) Dismiss() (dbusErr *dbus.Error) {
+
+	var ok bool
+	var p *prompt
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if p, ok = o.srv.prompts[o.path]; !ok {
+		dbusErr = newDbusError(DbusErrNoSuchObject, "no such prompt: "+string(o.path))
+		return
+	}
+
+	o.srv.finishPrompt(p, true)
+
+	return
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/introspect"
+)
+
+// Get implements org.freedesktop.DBus.Properties.Get.
+func (o *propsObj) Get(iface, name string) (value dbus.Variant, dbusErr *dbus.Error) {
+
+	var ok bool
+	var props map[string]dbus.Variant
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if props, dbusErr = o.holder.properties(iface); dbusErr != nil {
+		return
+	}
+
+	if value, ok = props[name]; !ok {
+		dbusErr = newDbusError(DbusErrUnknownProperty, "no such property: "+iface+"."+name)
+		return
+	}
+
+	return
+}
+
+// GetAll implements org.freedesktop.DBus.Properties.GetAll.
+func (o *propsObj) GetAll(iface string) (props map[string]dbus.Variant, dbusErr *dbus.Error) {
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	props, dbusErr = o.holder.properties(iface)
+
+	return
+}
+
+// Set implements org.freedesktop.DBus.Properties.Set.
+func (o *propsObj) Set(iface, name string, value dbus.Variant) (dbusErr *dbus.Error) {
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	dbusErr = o.holder.setProperty(iface, name, value)
+
+	return
+}
+
+// Introspect implements org.freedesktop.DBus.Introspectable.Introspect.
+func (o *introspectObj) Introspect() (data string, dbusErr *dbus.Error) {
+
+	var err error
+	var b []byte
+	var node introspect.Node
+	var children map[string]bool = make(map[string]bool)
+	var prefix string = strings.TrimSuffix(string(o.path), "/") + "/"
+	var rest string
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	node.Name = string(o.path)
+
+	// Read from Server.objects rather than a copy, as interfaces may be added to a path after export.
+	for _, iface := range o.srv.objects[o.path] {
+		switch iface {
+		case DbusInterfaceService:
+			node.Interfaces = append(node.Interfaces, introspectService)
+		case DbusInterfaceCollection:
+			node.Interfaces = append(node.Interfaces, introspectCollection)
+		case DbusInterfaceItem:
+			if o.srv.Legacy {
+				node.Interfaces = append(node.Interfaces, introspectItemLegacy)
+			} else {
+				node.Interfaces = append(node.Interfaces, introspectItem)
+			}
+		case DbusInterfaceGnomeKeyring:
+			if o.srv.MasterPassword {
+				node.Interfaces = append(node.Interfaces, introspectGnomeKeyring)
+			}
+		case DbusInterfaceSession:
+			node.Interfaces = append(node.Interfaces, introspectSession)
+		case DbusInterfacePrompt:
+			node.Interfaces = append(node.Interfaces, introspectPrompt)
+		case DbusInterfaceProperties:
+			node.Interfaces = append(node.Interfaces, introspectProperties)
+		case DbusInterfaceIntrospectable:
+			node.Interfaces = append(node.Interfaces, introspect.IntrospectData)
+		}
+	}
+
+	for path := range o.srv.objects {
+		if !strings.HasPrefix(string(path), prefix) {
+			continue
+		}
+		rest = strings.TrimPrefix(string(path), prefix)
+		children[strings.SplitN(rest, "/", 2)[0]] = true
+	}
+	for c := range children {
+		node.Children = append(node.Children, introspect.Node{Name: c})
+	}
+	sort.Slice(node.Children, func(i, j int) bool { return node.Children[i].Name < node.Children[j].Name })
+
+	if b, err = xml.Marshal(node); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	data = introspect.IntrospectDeclarationString + string(b)
+
+	return
+}
+
+/*
+	readOnlyOrUnknown returns the appropriate error for an attempt to set property name
+	(of Dbus interface iface) on holder that is not writable.
+*/
+func readOnlyOrUnknown(holder propertyHolder, iface, name string) (dbusErr *dbus.Error) {
+
+	var ok bool
+	var props map[string]dbus.Variant
+
+	if props, dbusErr = holder.properties(iface); dbusErr != nil {
+		return
+	}
+
+	if _, ok = props[name]; !ok {
+		dbusErr = newDbusError(DbusErrUnknownProperty, "no such property: "+iface+"."+name)
+		return
+	}
+
+	dbusErr = newDbusError(DbusErrPropReadOnly, "the property is read-only: "+iface+"."+name)
+
+	return
+}
@@ -0,0 +1,661 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	New returns a new Server exporting the SecretService objects for the contents of storage on conn.
+
+	The Server does not own a bus name until Server.RequestName is called;
+	clients may still address it directly by conn's unique name.
+*/
+func New(conn *dbus.Conn, storage Storage) (srv *Server, err error) {
+
+	var collections []*CollectionRecord
+	var items []*ItemRecord
+
+	if conn == nil {
+		err = ErrNoConn
+		return
+	}
+	if storage == nil {
+		err = ErrNoStorage
+		return
+	}
+
+	srv = &Server{
+		Conn:      conn,
+		Storage:   storage,
+		sessions:  make(map[dbus.ObjectPath]*session),
+		prompts:   make(map[dbus.ObjectPath]*prompt),
+		objects:   make(map[dbus.ObjectPath][]string),
+		passwords: make(map[string][]byte),
+	}
+
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+
+	if err = srv.export(dbus.ObjectPath(DbusPath), &serviceObj{srv: srv}, DbusInterfaceService); err != nil {
+		srv = nil
+		return
+	}
+	// Always exported (so Server.MasterPassword may be set after New), but only introspectable/usable if enabled.
+	if err = conn.Export(&gnomeKeyringObj{srv: srv}, dbus.ObjectPath(DbusPath), DbusInterfaceGnomeKeyring); err != nil {
+		srv = nil
+		return
+	}
+	srv.objects[dbus.ObjectPath(DbusPath)] = append(srv.objects[dbus.ObjectPath(DbusPath)], DbusInterfaceGnomeKeyring)
+
+	if collections, err = storage.Collections(); err != nil {
+		srv = nil
+		return
+	}
+	for _, c := range collections {
+		if err = srv.exportCollection(c.ID); err != nil {
+			srv = nil
+			return
+		}
+		if items, err = storage.Items(c.ID); err != nil {
+			srv = nil
+			return
+		}
+		for _, i := range items {
+			if err = srv.exportItem(c.ID, i.ID); err != nil {
+				srv = nil
+				return
+			}
+		}
+	}
+
+	if err = srv.watchClients(); err != nil {
+		srv = nil
+		return
+	}
+
+	return
+}
+
+/*
+	RequestName requests the bus name name (if empty, DbusService) for the Server.
+	If the name is already owned by another connection, err will be ErrNameTaken.
+*/
+func (s *Server) RequestName(name string) (err error) {
+
+	var reply dbus.RequestNameReply
+
+	if name == "" {
+		name = DbusService
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		err = ErrClosed
+		return
+	}
+
+	if reply, err = s.Conn.RequestName(name, dbus.NameFlagDoNotQueue); err != nil {
+		return
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner && reply != dbus.RequestNameReplyAlreadyOwner {
+		err = ErrNameTaken
+		return
+	}
+
+	s.name = name
+
+	return
+}
+
+/*
+	Close dismisses any outstanding prompts, closes all sessions, unexports all objects and releases the bus name
+	(if one was requested). The Dbus connection itself is left open.
+*/
+func (s *Server) Close() (err error) {
+
+	// The watcher takes s.lock itself, so it must be stopped first.
+	s.clientsCancel()
+	<-s.clientsDone
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+
+	for _, p := range s.prompts {
+		s.finishPrompt(p, true)
+	}
+	for _, ssn := range s.sessions {
+		s.closeSession(ssn)
+	}
+	for path := range s.objects {
+		s.unexport(path)
+	}
+
+	if s.name != "" {
+		if _, err = s.Conn.ReleaseName(s.name); err != nil {
+			return
+		}
+		s.name = ""
+	}
+
+	return
+}
+
+/*
+	watchClients watches the bus for clients disconnecting (NameOwnerChanged with an empty new owner
+	for a unique name) until Server.Close, closing their sessions and dismissing their prompts (see Server.dropClient),
+	as the SecretService specification requires.
+*/
+func (s *Server) watchClients() (err error) {
+
+	var ctx context.Context
+	var c chan *dbus.Signal
+	var match []dbus.MatchOption = []dbus.MatchOption{
+		dbus.WithMatchSender(DbusBusName),
+		dbus.WithMatchInterface(DbusBusName),
+		dbus.WithMatchMember(DbusNameOwnerChanged),
+	}
+
+	if err = s.Conn.AddMatchSignal(match...); err != nil {
+		return
+	}
+
+	// c is owned by the connection and never closed by us; removing it from the connection is enough.
+	c = make(chan *dbus.Signal, 16)
+	s.Conn.Signal(c)
+
+	ctx, s.clientsCancel = context.WithCancel(context.Background())
+	s.clientsDone = make(chan struct{})
+
+	go func() {
+
+		var ok bool
+		var sig *dbus.Signal
+		var name string
+		var owner string
+
+		defer close(s.clientsDone)
+		defer func() {
+			s.Conn.RemoveSignal(c)
+			_ = s.Conn.RemoveMatchSignal(match...)
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig, ok = <-c:
+				if !ok {
+					return
+				}
+				if sig.Name != DbusBusName+"."+DbusNameOwnerChanged || len(sig.Body) != 3 {
+					continue
+				}
+				name, _ = sig.Body[0].(string)
+				owner, _ = sig.Body[2].(string)
+				// Only unique names (":1.42") identify a client connection.
+				if !strings.HasPrefix(name, ":") || owner != "" {
+					continue
+				}
+				s.dropClient(name)
+			}
+		}
+	}()
+
+	return
+}
+
+// dropClient closes the sessions and dismisses the prompts of sender, which has left the bus.
+func (s *Server) dropClient(sender string) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	for _, ssn := range s.sessions {
+		if ssn.sender == sender {
+			s.closeSession(ssn)
+		}
+	}
+	for _, p := range s.prompts {
+		if p.sender == sender {
+			s.finishPrompt(p, true)
+		}
+	}
+
+	return
+}
+
+/*
+	export exports obj on path for each of ifaces, as well as the Properties (if obj is a propertyHolder)
+	and Introspectable interfaces. s.lock must be held.
+*/
+func (s *Server) export(path dbus.ObjectPath, obj interface{}, ifaces ...string) (err error) {
+
+	var ok bool
+	var holder propertyHolder
+	var exported []string = make([]string, 0, len(ifaces)+2)
+
+	for _, iface := range ifaces {
+		if err = s.Conn.Export(obj, path, iface); err != nil {
+			return
+		}
+		exported = append(exported, iface)
+	}
+
+	if holder, ok = obj.(propertyHolder); ok {
+		if err = s.Conn.Export(&propsObj{srv: s, holder: holder}, path, DbusInterfaceProperties); err != nil {
+			return
+		}
+		exported = append(exported, DbusInterfaceProperties)
+	}
+
+	if err = s.Conn.Export(
+		&introspectObj{srv: s, path: path}, path, DbusInterfaceIntrospectable,
+	); err != nil {
+		return
+	}
+	exported = append(exported, DbusInterfaceIntrospectable)
+
+	s.objects[path] = exported
+
+	return
+}
+
+// unexport removes everything exported on path. s.lock must be held.
+func (s *Server) unexport(path dbus.ObjectPath) {
+
+	for _, iface := range s.objects[path] {
+		_ = s.Conn.Export(nil, path, iface)
+	}
+
+	delete(s.objects, path)
+
+	return
+}
+
+// exportCollection exports the collection with ID id. s.lock must be held.
+func (s *Server) exportCollection(id string) (err error) {
+
+	err = s.export(collectionPath(id), &collectionObj{srv: s, id: id}, DbusInterfaceCollection)
+
+	return
+}
+
+// exportItem exports an item. s.lock must be held.
+func (s *Server) exportItem(collectionID, id string) (err error) {
+
+	err = s.export(itemPath(collectionID, id), &itemObj{srv: s, collectionID: collectionID, id: id}, DbusInterfaceItem)
+
+	return
+}
+
+/*
+	emit emits signal member of Dbus interface iface from path.
+	Errors are ignored; a failed signal emission is not the caller's problem.
+*/
+func (s *Server) emit(path dbus.ObjectPath, iface, member string, values ...interface{}) {
+
+	_ = s.Conn.Emit(path, iface+"."+member, values...)
+
+	return
+}
+
+// nextPath returns a new unique path under prefix. s.lock must be held.
+func (s *Server) nextPath(prefix string) (path dbus.ObjectPath) {
+
+	s.seq++
+
+	path = dbus.ObjectPath(prefix + "s" + strconv.FormatUint(s.seq, 10))
+
+	return
+}
+
+/*
+	createCollection creates, stores and exports a new collection labelled label, optionally pointing alias at it,
+	and emits CollectionCreated. s.lock must be held.
+*/
+func (s *Server) createCollection(label, alias string) (path dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var id string
+	var now time.Time = time.Now()
+
+	if id, err = s.newCollectionID(label); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	if err = s.Storage.PutCollection(&CollectionRecord{
+		ID:       id,
+		Label:    label,
+		Created:  now,
+		Modified: now,
+	}); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+	if alias != "" {
+		if err = s.Storage.SetAlias(alias, id); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+	}
+	if err = s.exportCollection(id); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	path = collectionPath(id)
+
+	s.emit(dbus.ObjectPath(DbusPath), DbusInterfaceService, "CollectionCreated", path)
+
+	return
+}
+
+/*
+	newCollectionID returns an unused collection ID derived from label
+	(lowercased, with anything that is not a valid Dbus path element character replaced with "_"),
+	as gnome-keyring does. s.lock must be held.
+*/
+func (s *Server) newCollectionID(label string) (id string, err error) {
+
+	var base string
+	var n int
+
+	base = strings.Map(
+		func(r rune) (out rune) {
+			switch {
+			case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+				out = r
+			case r >= 'A' && r <= 'Z':
+				out = r + ('a' - 'A')
+			default:
+				out = '_'
+			}
+			return
+		},
+		label,
+	)
+	if base == "" {
+		base = "collection"
+	}
+
+	id = base
+	for {
+		if _, err = s.Storage.GetCollection(id); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				err = nil
+				return
+			}
+			return
+		}
+		n++
+		id = fmt.Sprintf("%v_%d", base, n)
+	}
+}
+
+// newItemID returns an unused (numeric) item ID for the collection with ID collectionID. s.lock must be held.
+func (s *Server) newItemID(collectionID string) (id string, err error) {
+
+	var n int
+	var max int
+	var items []*ItemRecord
+
+	if items, err = s.Storage.Items(collectionID); err != nil {
+		return
+	}
+
+	for _, i := range items {
+		if n, err = strconv.Atoi(i.ID); err != nil {
+			err = nil
+			continue
+		}
+		if n > max {
+			max = n
+		}
+	}
+
+	id = strconv.Itoa(max + 1)
+
+	return
+}
+
+/*
+	resolve returns the collection ID (and item ID, if path is an item) for path.
+	ok is false if path is not an exported collection or item. s.lock must be held.
+*/
+func (s *Server) resolve(path dbus.ObjectPath) (collectionID, itemID string, ok bool) {
+
+	var rest string
+	var parts []string
+
+	if _, ok = s.objects[path]; !ok {
+		return
+	}
+
+	if !strings.HasPrefix(string(path), DbusCollectionPrefix) {
+		ok = false
+		return
+	}
+
+	rest = strings.TrimPrefix(string(path), DbusCollectionPrefix)
+	parts = strings.Split(rest, "/")
+
+	switch len(parts) {
+	case 1:
+		collectionID = parts[0]
+	case 2:
+		collectionID = parts[0]
+		itemID = parts[1]
+	default:
+		ok = false
+	}
+
+	return
+}
+
+// collectionLocked returns true if the collection with ID id is locked (or does not exist). s.lock must be held.
+func (s *Server) collectionLocked(id string) (locked bool) {
+
+	var err error
+	var c *CollectionRecord
+
+	if c, err = s.Storage.GetCollection(id); err != nil {
+		locked = true
+		return
+	}
+
+	locked = c.Locked
+
+	return
+}
+
+// setCollectionLocked locks/unlocks a collection and emits CollectionChanged if it changed. s.lock must be held.
+func (s *Server) setCollectionLocked(id string, locked bool) (err error) {
+
+	var c *CollectionRecord
+
+	if c, err = s.Storage.GetCollection(id); err != nil {
+		return
+	}
+	if c.Locked == locked {
+		return
+	}
+
+	c.Locked = locked
+	if err = s.Storage.PutCollection(c); err != nil {
+		return
+	}
+
+	s.emit(dbus.ObjectPath(DbusPath), DbusInterfaceService, "CollectionChanged", collectionPath(id))
+
+	return
+}
+
+// touchCollection updates a collection's Modified time. s.lock must be held.
+func (s *Server) touchCollection(id string) (err error) {
+
+	var c *CollectionRecord
+
+	if c, err = s.Storage.GetCollection(id); err != nil {
+		return
+	}
+
+	c.Modified = time.Now()
+
+	err = s.Storage.PutCollection(c)
+
+	return
+}
+
+// collectionPath returns the Dbus path for the collection with ID id.
+func collectionPath(id string) (path dbus.ObjectPath) {
+
+	path = dbus.ObjectPath(DbusCollectionPrefix + id)
+
+	return
+}
+
+// itemPath returns the Dbus path for an item.
+func itemPath(collectionID, id string) (path dbus.ObjectPath) {
+
+	path = dbus.ObjectPath(DbusCollectionPrefix + collectionID + "/" + id)
+
+	return
+}
+
+// attrsMatch returns true if every attribute in want is present (with the same value) in have.
+func attrsMatch(have, want map[string]string) (match bool) {
+
+	var ok bool
+	var v string
+
+	for k, w := range want {
+		if v, ok = have[k]; !ok || v != w {
+			return
+		}
+	}
+
+	match = true
+
+	return
+}
+
+// dbusError returns err as a *dbus.Error to return to the client.
+func dbusError(err error) (dbusErr *dbus.Error) {
+
+	if errors.Is(err, ErrNotFound) {
+		dbusErr = newDbusError(DbusErrNoSuchObject, err.Error())
+		return
+	}
+
+	dbusErr = newDbusError(DbusErrFailed, err.Error())
+
+	return
+}
+
+// newDbusError returns a *dbus.Error named name with message msg.
+func newDbusError(name, msg string) (dbusErr *dbus.Error) {
+
+	dbusErr = dbus.NewError(name, []interface{}{msg})
+
+	return
+}
+
+/*
+	newPrompt creates and exports a new prompt for sender that runs action if accepted.
+	The prompt is dismissed if sender disconnects. s.lock must be held.
+*/
+func (s *Server) newPrompt(
+	sender dbus.Sender, req *PromptRequest, action func() (result dbus.Variant),
+) (path dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var p *prompt
+
+	path = s.nextPath(DbusPromptPrefix)
+	req.Path = path
+
+	p = &prompt{
+		path:   path,
+		sender: string(sender),
+		req:    req,
+		action: action,
+	}
+
+	if err = s.export(path, &promptObj{srv: s, path: path}, DbusInterfacePrompt); err != nil {
+		path = ""
+		dbusErr = dbusError(err)
+		return
+	}
+
+	s.prompts[path] = p
+
+	return
+}
+
+/*
+	runPrompt calls the PromptHandler for p (without s.lock held) and completes p with the outcome.
+	It is run in its own goroutine by promptObj.Prompt.
+*/
+func (s *Server) runPrompt(ctx context.Context, p *prompt, handler PromptHandler) {
+
+	var accept bool = true
+
+	if handler != nil {
+		accept = handler(ctx, p.req)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.finishPrompt(p, !accept)
+
+	return
+}
+
+/*
+	finishPrompt completes (or dismisses) p, emits its Completed signal and unexports it.
+	It is a no-op if p has already completed. s.lock must be held.
+*/
+func (s *Server) finishPrompt(p *prompt, dismissed bool) {
+
+	var result dbus.Variant = dbus.MakeVariant("")
+
+	if p.done {
+		return
+	}
+	p.done = true
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+
+	if !dismissed && !s.closed {
+		result = p.action()
+	}
+
+	s.emit(p.path, DbusInterfacePrompt, "Completed", dismissed, result)
+
+	delete(s.prompts, p.path)
+	s.unexport(p.path)
+
+	return
+}
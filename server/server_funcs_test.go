@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/internal/testbus"
+)
+
+/*
+	startTestServer starts a private bus and a Server on it (owning DbusService), returning a client connection.
+	configure, if non-nil, is called before the bus name is requested.
+*/
+func startTestServer(t *testing.T, configure func(srv *Server)) (srv *Server, client *dbus.Conn) {
+
+	var err error
+	var bus *testbus.Bus
+	var conn *dbus.Conn
+
+	if bus, err = testbus.Start(); err != nil {
+		if errors.Is(err, testbus.ErrNoDaemon) {
+			t.Skipf("cannot start private bus: %v", err.Error())
+		}
+		t.Fatalf("cannot start private bus: %v", err.Error())
+	}
+	t.Cleanup(func() { _ = bus.Close() })
+
+	if conn, err = dbus.Connect(bus.Address); err != nil {
+		t.Fatalf("could not connect to private bus: %v", err.Error())
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	if srv, err = New(conn, NewMemoryStorage()); err != nil {
+		t.Fatalf("could not create server: %v", err.Error())
+	}
+	t.Cleanup(func() { _ = srv.Close() })
+
+	if configure != nil {
+		configure(srv)
+	}
+
+	if err = srv.RequestName(""); err != nil {
+		t.Fatalf("could not request bus name: %v", err.Error())
+	}
+
+	if client, err = dbus.Connect(bus.Address); err != nil {
+		t.Fatalf("could not connect to private bus: %v", err.Error())
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	return
+}
+
+/*
+	TestServer tests the following internal functions/methods via nested calls:
+
+		New
+		Server.RequestName
+		serviceObj.OpenSession
+		serviceObj.CreateCollection
+		serviceObj.Lock
+		serviceObj.Unlock
+		collectionObj.CreateItem
+		itemObj.GetSecret
+		propsObj.Get
+		introspectObj.Introspect
+		promptObj.Prompt
+		Server.Close
+
+*/
+func TestServer(t *testing.T) {
+
+	var err error
+	var client *dbus.Conn
+	var svc dbus.BusObject
+	var ssnPath dbus.ObjectPath
+	var collPath dbus.ObjectPath
+	var itemPath dbus.ObjectPath
+	var promptPath dbus.ObjectPath
+	var unlocked []dbus.ObjectPath
+	var output dbus.Variant
+	var secret wireSecret
+	var xml string
+	var dbusErr dbus.Error
+	var sigs chan *dbus.Signal = make(chan *dbus.Signal, 10)
+	var sig *dbus.Signal
+	var other *Server
+
+	_, client = startTestServer(t, func(srv *Server) {
+		srv.Legacy = true
+		// Every prompt is dismissed.
+		srv.PromptHandler = func(ctx context.Context, req *PromptRequest) (accept bool) {
+			return false
+		}
+	})
+	svc = client.Object(DbusService, dbus.ObjectPath(DbusPath))
+
+	// Only one provider may own the name.
+	if other, err = New(client, NewMemoryStorage()); err != nil {
+		t.Fatalf("could not create second server: %v", err.Error())
+	}
+	if err = other.RequestName(""); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("second server's RequestName returned '%v', expected ErrNameTaken", err)
+	}
+	_ = other.Close()
+
+	if err = svc.Call(
+		DbusInterfaceService+".OpenSession", 0, SessionAlgoPlain, dbus.MakeVariant(""),
+	).Store(&output, &ssnPath); err != nil {
+		t.Fatalf("OpenSession failed: %v", err.Error())
+	}
+	if err = svc.Call(
+		DbusInterfaceService+".CreateCollection", 0,
+		map[string]dbus.Variant{DbusInterfaceCollection + ".Label": dbus.MakeVariant("Test Collection")}, "",
+	).Store(&collPath, &promptPath); err != nil {
+		t.Fatalf("CreateCollection failed: %v", err.Error())
+	}
+	if collPath != collectionPath("test_collection") || promptPath != DbusNoPrompt {
+		t.Errorf("unexpected CreateCollection result: collection '%v', prompt '%v'", collPath, promptPath)
+	}
+
+	if err = client.Object(DbusService, collPath).Call(
+		DbusInterfaceCollection+".CreateItem", 0,
+		map[string]dbus.Variant{
+			DbusInterfaceItem + ".Label":      dbus.MakeVariant("Test Item"),
+			DbusInterfaceItem + ".Attributes": dbus.MakeVariant(map[string]string{"foo": "bar"}),
+		},
+		wireSecret{Session: ssnPath, Parameters: []byte{}, Value: []byte("s3cr3t"), ContentType: "text/plain"},
+		false,
+	).Store(&itemPath, &promptPath); err != nil {
+		t.Fatalf("CreateItem failed: %v", err.Error())
+	}
+	if err = client.Object(DbusService, itemPath).Call(
+		DbusInterfaceItem+".GetSecret", 0, ssnPath,
+	).Store(&secret); err != nil {
+		t.Fatalf("GetSecret failed: %v", err.Error())
+	}
+	if string(secret.Value) != "s3cr3t" {
+		t.Errorf("GetSecret returned '%v', expected 's3cr3t'", string(secret.Value))
+	}
+
+	// Legacy servers have no Type property.
+	if _, err = client.Object(DbusService, itemPath).GetProperty(DbusInterfaceItem + ".Type"); err == nil {
+		t.Errorf("Type property returned for a Legacy server")
+	} else if !errors.As(err, &dbusErr) || dbusErr.Name != DbusErrUnknownProperty {
+		t.Errorf("Type property returned '%v', expected %v", err, DbusErrUnknownProperty)
+	}
+	if err = client.Object(DbusService, itemPath).Call(
+		DbusInterfaceIntrospectable+".Introspect", 0,
+	).Store(&xml); err != nil {
+		t.Fatalf("Introspect failed: %v", err.Error())
+	}
+	if !strings.Contains(xml, DbusInterfaceItem) || strings.Contains(xml, `name="Type"`) {
+		t.Errorf("unexpected item introspection data for a Legacy server: %v", xml)
+	}
+
+	// A dismissed unlock prompt leaves the collection locked.
+	if err = svc.Call(DbusInterfaceService+".Lock", 0, []dbus.ObjectPath{collPath}).Err; err != nil {
+		t.Fatalf("Lock failed: %v", err.Error())
+	}
+	if err = svc.Call(
+		DbusInterfaceService+".Unlock", 0, []dbus.ObjectPath{collPath},
+	).Store(&unlocked, &promptPath); err != nil {
+		t.Fatalf("Unlock failed: %v", err.Error())
+	}
+	if len(unlocked) != 0 || promptPath == DbusNoPrompt {
+		t.Fatalf("Unlock of a locked collection did not require a prompt: %v, %v", unlocked, promptPath)
+	}
+
+	if err = client.AddMatchSignal(dbus.WithMatchObjectPath(promptPath)); err != nil {
+		t.Fatalf("could not add match rule: %v", err.Error())
+	}
+	client.Signal(sigs)
+	if err = client.Object(DbusService, promptPath).Call(DbusInterfacePrompt+".Prompt", 0, "").Err; err != nil {
+		t.Fatalf("Prompt failed: %v", err.Error())
+	}
+	select {
+	case sig = <-sigs:
+		if sig.Name != DbusInterfacePrompt+".Completed" || len(sig.Body) != 2 || sig.Body[0] != true {
+			t.Errorf("unexpected prompt signal: %#v", sig)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the prompt to complete")
+	}
+
+	if err = client.Object(DbusService, itemPath).Call(
+		DbusInterfaceItem+".GetSecret", 0, ssnPath,
+	).Store(&secret); !errors.As(err, &dbusErr) || dbusErr.Name != DbusErrIsLocked {
+		t.Errorf("GetSecret on a locked item returned '%v', expected %v", err, DbusErrIsLocked)
+	}
+}
+
+/*
+	TestServer_ClientDisconnect tests the following internal functions/methods via nested calls:
+
+		New
+			Server.watchClients
+				Server.dropClient
+					Server.closeSession
+					Server.finishPrompt
+
+*/
+func TestServer_ClientDisconnect(t *testing.T) {
+
+	var err error
+	var srv *Server
+	var client *dbus.Conn
+	var svc dbus.BusObject
+	var ssnPath dbus.ObjectPath
+	var collPath dbus.ObjectPath
+	var promptPath dbus.ObjectPath
+	var output dbus.Variant
+	var gone bool
+	var ssnExported bool
+	var promptExported bool
+	var deadline time.Time = time.Now().Add(5 * time.Second)
+
+	srv, client = startTestServer(t, func(srv *Server) {
+		srv.PromptOnCreate = true
+	})
+	svc = client.Object(DbusService, dbus.ObjectPath(DbusPath))
+
+	if err = svc.Call(
+		DbusInterfaceService+".OpenSession", 0, SessionAlgoPlain, dbus.MakeVariant(""),
+	).Store(&output, &ssnPath); err != nil {
+		t.Fatalf("OpenSession failed: %v", err.Error())
+	}
+	if err = svc.Call(
+		DbusInterfaceService+".CreateCollection", 0,
+		map[string]dbus.Variant{DbusInterfaceCollection + ".Label": dbus.MakeVariant("Abandoned")}, "",
+	).Store(&collPath, &promptPath); err != nil {
+		t.Fatalf("CreateCollection failed: %v", err.Error())
+	}
+	if promptPath == DbusNoPrompt {
+		t.Fatalf("CreateCollection did not return a prompt with PromptOnCreate set")
+	}
+
+	if err = client.Close(); err != nil {
+		t.Fatalf("could not disconnect client: %v", err.Error())
+	}
+
+	for !gone && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		srv.lock.Lock()
+		_, ssnExported = srv.objects[ssnPath]
+		_, promptExported = srv.objects[promptPath]
+		gone = len(srv.sessions) == 0 && len(srv.prompts) == 0 && !ssnExported && !promptExported
+		srv.lock.Unlock()
+	}
+	if !gone {
+		t.Errorf("session %v and/or prompt %v still present after the client disconnected", ssnPath, promptPath)
+	}
+}
@@ -0,0 +1,403 @@
+package server
+
+import (
+	"sort"
+
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/internal/sscrypto"
+)
+
+// OpenSession implements org.freedesktop.Secret.Service.OpenSession.
+func (o *serviceObj) OpenSession(
+	sender dbus.Sender, algorithm string, input dbus.Variant,
+) (output dbus.Variant, result dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var clientPub []byte
+	var kp *sscrypto.Keypair
+	var ssn *session
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	ssn = &session{
+		algo:   algorithm,
+		sender: string(sender),
+	}
+
+	switch algorithm {
+	case SessionAlgoPlain:
+		output = dbus.MakeVariant("")
+	case SessionAlgoDH:
+		if clientPub, ok = input.Value().([]byte); !ok {
+			dbusErr = newDbusError(DbusErrInvalidArgs, "the session input must be a byte array")
+			return
+		}
+		if kp, err = sscrypto.NewKeypair(); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+		if ssn.aesKey, err = kp.DeriveKey(clientPub); err != nil {
+			dbusErr = newDbusError(DbusErrInvalidArgs, err.Error())
+			return
+		}
+		output = dbus.MakeVariant(kp.PublicKey())
+	default:
+		dbusErr = newDbusError(DbusErrNotSupported, "unsupported session algorithm: "+algorithm)
+		return
+	}
+
+	ssn.path = o.srv.nextPath(DbusSessionPrefix)
+
+	if err = o.srv.export(ssn.path, &sessionObj{srv: o.srv, path: ssn.path}, DbusInterfaceSession); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+	o.srv.sessions[ssn.path] = ssn
+
+	result = ssn.path
+
+	return
+}
+
+/*
+	CreateCollection implements org.freedesktop.Secret.Service.CreateCollection.
+	If alias is non-empty and already points to a collection, that collection is returned instead.
+*/
+func (o *serviceObj) CreateCollection(
+	sender dbus.Sender, properties map[string]dbus.Variant, alias string,
+) (collection dbus.ObjectPath, promptPath dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var label string
+	var existing string
+	var aliases map[string]string
+	var v dbus.Variant
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	collection = DbusNoPrompt
+	promptPath = DbusNoPrompt
+
+	if v, ok = properties[DbusInterfaceCollection+".Label"]; ok {
+		if label, ok = v.Value().(string); !ok {
+			dbusErr = newDbusError(DbusErrInvalidArgs, "the collection label must be a string")
+			return
+		}
+	}
+
+	if alias != "" {
+		if aliases, err = o.srv.Storage.Aliases(); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+		if existing, ok = aliases[alias]; ok {
+			collection = collectionPath(existing)
+			return
+		}
+	}
+
+	if !o.srv.PromptOnCreate {
+		collection, dbusErr = o.srv.createCollection(label, alias)
+		return
+	}
+
+	promptPath, dbusErr = o.srv.newPrompt(
+		sender,
+		&PromptRequest{
+			Kind:  PromptCreateCollection,
+			Label: label,
+		},
+		func() (result dbus.Variant) {
+			var path dbus.ObjectPath
+			var createErr *dbus.Error
+			if path, createErr = o.srv.createCollection(label, alias); createErr != nil {
+				path = DbusNoPrompt
+			}
+			result = dbus.MakeVariant(path)
+			return
+		},
+	)
+
+	return
+}
+
+// SearchItems implements org.freedesktop.Secret.Service.SearchItems.
+func (o *serviceObj) SearchItems(attributes map[string]string) (unlocked, locked []dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var collections []*CollectionRecord
+	var items []*ItemRecord
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	unlocked = make([]dbus.ObjectPath, 0)
+	locked = make([]dbus.ObjectPath, 0)
+
+	if collections, err = o.srv.Storage.Collections(); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	for _, c := range collections {
+		if items, err = o.srv.Storage.Items(c.ID); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+		for _, i := range items {
+			if !attrsMatch(i.Attributes, attributes) {
+				continue
+			}
+			if c.Locked {
+				locked = append(locked, itemPath(c.ID, i.ID))
+			} else {
+				unlocked = append(unlocked, itemPath(c.ID, i.ID))
+			}
+		}
+	}
+
+	return
+}
+
+/*
+	Unlock implements org.freedesktop.Secret.Service.Unlock.
+	Objects that are already unlocked are returned immediately; the rest require a (PromptUnlock) prompt.
+	Unlocking an item unlocks its collection.
+*/
+func (o *serviceObj) Unlock(
+	sender dbus.Sender, objects []dbus.ObjectPath,
+) (unlocked []dbus.ObjectPath, promptPath dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var ok bool
+	var collectionID string
+	var pending []dbus.ObjectPath
+	var pendingIDs []string
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	unlocked = make([]dbus.ObjectPath, 0, len(objects))
+	promptPath = DbusNoPrompt
+
+	for _, path := range objects {
+		if collectionID, _, ok = o.srv.resolve(path); !ok {
+			continue
+		}
+		if !o.srv.collectionLocked(collectionID) {
+			unlocked = append(unlocked, path)
+			continue
+		}
+		pending = append(pending, path)
+		pendingIDs = append(pendingIDs, collectionID)
+	}
+
+	if len(pending) == 0 {
+		return
+	}
+
+	promptPath, dbusErr = o.srv.newPrompt(
+		sender,
+		&PromptRequest{
+			Kind:    PromptUnlock,
+			Objects: pending,
+		},
+		func() (result dbus.Variant) {
+			var done []dbus.ObjectPath = make([]dbus.ObjectPath, 0, len(pending))
+			for idx, id := range pendingIDs {
+				if o.srv.setCollectionLocked(id, false) == nil {
+					done = append(done, pending[idx])
+				}
+			}
+			result = dbus.MakeVariant(done)
+			return
+		},
+	)
+
+	return
+}
+
+/*
+	Lock implements org.freedesktop.Secret.Service.Lock.
+	Locking never requires a prompt. Locking an item locks its collection.
+*/
+func (o *serviceObj) Lock(objects []dbus.ObjectPath) (locked []dbus.ObjectPath, promptPath dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var collectionID string
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	locked = make([]dbus.ObjectPath, 0, len(objects))
+	promptPath = DbusNoPrompt
+
+	for _, path := range objects {
+		if collectionID, _, ok = o.srv.resolve(path); !ok {
+			continue
+		}
+		if err = o.srv.setCollectionLocked(collectionID, true); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+		locked = append(locked, path)
+	}
+
+	return
+}
+
+/*
+	GetSecrets implements org.freedesktop.Secret.Service.GetSecrets.
+	Locked (and unknown) items are omitted from the result.
+*/
+func (o *serviceObj) GetSecrets(
+	sender dbus.Sender, items []dbus.ObjectPath, sessionPath dbus.ObjectPath,
+) (secrets map[dbus.ObjectPath]wireSecret, dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var collectionID string
+	var itemID string
+	var ssn *session
+	var rec *ItemRecord
+	var secret wireSecret
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if ssn, dbusErr = o.srv.getSession(sender, sessionPath); dbusErr != nil {
+		return
+	}
+
+	secrets = make(map[dbus.ObjectPath]wireSecret)
+
+	for _, path := range items {
+		if collectionID, itemID, ok = o.srv.resolve(path); !ok || itemID == "" {
+			continue
+		}
+		if o.srv.collectionLocked(collectionID) {
+			continue
+		}
+		if rec, err = o.srv.Storage.GetItem(collectionID, itemID); err != nil {
+			continue
+		}
+		if secret, err = ssn.encodeSecret(rec.Secret, rec.ContentType); err != nil {
+			dbusErr = dbusError(err)
+			return
+		}
+		secrets[path] = secret
+	}
+
+	return
+}
+
+// ReadAlias implements org.freedesktop.Secret.Service.ReadAlias. If the alias does not exist, "/" is returned.
+func (o *serviceObj) ReadAlias(name string) (collection dbus.ObjectPath, dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var id string
+	var aliases map[string]string
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if aliases, err = o.srv.Storage.Aliases(); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	if id, ok = aliases[name]; !ok {
+		collection = DbusNoPrompt
+		return
+	}
+
+	collection = collectionPath(id)
+
+	return
+}
+
+// SetAlias implements org.freedesktop.Secret.Service.SetAlias. A collection of "/" removes the alias.
+func (o *serviceObj) SetAlias(name string, collection dbus.ObjectPath) (dbusErr *dbus.Error) {
+
+	var err error
+	var ok bool
+	var collectionID string
+	var itemID string
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if collection != DbusNoPrompt {
+		if collectionID, itemID, ok = o.srv.resolve(collection); !ok || itemID != "" {
+			dbusErr = newDbusError(DbusErrNoSuchObject, "no such collection: "+string(collection))
+			return
+		}
+	}
+
+	if err = o.srv.Storage.SetAlias(name, collectionID); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	return
+}
+
+// properties implements propertyHolder.
+func (o *serviceObj) properties(iface string) (props map[string]dbus.Variant, dbusErr *dbus.Error) {
+
+	var err error
+	var paths []dbus.ObjectPath
+	var collections []*CollectionRecord
+
+	if iface != DbusInterfaceService {
+		dbusErr = newDbusError(DbusErrInvalidArgs, "no such interface: "+iface)
+		return
+	}
+
+	if collections, err = o.srv.Storage.Collections(); err != nil {
+		dbusErr = dbusError(err)
+		return
+	}
+
+	paths = make([]dbus.ObjectPath, 0, len(collections))
+	for _, c := range collections {
+		paths = append(paths, collectionPath(c.ID))
+	}
+	sort.Slice(paths, func(i, j int) bool { return paths[i] < paths[j] })
+
+	props = map[string]dbus.Variant{
+		"Collections": dbus.MakeVariant(paths),
+	}
+
+	return
+}
+
+// setProperty implements propertyHolder. The Service has no writable properties.
+func (o *serviceObj) setProperty(iface, name string, value dbus.Variant) (dbusErr *dbus.Error) {
+
+	dbusErr = readOnlyOrUnknown(o, iface, name)
+
+	return
+}
+
+/*
+	getSession returns the open session at path, which must belong to sender.
+	s.lock must be held.
+*/
+func (s *Server) getSession(sender dbus.Sender, path dbus.ObjectPath) (ssn *session, dbusErr *dbus.Error) {
+
+	var ok bool
+
+	if ssn, ok = s.sessions[path]; !ok || ssn.sender != string(sender) {
+		ssn = nil
+		dbusErr = newDbusError(DbusErrNoSession, "no such session: "+string(path))
+		return
+	}
+
+	return
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/internal/sscrypto"
+)
+
+// Close implements org.freedesktop.Secret.Session.Close.
+func (o *sessionObj) Close(sender dbus.Sender) (dbusErr *dbus.Error) {
+
+	var ssn *session
+
+	o.srv.lock.Lock()
+	defer o.srv.lock.Unlock()
+
+	if ssn, dbusErr = o.srv.getSession(sender, o.path); dbusErr != nil {
+		return
+	}
+
+	o.srv.closeSession(ssn)
+
+	return
+}
+
+// closeSession removes and unexports ssn and wipes its key. s.lock must be held.
+func (s *Server) closeSession(ssn *session) {
+
+	for idx := range ssn.aesKey {
+		ssn.aesKey[idx] = 0
+	}
+
+	delete(s.sessions, ssn.path)
+	s.unexport(ssn.path)
+
+	return
+}
+
+// encodeSecret returns the wire representation of value for this session (encrypting it if necessary).
+func (s *session) encodeSecret(value []byte, contentType string) (secret wireSecret, err error) {
+
+	secret = wireSecret{
+		Session:     s.path,
+		Parameters:  []byte{},
+		Value:       value,
+		ContentType: contentType,
+	}
+
+	if secret.Value == nil {
+		secret.Value = []byte{}
+	}
+
+	if s.algo != SessionAlgoDH {
+		return
+	}
+
+	if secret.Parameters, secret.Value, err = sscrypto.Encrypt(s.aesKey, value); err != nil {
+		return
+	}
+
+	return
+}
+
+// decodeSecret returns the plaintext value of secret (decrypting it if necessary).
+func (s *session) decodeSecret(secret wireSecret) (value []byte, err error) {
+
+	if s.algo != SessionAlgoDH {
+		value = secret.Value
+		return
+	}
+
+	value, err = sscrypto.Decrypt(s.aesKey, secret.Parameters, secret.Value)
+
+	return
+}
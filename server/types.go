@@ -0,0 +1,241 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+/*
+	Server is an in-process SecretService provider.
+	It exports the org.freedesktop.Secret.Service, Collection, Item, Session and Prompt interfaces on Conn,
+	backed by Storage.
+
+	Set any of the exported configuration fields before calling Server.RequestName;
+	changing them while clients are connected is not safe.
+*/
+type Server struct {
+	// Conn is the Dbus connection the objects are exported on. It is not closed by Server.Close.
+	Conn *dbus.Conn
+	// Storage holds the collections, items and aliases.
+	Storage Storage
+	/*
+		PromptHandler is called to decide the outcome of each prompt (e.g. to authenticate an unlock).
+		If nil, every prompt is accepted; this is only suitable for testing.
+	*/
+	PromptHandler PromptHandler
+	/*
+		PromptOnCreate, if true, requires a prompt for Service.CreateCollection
+		(as with gnome-keyring, which prompts for the new collection's password).
+	*/
+	PromptOnCreate bool
+	/*
+		Legacy, if true, omits the Item "Type" property (as with older gnome-keyring/KeePassXC versions)
+		so clients' legacy handling can be exercised.
+	*/
+	Legacy bool
+	/*
+		MasterPassword, if true, offers gnome-keyring's DbusInterfaceGnomeKeyring interface for unlocking, creating
+		and changing the password of collections without a prompt. Collections created via the interface
+		require their password to be unlocked; other collections accept any password.
+	*/
+	MasterPassword bool
+	// lock protects everything below as well as access to Storage.
+	lock sync.Mutex
+	// name is the bus name requested by Server.RequestName, if any.
+	name string
+	// sessions are the open sessions, by path.
+	sessions map[dbus.ObjectPath]*session
+	// prompts are the outstanding prompts, by path.
+	prompts map[dbus.ObjectPath]*prompt
+	// objects are the exported paths and the interfaces exported on each.
+	objects map[dbus.ObjectPath][]string
+	// seq is used to generate session and prompt paths.
+	seq uint64
+	// passwords are the master passwords of collections (by ID) set via DbusInterfaceGnomeKeyring.
+	passwords map[string][]byte
+	// closed is true once Server.Close has been called.
+	closed bool
+	// clientsCancel stops the NameOwnerChanged watcher started by New (see Server.watchClients).
+	clientsCancel context.CancelFunc
+	// clientsDone is closed when the NameOwnerChanged watcher has stopped.
+	clientsDone chan struct{}
+}
+
+/*
+	PromptHandler decides whether a prompt is accepted (true) or dismissed (false).
+	ctx is cancelled if the client dismisses the prompt (or the Server is closed) before the handler returns.
+	It is called without any Server locks held, so it may block (e.g. to wait for user input).
+*/
+type PromptHandler func(ctx context.Context, req *PromptRequest) (accept bool)
+
+// PromptRequest describes a prompt to a PromptHandler.
+type PromptRequest struct {
+	// Kind is the operation the prompt is for.
+	Kind PromptKind
+	// Path is the prompt's Dbus path.
+	Path dbus.ObjectPath
+	// Objects are the collections/items to unlock (PromptUnlock).
+	Objects []dbus.ObjectPath
+	// Label is the label of the collection to create (PromptCreateCollection).
+	Label string
+	// WindowID is the window ID the client passed to Prompt.Prompt.
+	WindowID string
+}
+
+/*
+	Storage is a backend for a Server.
+	IDs are single Dbus path elements (i.e. [A-Za-z0-9_]); the Server assigns them.
+	Records passed to and returned from a Storage must not be retained/modified by the caller afterwards
+	(implementations should copy them).
+*/
+type Storage interface {
+	// Collections returns all stored collections.
+	Collections() (collections []*CollectionRecord, err error)
+	// GetCollection returns the collection with ID id, or ErrNotFound.
+	GetCollection(id string) (collection *CollectionRecord, err error)
+	// PutCollection creates or replaces a collection.
+	PutCollection(collection *CollectionRecord) (err error)
+	// DeleteCollection removes a collection, all of its items and any aliases pointing to it.
+	DeleteCollection(id string) (err error)
+	// Items returns all items in the collection with ID collectionID.
+	Items(collectionID string) (items []*ItemRecord, err error)
+	// GetItem returns a single item, or ErrNotFound.
+	GetItem(collectionID, itemID string) (item *ItemRecord, err error)
+	// PutItem creates or replaces an item in an (existing) collection.
+	PutItem(collectionID string, item *ItemRecord) (err error)
+	// DeleteItem removes an item.
+	DeleteItem(collectionID, itemID string) (err error)
+	// Aliases returns a map of alias name to collection ID.
+	Aliases() (aliases map[string]string, err error)
+	// SetAlias points alias at the collection with ID collectionID. An empty collectionID removes the alias.
+	SetAlias(alias, collectionID string) (err error)
+}
+
+// CollectionRecord is a stored collection.
+type CollectionRecord struct {
+	// ID is the last element of the collection's Dbus path.
+	ID string `json:"id"`
+	// Label is the collection's label.
+	Label string `json:"label"`
+	// Locked indicates if the collection (and all of its items) is locked.
+	Locked bool `json:"locked"`
+	// Created is when the collection was created.
+	Created time.Time `json:"created"`
+	// Modified is when the collection was last modified.
+	Modified time.Time `json:"modified"`
+}
+
+// ItemRecord is a stored item.
+type ItemRecord struct {
+	// ID is the last element of the item's Dbus path.
+	ID string `json:"id"`
+	// Label is the item's label.
+	Label string `json:"label"`
+	// Type is the item's type (schema).
+	Type string `json:"type"`
+	// Attributes are the item's (searchable) attributes.
+	Attributes map[string]string `json:"attributes"`
+	// Secret is the (plaintext) secret value.
+	Secret []byte `json:"secret"`
+	// ContentType is the secret's content type.
+	ContentType string `json:"content_type"`
+	// Created is when the item was created.
+	Created time.Time `json:"created"`
+	// Modified is when the item was last modified.
+	Modified time.Time `json:"modified"`
+}
+
+// MemoryStorage is a Storage that holds everything in memory.
+type MemoryStorage struct {
+	lock        sync.Mutex
+	collections map[string]*CollectionRecord
+	items       map[string]map[string]*ItemRecord
+	aliases     map[string]string
+}
+
+// wireSecret is the Dbus (oayays) representation of a secret.
+type wireSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// session is an open client session.
+type session struct {
+	path   dbus.ObjectPath
+	algo   string
+	sender string
+	aesKey []byte
+}
+
+// prompt is an outstanding prompt.
+type prompt struct {
+	path dbus.ObjectPath
+	// sender is the unique bus name of the client the prompt was created for.
+	sender string
+	req    *PromptRequest
+	// action performs the prompted operation (with the Server lock held) and returns the prompt result.
+	action  func() (result dbus.Variant)
+	cancel  context.CancelFunc
+	started bool
+	done    bool
+}
+
+// propertyHolder is implemented by the exported objects that have properties.
+type propertyHolder interface {
+	// properties returns all properties of the object for Dbus interface iface.
+	properties(iface string) (props map[string]dbus.Variant, dbusErr *dbus.Error)
+	// setProperty sets a single (writable) property.
+	setProperty(iface, name string, value dbus.Variant) (dbusErr *dbus.Error)
+}
+
+// serviceObj is exported at DbusPath.
+type serviceObj struct {
+	srv *Server
+}
+
+// gnomeKeyringObj is exported at DbusPath (for DbusInterfaceGnomeKeyring).
+type gnomeKeyringObj struct {
+	srv *Server
+}
+
+// collectionObj is exported for each collection.
+type collectionObj struct {
+	srv *Server
+	id  string
+}
+
+// itemObj is exported for each item.
+type itemObj struct {
+	srv          *Server
+	collectionID string
+	id           string
+}
+
+// sessionObj is exported for each session.
+type sessionObj struct {
+	srv  *Server
+	path dbus.ObjectPath
+}
+
+// promptObj is exported for each prompt.
+type promptObj struct {
+	srv  *Server
+	path dbus.ObjectPath
+}
+
+// propsObj implements org.freedesktop.DBus.Properties for a propertyHolder.
+type propsObj struct {
+	srv    *Server
+	holder propertyHolder
+}
+
+// introspectObj implements org.freedesktop.DBus.Introspectable for an exported path.
+type introspectObj struct {
+	srv  *Server
+	path dbus.ObjectPath
+}
@@ -1,19 +1,44 @@
 package gosecret
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/internal/sscrypto"
 	`r00t2.io/goutils/multierr`
 )
 
-// NewService returns a pointer to a new Service connection.
-func NewService() (service *Service, err error) {
+/*
+	NewService returns a pointer to a new Service connection.
+
+	By default, a new private connection to the session bus is opened (and closed by Service.Close),
+	the SecretService at DbusService is used, a default Session is opened (see Service.GetSession)
+	and the implementation's Capabilities are detected (which sets Service.Legacy; see Service.DetectCapabilities).
+	This can be changed with opts (see Option).
+
+	If FlagServiceLoadCollections is specified and loading a Collection fails, err MAY be a *multierr.MultiError;
+	service will still be returned in that case.
+*/
+func NewService(opts ...Option) (service *Service, err error) {
+
+	service, err = NewServiceContext(context.Background(), opts...)
 
+	return
+}
+
+// NewServiceContext is like NewService but uses ctx for the Dbus call(s) (e.g. opening the default Session).
+func NewServiceContext(ctx context.Context, opts ...Option) (service *Service, err error) {
+
+	var o serviceOpts = serviceOpts{
+		destination: DbusService,
+		parallelism: DefaultParallelism,
+	}
 	var svc Service = Service{
 		DbusObject: &DbusObject{
 			Conn: nil,
@@ -22,59 +47,150 @@ func NewService() (service *Service, err error) {
 		Session: nil,
 	}
 
-	if svc.Conn, err = dbus.SessionBus(); err != nil {
-		return
+	for _, opt := range opts {
+		if err = opt(&o); err != nil {
+			return
+		}
 	}
-	svc.Dbus = svc.Conn.Object(DbusService, dbus.ObjectPath(DbusPath))
 
-	if svc.Session, err = svc.GetSession(); err != nil {
-		return
+	switch {
+	case o.conn != nil:
+		svc.Conn = o.conn
+	case o.busAddress != "":
+		if svc.Conn, err = dbus.Connect(o.busAddress); err != nil {
+			return
+		}
+		svc.ownsConn = true
+	default:
+		if svc.Conn, err = dbus.ConnectSessionBus(); err != nil {
+			return
+		}
+		svc.ownsConn = true
+	}
+	svc.Dbus = svc.Conn.Object(o.destination, dbus.ObjectPath(DbusPath))
+
+	if o.waitProvider {
+		if err = waitForProvider(ctx, svc.Conn, o.destination, o.waitTimeout); err != nil {
+			if svc.ownsConn {
+				_ = svc.Conn.Close()
+			}
+			return
+		}
+	}
+	svc.Legacy = o.legacy
+	svc.Parallelism = o.parallelism
+	svc.lockedMemory = o.lockedMemory
+
+	if !o.noSession {
+		if o.sessionAlgo != "" {
+			svc.Session, _, err = svc.OpenSessionContext(ctx, o.sessionAlgo, "")
+		} else {
+			svc.Session, err = svc.GetSessionContext(ctx)
+		}
+		if err != nil {
+			if svc.ownsConn {
+				_ = svc.Conn.Close()
+			}
+			return
+		}
 	}
 
 	service = &svc
 
+	if o.reconnect {
+		if err = service.startReconnect(o.reconnectHandler); err != nil {
+			_ = service.CloseContext(ctx)
+			service = nil
+			return
+		}
+	}
+
+	// Detection is best-effort; not every implementation supports introspection.
+	if !o.legacy && !o.noDetect {
+		_, _ = service.DetectCapabilitiesContext(ctx)
+	}
+
+	if o.hasFlag(FlagServiceLoadCollections) {
+		service.LoadedCollections, err = service.CollectionsContext(ctx)
+	}
+
 	return
 }
 
 // Close cleanly closes a Service and all its underlying connections (e.g. Service.Session).
 func (s *Service) Close() (err error) {
 
-	if err = s.Session.Close(); err != nil {
-		return
+	err = s.CloseContext(context.Background())
+
+	return
+}
+
+/*
+	CloseContext is like Service.Close but uses ctx for the Dbus call(s).
+	The Dbus connection is only closed if it was opened by NewService (i.e. not if WithConn was used).
+*/
+func (s *Service) CloseContext(ctx context.Context) (err error) {
+
+	var ssn *Session
+
+	s.stopReconnect()
+
+	if ssn = s.CurrentSession(); ssn != nil {
+		if err = ssn.CloseContext(ctx); err != nil {
+			return
+		}
 	}
 
-	if err = s.Conn.Close(); err != nil {
-		return
+	if s.ownsConn {
+		if err = s.Conn.Close(); err != nil {
+			return
+		}
 	}
 
 	return
 }
 
 /*
-	Collections returns a slice of Collection items accessible to this Service.
+	Collections returns a slice of Collection items accessible to this Service, ordered by Dbus path
+	(see Collection.Items).
+	They are constructed concurrently (see Service.Parallelism).
 
 	err MAY be a *multierr.MultiError.
 */
 func (s *Service) Collections() (collections []*Collection, err error) {
 
+	collections, err = s.CollectionsContext(context.Background())
+
+	return
+}
+
+// CollectionsContext is like Service.Collections but uses ctx for the Dbus call(s).
+func (s *Service) CollectionsContext(ctx context.Context) (collections []*Collection, err error) {
+
 	var paths []dbus.ObjectPath
-	var variant dbus.Variant
-	var coll *Collection
+	var colls []*Collection
+	var errList []error
 	var errs *multierr.MultiError = multierr.NewMultiError()
 
-	if variant, err = s.Dbus.GetProperty(DbusServiceCollections); err != nil {
+	if err = s.withRetry(ctx, func() (err error) {
+		paths, err = pathsFromPathContext(ctx, s.Dbus, DbusServiceCollections)
+		return
+	}); err != nil {
 		return
 	}
+	paths = sortPaths(paths)
 
-	paths = variant.Value().([]dbus.ObjectPath)
+	colls = make([]*Collection, len(paths))
+	errList = parallelEach(ctx, len(paths), s.parallelism(), func(idx int) (err error) {
+		colls[idx], err = NewCollectionContext(ctx, s, paths[idx])
+		return
+	})
 
-	collections = make([]*Collection, 0)
+	collections = make([]*Collection, 0, len(paths))
 
-	for _, path := range paths {
-		coll = nil
-		if coll, err = NewCollection(s, path); err != nil {
-			errs.AddError(err)
-			err = nil
+	for idx, coll := range colls {
+		if errList[idx] != nil {
+			errs.AddError(errList[idx])
 			continue
 		}
 		collections = append(collections, coll)
@@ -93,19 +209,29 @@ func (s *Service) Collections() (collections []*Collection, err error) {
 */
 func (s *Service) CreateAliasedCollection(label, alias string) (collection *Collection, err error) {
 
+	collection, err = s.CreateAliasedCollectionContext(context.Background(), label, alias)
+
+	return
+}
+
+/*
+	CreateAliasedCollectionContext is like Service.CreateAliasedCollection but uses ctx for the Dbus call(s).
+	If a Prompt is required, it is passed (with ctx) to the Service.Prompter.
+*/
+func (s *Service) CreateAliasedCollectionContext(ctx context.Context, label, alias string) (collection *Collection, err error) {
+
 	var call *dbus.Call
 	var variant *dbus.Variant
 	var path dbus.ObjectPath
 	var promptPath dbus.ObjectPath
-	var prompt *Prompt
 	var props map[string]dbus.Variant = make(map[string]dbus.Variant)
 
 	props[DbusCollectionLabel] = dbus.MakeVariant(label)
 	props[DbusCollectionCreated] = dbus.MakeVariant(uint64(time.Now().Unix()))
 	props[DbusCollectionModified] = dbus.MakeVariant(uint64(time.Now().Unix()))
 
-	if call = s.Dbus.Call(
-		DbusServiceCreateCollection, 0, props, alias,
+	if call = callContext(
+		ctx, s.Dbus, DbusServiceCreateCollection, props, alias,
 	); call.Err != nil {
 		err = call.Err
 		return
@@ -116,15 +242,14 @@ func (s *Service) CreateAliasedCollection(label, alias string) (collection *Coll
 
 	if isPrompt(promptPath) {
 
-		prompt = NewPrompt(s.Conn, promptPath)
-		if variant, err = prompt.Prompt(); err != nil {
+		if variant, err = s.handlePrompt(ctx, promptPath); err != nil {
 			return
 		}
 
 		path = variant.Value().(dbus.ObjectPath)
 	}
 
-	collection, err = NewCollection(s, path)
+	collection, err = NewCollectionContext(ctx, s, path)
 
 	return
 }
@@ -140,6 +265,14 @@ func (s *Service) CreateCollection(label string) (collection *Collection, err er
 	return
 }
 
+// CreateCollectionContext is like Service.CreateCollection but uses ctx for the Dbus call(s).
+func (s *Service) CreateCollectionContext(ctx context.Context, label string) (collection *Collection, err error) {
+
+	collection, err = s.CreateAliasedCollectionContext(ctx, label, "")
+
+	return
+}
+
 /*
 	GetCollection returns a single Collection based on the name (name can also be an alias).
 	It's a helper function that avoids needing to make multiple calls in user code.
@@ -148,12 +281,20 @@ func (s *Service) CreateCollection(label string) (collection *Collection, err er
 */
 func (s *Service) GetCollection(name string) (c *Collection, err error) {
 
+	c, err = s.GetCollectionContext(context.Background(), name)
+
+	return
+}
+
+// GetCollectionContext is like Service.GetCollection but uses ctx for the Dbus call(s).
+func (s *Service) GetCollectionContext(ctx context.Context, name string) (c *Collection, err error) {
+
 	var errs *multierr.MultiError = multierr.NewMultiError()
 	var colls []*Collection
 	var pathName string
 
 	// First check for an alias.
-	if c, err = s.ReadAlias(name); err != nil && err != ErrDoesNotExist {
+	if c, err = s.ReadAliasContext(ctx, name); err != nil && err != ErrDoesNotExist {
 		c = nil
 		return
 	}
@@ -164,7 +305,7 @@ func (s *Service) GetCollection(name string) (c *Collection, err error) {
 	}
 
 	// We didn't get it by alias, so let's try by name...
-	if colls, err = s.Collections(); err != nil {
+	if colls, err = s.CollectionsContext(ctx); err != nil {
 		return
 	}
 	for _, i := range colls {
@@ -201,11 +342,20 @@ func (s *Service) GetCollection(name string) (c *Collection, err error) {
 	GetSecrets allows you to fetch values (Secret) from multiple Item object paths using this Service's Session.
 	An ErrMissingPaths will be returned for err if itemPaths is nil or empty.
 	The returned secrets is a map with itemPaths as the keys and their corresponding Secret as the value.
+	If Service.Session is encrypted, the Secret values are decrypted automatically.
 	If you know which Collection your desired Secret is in, it is recommended to iterate through Collection.Items instead
 	(as Secrets returned here may have missing functionality).
 */
 func (s *Service) GetSecrets(itemPaths ...dbus.ObjectPath) (secrets map[dbus.ObjectPath]*Secret, err error) {
 
+	secrets, err = s.GetSecretsContext(context.Background(), itemPaths...)
+
+	return
+}
+
+// GetSecretsContext is like Service.GetSecrets but uses ctx for the Dbus call.
+func (s *Service) GetSecretsContext(ctx context.Context, itemPaths ...dbus.ObjectPath) (secrets map[dbus.ObjectPath]*Secret, err error) {
+
 	/*
 		Results are in the form of a map with the value consisting of:
 		[]interface {}{
@@ -216,7 +366,7 @@ func (s *Service) GetSecrets(itemPaths ...dbus.ObjectPath) (secrets map[dbus.Obj
 		}
 	*/
 	var results map[dbus.ObjectPath][]interface{}
-	var call *dbus.Call
+	var ssn *Session
 
 	if itemPaths == nil || len(itemPaths) == 0 {
 		err = ErrMissingPaths
@@ -224,23 +374,26 @@ func (s *Service) GetSecrets(itemPaths ...dbus.ObjectPath) (secrets map[dbus.Obj
 	}
 
 	secrets = make(map[dbus.ObjectPath]*Secret, len(itemPaths))
-	results = make(map[dbus.ObjectPath][]interface{}, len(itemPaths))
 
 	// TODO: trigger a Service.Unlock for any locked items?
-	if call = s.Dbus.Call(
-		DbusServiceGetSecrets, 0, itemPaths, s.Session.Dbus.Path(),
-	); call.Err != nil {
-		err = call.Err
+	if err = s.withRetry(ctx, func() (err error) {
+		ssn = s.CurrentSession()
+		results = make(map[dbus.ObjectPath][]interface{}, len(itemPaths))
+		err = callContext(
+			ctx, s.Dbus, DbusServiceGetSecrets, itemPaths, ssn.Dbus.Path(),
+		).Store(&results)
 		return
-	}
-	if err = call.Store(&results); err != nil {
+	}); err != nil {
 		return
 	}
 
 	for p, r := range results {
 		secrets[p] = NewSecret(
-			s.Session, r[1].([]byte), r[2].([]byte), r[3].(string),
+			ssn, r[1].([]byte), r[2].([]byte), r[3].(string),
 		)
+		if err = ssn.decodeSecret(secrets[p]); err != nil {
+			return
+		}
 	}
 
 	return
@@ -249,81 +402,52 @@ func (s *Service) GetSecrets(itemPaths ...dbus.ObjectPath) (secrets map[dbus.Obj
 /*
 	GetSession returns a single Session.
 	It's a helper function that wraps Service.OpenSession.
+
+	An encrypted (SessionAlgoDH) Session is attempted first;
+	if the SecretService refuses that algorithm, a SessionAlgoPlain Session is opened instead.
 */
 func (s *Service) GetSession() (ssn *Session, err error) {
 
-	ssn, _, err = s.OpenSession("", "")
+	ssn, err = s.GetSessionContext(context.Background())
 
 	return
 }
 
-// Scrapping this idea for now; it would require introspection on a known Item path.
-/*
-	IsLegacy indicates with a decent likelihood of accuracy if this Service is
-	connected to a legacy spec Secret Service (true) or if the spec is current (false).
+// GetSessionContext is like Service.GetSession but uses ctx for the Dbus call(s).
+func (s *Service) GetSessionContext(ctx context.Context) (ssn *Session, err error) {
 
-	It also returns a confidence indicator as a float, which indicates how accurate
-	the guess (because it is a guess) may/is likely to be (as a percentage). For example,
-	if confidence is expressed as 0.25, the result of legacyAPI has a 25% of being accurate.
-*/
-/*
-func (s *Service) IsLegacy() (legacyAPI bool, confidence int) {
-
-	var maxCon int
-
-	// Test 1, property introspection on Item. We're looking for a Type property.
-	DbusInterfaceItem
+	if ssn, _, err = s.OpenSessionContext(ctx, SessionAlgoDH, ""); err != nil {
+		if !errIsNotSupported(err) {
+			return
+		}
+		ssn, _, err = s.OpenSessionContext(ctx, SessionAlgoPlain, "")
+	}
 
 	return
 }
-*/
-
-// Lock locks an Unlocked Collection or Item (LockableObject).
-func (s *Service) Lock(objects ...LockableObject) (err error) {
-
-	var call *dbus.Call
-	var toLock []dbus.ObjectPath
-	// We only use these as destinations.
-	var locked []dbus.ObjectPath
-	var prompt *Prompt
-	var promptPath dbus.ObjectPath
-
-	if objects == nil || len(objects) == 0 {
-		err = ErrMissingObj
-		return
-	}
 
-	toLock = make([]dbus.ObjectPath, len(objects))
-
-	for idx, o := range objects {
-		toLock[idx] = o.path()
-	}
+/*
+	Lock locks Unlocked Collection or Item objects (LockableObject).
 
-	if call = s.Dbus.Call(
-		DbusServiceLock, 0, toLock,
-	); call.Err != nil {
-		err = call.Err
-		return
-	}
-	if err = call.Store(&locked, &promptPath); err != nil {
-		return
-	}
+	result reports which objects were locked immediately, which required a Prompt and which the Prompt locked,
+	and which could not be locked. If any could not be, err is a *multierr.MultiError of their *LockFailure
+	(see ErrorIs and ErrorAs, e.g. ErrorIs(err, ErrPromptDismissed)); result is still returned.
+	The objects' lock state (e.g. Collection.IsLocked) is refreshed afterwards.
+*/
+func (s *Service) Lock(objects ...LockableObject) (result *LockResult, err error) {
 
-	if isPrompt(promptPath) {
+	result, err = s.LockContext(context.Background(), objects...)
 
-		prompt = NewPrompt(s.Conn, promptPath)
+	return
+}
 
-		if _, err = prompt.Prompt(); err != nil {
-			return
-		}
-	}
+/*
+	LockContext is like Service.Lock but uses ctx for the Dbus call(s).
+	If a Prompt is required, it is passed (with ctx) to the Service.Prompter.
+*/
+func (s *Service) LockContext(ctx context.Context, objects ...LockableObject) (result *LockResult, err error) {
 
-	// TODO: use channels and goroutines here.
-	for _, o := range objects {
-		if _, err = o.Locked(); err != nil {
-			return
-		}
-	}
+	result, err = s.setLocked(ctx, DbusServiceLock, true, objects)
 
 	return
 }
@@ -332,25 +456,46 @@ func (s *Service) Lock(objects ...LockableObject) (err error) {
 	OpenSession returns a pointer to a Session from the Service.
 	It's a convenience function around NewSession.
 	However, NewService attaches a Session by default at Service.Session so this is likely unnecessary.
+
+	algo should be one of SessionAlgoPlain (the default, if algo is empty) or SessionAlgoDH.
+	If algo is SessionAlgoDH, input is ignored; a keypair is generated and the key exchange
+	is performed automatically, and output will contain the SecretService's public key.
 */
 func (s *Service) OpenSession(algo, input string) (session *Session, output dbus.Variant, err error) {
 
+	session, output, err = s.OpenSessionContext(context.Background(), algo, input)
+
+	return
+}
+
+// OpenSessionContext is like Service.OpenSession but uses ctx for the Dbus call.
+func (s *Service) OpenSessionContext(ctx context.Context, algo, input string) (session *Session, output dbus.Variant, err error) {
+
 	var call *dbus.Call
 	var path dbus.ObjectPath
 	var inputVariant dbus.Variant
+	var kp *sscrypto.Keypair
+	var peerPub []byte
+	var ok bool
 
 	if strings.TrimSpace(algo) == "" {
-		algo = "plain"
+		algo = SessionAlgoPlain
 	}
 
-	inputVariant = dbus.MakeVariant(input)
+	switch algo {
+	case SessionAlgoDH:
+		if kp, err = sscrypto.NewKeypair(); err != nil {
+			return
+		}
+		inputVariant = dbus.MakeVariant(kp.PublicKey())
+	default:
+		inputVariant = dbus.MakeVariant(input)
+	}
 
-	// In *theory*, SecretService supports multiple "algorithms" for encryption in-transit, but I don't think it's implemented (yet)?
-	// TODO: confirm this.
 	// Possible flags are dbus.Flags consts: https://pkg.go.dev/github.com/godbus/dbus#Flags
 	// Oddly, there is no "None" flag. So it's explicitly specified as a null byte.
-	if call = s.Dbus.Call(
-		DbusServiceOpenSession, 0, algo, inputVariant,
+	if call = callContext(
+		ctx, s.Dbus, DbusServiceOpenSession, algo, inputVariant,
 	); call.Err != nil {
 		err = call.Err
 		return
@@ -359,7 +504,22 @@ func (s *Service) OpenSession(algo, input string) (session *Session, output dbus
 		return
 	}
 
-	session, err = NewSession(s, path)
+	if session, err = NewSession(s, path); err != nil {
+		return
+	}
+	session.Algorithm = algo
+
+	if kp != nil {
+		if peerPub, ok = output.Value().([]byte); !ok {
+			err = ErrBadPubKey
+			session = nil
+			return
+		}
+		if session.aesKey, err = kp.DeriveKey(peerPub); err != nil {
+			session = nil
+			return
+		}
+	}
 
 	return
 }
@@ -371,11 +531,19 @@ func (s *Service) OpenSession(algo, input string) (session *Session, output dbus
 */
 func (s *Service) ReadAlias(alias string) (collection *Collection, err error) {
 
+	collection, err = s.ReadAliasContext(context.Background(), alias)
+
+	return
+}
+
+// ReadAliasContext is like Service.ReadAlias but uses ctx for the Dbus call(s).
+func (s *Service) ReadAliasContext(ctx context.Context, alias string) (collection *Collection, err error) {
+
 	var call *dbus.Call
 	var objectPath dbus.ObjectPath
 
-	if call = s.Dbus.Call(
-		DbusServiceReadAlias, 0, alias,
+	if call = callContext(
+		ctx, s.Dbus, DbusServiceReadAlias, alias,
 	); call.Err != nil {
 		err = call.Err
 		return
@@ -394,7 +562,7 @@ func (s *Service) ReadAlias(alias string) (collection *Collection, err error) {
 		return
 	}
 
-	if collection, err = NewCollection(s, objectPath); err != nil {
+	if collection, err = NewCollectionContext(ctx, s, objectPath); err != nil {
 		return
 	}
 
@@ -404,7 +572,15 @@ func (s *Service) ReadAlias(alias string) (collection *Collection, err error) {
 // RemoveAlias is a thin wrapper around Service.SetAlias using the removal method specified there.
 func (s *Service) RemoveAlias(alias string) (err error) {
 
-	if err = s.SetAlias(alias, DbusRemoveAliasPath); err != nil {
+	err = s.RemoveAliasContext(context.Background(), alias)
+
+	return
+}
+
+// RemoveAliasContext is like Service.RemoveAlias but uses ctx for the Dbus call(s).
+func (s *Service) RemoveAliasContext(ctx context.Context, alias string) (err error) {
+
+	if err = s.SetAliasContext(ctx, alias, DbusRemoveAliasPath); err != nil {
 		return
 	}
 
@@ -413,20 +589,32 @@ func (s *Service) RemoveAlias(alias string) (err error) {
 
 /*
 	SearchItems searches all Collection objects and returns all matches based on the map of attributes.
+	Secrets are not fetched unless FlagItemLoadSecret is specified in flags (see NewItem);
+	it is never applied to lockedItems.
+	Both unlockedItems and lockedItems are ordered by Dbus path (see Collection.Items); they are constructed concurrently (see Service.Parallelism).
 
 	err MAY be a *multierr.MultiError.
 */
-func (s *Service) SearchItems(attributes map[string]string) (unlockedItems []*Item, lockedItems []*Item, err error) {
+func (s *Service) SearchItems(
+	attributes map[string]string, flags ...ItemInitFlag,
+) (unlockedItems []*Item, lockedItems []*Item, err error) {
+
+	unlockedItems, lockedItems, err = s.SearchItemsContext(context.Background(), attributes, flags...)
+
+	return
+}
+
+// SearchItemsContext is like Service.SearchItems but uses ctx for the Dbus call(s).
+func (s *Service) SearchItemsContext(
+	ctx context.Context, attributes map[string]string, flags ...ItemInitFlag,
+) (unlockedItems []*Item, lockedItems []*Item, err error) {
 
-	var call *dbus.Call
 	var locked []dbus.ObjectPath
 	var unlocked []dbus.ObjectPath
 	var collectionObjs []*Collection
 	var collections map[dbus.ObjectPath]*Collection = make(map[dbus.ObjectPath]*Collection, 0)
 	var ok bool
 	var c *Collection
-	var cPath dbus.ObjectPath
-	var item *Item
 	var errs *multierr.MultiError = multierr.NewMultiError()
 
 	if attributes == nil || len(attributes) == 0 {
@@ -434,18 +622,16 @@ func (s *Service) SearchItems(attributes map[string]string) (unlockedItems []*It
 		return
 	}
 
-	if call = s.Dbus.Call(
-		DbusServiceSearchItems, 0, attributes,
-	); call.Err != nil {
-	}
-	if err = call.Store(&unlocked, &locked); err != nil {
+	if err = s.withRetry(ctx, func() (err error) {
+		err = callContext(
+			ctx, s.Dbus, DbusServiceSearchItems, attributes,
+		).Store(&unlocked, &locked)
+		return
+	}); err != nil {
 		return
 	}
 
-	lockedItems = make([]*Item, 0)
-	unlockedItems = make([]*Item, 0)
-
-	if collectionObjs, err = s.Collections(); err != nil {
+	if collectionObjs, err = s.CollectionsContext(ctx); err != nil {
 		return
 	}
 
@@ -455,53 +641,8 @@ func (s *Service) SearchItems(attributes map[string]string) (unlockedItems []*It
 		}
 	}
 
-	// Locked items
-	for _, i := range locked {
-
-		item = nil
-		cPath = dbus.ObjectPath(filepath.Dir(string(i)))
-
-		if c, ok = collections[cPath]; !ok {
-			errs.AddError(errors.New(fmt.Sprintf(
-				"could not find matching Collection for locked item %v", string(i),
-			)))
-			continue
-		}
-
-		if item, err = NewItem(c, i); err != nil {
-			errs.AddError(errors.New(fmt.Sprintf(
-				"could not create Item for locked item %v; error follows", string(i),
-			)))
-			errs.AddError(err)
-			err = nil
-			continue
-		}
-		lockedItems = append(lockedItems, item)
-	}
-
-	// Unlocked items
-	for _, i := range unlocked {
-
-		item = nil
-		cPath = dbus.ObjectPath(filepath.Dir(string(i)))
-
-		if c, ok = collections[cPath]; !ok {
-			errs.AddError(errors.New(fmt.Sprintf(
-				"could not find matching Collection for unlocked item %v", string(i),
-			)))
-			continue
-		}
-
-		if item, err = NewItem(c, i); err != nil {
-			errs.AddError(errors.New(fmt.Sprintf(
-				"could not create Item for unlocked item %v; error follows", string(i),
-			)))
-			errs.AddError(err)
-			err = nil
-			continue
-		}
-		unlockedItems = append(unlockedItems, item)
-	}
+	lockedItems = s.itemsFromPaths(ctx, collections, sortPaths(locked), "locked", errs)
+	unlockedItems = s.itemsFromPaths(ctx, collections, sortPaths(unlocked), "unlocked", errs, flags...)
 
 	if !errs.IsEmpty() {
 		err = errs
@@ -517,15 +658,23 @@ func (s *Service) SearchItems(attributes map[string]string) (unlockedItems []*It
 */
 func (s *Service) SetAlias(alias string, objectPath dbus.ObjectPath) (err error) {
 
+	err = s.SetAliasContext(context.Background(), alias, objectPath)
+
+	return
+}
+
+// SetAliasContext is like Service.SetAlias but uses ctx for the Dbus call(s).
+func (s *Service) SetAliasContext(ctx context.Context, alias string, objectPath dbus.ObjectPath) (err error) {
+
 	var call *dbus.Call
 	var collection *Collection
 
-	if collection, err = s.GetCollection(alias); err != nil {
+	if collection, err = s.GetCollectionContext(ctx, alias); err != nil {
 		return
 	}
 
-	if call = s.Dbus.Call(
-		DbusServiceSetAlias, 0, alias, objectPath,
+	if call = callContext(
+		ctx, s.Dbus, DbusServiceSetAlias, alias, objectPath,
 	); call.Err != nil {
 		err = call.Err
 		return
@@ -540,51 +689,370 @@ func (s *Service) SetAlias(alias string, objectPath dbus.ObjectPath) (err error)
 	return
 }
 
-// Unlock unlocks a locked Collection or Item (LockableObject).
-func (s *Service) Unlock(objects ...LockableObject) (err error) {
+/*
+	Unlock unlocks locked Collection or Item objects (LockableObject).
+
+	result reports which objects were unlocked immediately, which required a Prompt and which the Prompt unlocked,
+	and which could not be unlocked. If any could not be, err is a *multierr.MultiError of their *LockFailure
+	(see ErrorIs and ErrorAs, e.g. ErrorIs(err, ErrPromptDismissed)); result is still returned.
+	The objects' lock state (e.g. Collection.IsLocked) is refreshed afterwards.
+*/
+func (s *Service) Unlock(objects ...LockableObject) (result *LockResult, err error) {
+
+	result, err = s.UnlockContext(context.Background(), objects...)
+
+	return
+}
+
+/*
+	UnlockContext is like Service.Unlock but uses ctx for the Dbus call(s).
+	If a Prompt is required, it is passed (with ctx) to the Service.Prompter.
+	If ctx is cancelled while waiting on the Prompt, the partial result is returned along with ctx.Err().
+*/
+func (s *Service) UnlockContext(ctx context.Context, objects ...LockableObject) (result *LockResult, err error) {
+
+	result, err = s.setLocked(ctx, DbusServiceUnlock, false, objects)
+
+	return
+}
+
+/*
+	Watch subscribes to the SecretService's change signals for all Collection objects (and the Item objects in them)
+	and delivers them on events until ctx is cancelled (or the Dbus connection is closed), after which events is closed.
+
+	The Collection and Item referenced by each event are resolved (using ctx) before delivery where possible;
+	see WatchEvent. Events should be consumed promptly; signals received while events is full are held until it is drained.
+*/
+func (s *Service) Watch(ctx context.Context) (events <-chan *WatchEvent, err error) {
+
+	var dest string = s.Dbus.Destination()
+	var matches [][]dbus.MatchOption = [][]dbus.MatchOption{
+		{
+			dbus.WithMatchSender(dest),
+			dbus.WithMatchObjectPath(s.path()),
+			dbus.WithMatchInterface(DbusInterfaceService),
+		},
+		{
+			dbus.WithMatchSender(dest),
+			dbus.WithMatchPathNamespace(dbus.ObjectPath(strings.TrimSuffix(DbusNewCollectionPath, "/"))),
+			dbus.WithMatchInterface(DbusInterfaceCollection),
+		},
+	}
+
+	events, err = watchSignals(
+		ctx, s.Conn, matches,
+		func(sig *dbus.Signal) (evt *WatchEvent) {
+			evt = s.eventFromSignal(ctx, sig)
+			return
+		},
+	)
+
+	return
+}
+
+// eventFromSignal converts a change signal received by Service.Watch to a *WatchEvent (or nil if it is not relevant).
+func (s *Service) eventFromSignal(ctx context.Context, sig *dbus.Signal) (evt *WatchEvent) {
+
+	var ok bool
+	var err error
+	var evtType EventType
+	var objPath dbus.ObjectPath
+	var coll *Collection
+
+	if evtType, ok = eventTypeFromSignal(sig.Name); !ok {
+		return
+	}
+	if objPath, ok = signalPath(sig); !ok {
+		return
+	}
+
+	switch evtType {
+	case EventCollectionCreated, EventCollectionDeleted, EventCollectionChanged:
+		if sig.Path != s.path() {
+			return
+		}
+		evt = &WatchEvent{
+			Type: evtType,
+			Path: objPath,
+		}
+		if evtType != EventCollectionDeleted {
+			if evt.Collection, evt.Err = NewCollectionContext(ctx, s, objPath); evt.Err != nil {
+				evt.Collection = nil
+			}
+		}
+	default:
+		// Signals from alias paths (e.g. /org/freedesktop/secrets/aliases/default) are duplicates; skip them.
+		if !strings.HasPrefix(string(sig.Path), DbusNewCollectionPath) {
+			return
+		}
+		if coll, err = s.collectionForEvent(ctx, sig.Path); err != nil {
+			evt = &WatchEvent{
+				Type: evtType,
+				Path: objPath,
+				Err:  err,
+			}
+			return
+		}
+		evt = coll.itemEvent(ctx, evtType, objPath)
+	}
+
+	return
+}
+
+// collectionForEvent resolves the Collection at collPath for an Item event. coll is nil if err is not.
+func (s *Service) collectionForEvent(ctx context.Context, collPath dbus.ObjectPath) (coll *Collection, err error) {
+
+	if coll, err = NewCollectionContext(ctx, s, collPath); err != nil {
+		coll = nil
+		return
+	}
+
+	return
+}
+
+// newPrompt returns a new Prompt for path with this Service's WindowID applied.
+func (s *Service) newPrompt(path dbus.ObjectPath) (prompt *Prompt) {
+
+	prompt = NewPrompt(s.Conn, path)
+	prompt.Dbus = s.Conn.Object(s.Dbus.Destination(), path)
+	prompt.WindowID = s.WindowID
 
+	return
+}
+
+/*
+	handlePrompt hands the Prompt at path to the Service.Prompter (or an InteractivePrompter, if none is set).
+	A Prompter returning neither a result nor an error is treated as a dismissal.
+*/
+func (s *Service) handlePrompt(ctx context.Context, path dbus.ObjectPath) (result *dbus.Variant, err error) {
+
+	var prompter Prompter = s.Prompter
+
+	if prompter == nil {
+		prompter = InteractivePrompter{}
+	}
+
+	if result, err = prompter.HandlePrompt(ctx, s.newPrompt(path)); err != nil {
+		return
+	}
+	if result == nil {
+		err = ErrPromptDismissed
+		return
+	}
+
+	return
+}
+
+/*
+	setLocked performs a Lock or Unlock (method) of objects, handles the Prompt (if any)
+	and builds the LockResult. lock is the requested state.
+*/
+func (s *Service) setLocked(
+	ctx context.Context, method string, lock bool, objects []LockableObject,
+) (result *LockResult, err error) {
+
+	var ok bool
 	var call *dbus.Call
-	var toUnlock []dbus.ObjectPath
-	// We only use these as destinations.
-	var unlocked []dbus.ObjectPath
-	var prompt *Prompt
-	var resultPath dbus.ObjectPath
+	var paths []dbus.ObjectPath
+	var changed []dbus.ObjectPath
+	var affected []dbus.ObjectPath
+	var promptPath dbus.ObjectPath
+	var promptResult *dbus.Variant
+	var changedSet map[dbus.ObjectPath]bool
+	var affectedSet map[dbus.ObjectPath]bool
+	var states []bool
+	var errList []error
+	var failures []*LockFailure
+	var errs *multierr.MultiError = multierr.NewMultiError()
 
 	if objects == nil || len(objects) == 0 {
 		err = ErrMissingObj
 		return
 	}
 
-	toUnlock = make([]dbus.ObjectPath, len(objects))
-
+	paths = make([]dbus.ObjectPath, len(objects))
 	for idx, o := range objects {
-		toUnlock[idx] = o.path()
+		paths[idx] = o.path()
 	}
 
-	if call = s.Dbus.Call(
-		DbusServiceUnlock, 0, toUnlock,
-	); call.Err != nil {
+	if call = callContext(ctx, s.Dbus, method, paths); call.Err != nil {
 		err = call.Err
 		return
 	}
-	if err = call.Store(&unlocked, &resultPath); err != nil {
+	if err = call.Store(&changed, &promptPath); err != nil {
 		return
 	}
 
-	if isPrompt(resultPath) {
+	result = new(LockResult)
+	failures = make([]*LockFailure, len(objects))
+	changedSet = make(map[dbus.ObjectPath]bool, len(changed))
+	for _, p := range changed {
+		changedSet[p] = true
+	}
 
-		prompt = NewPrompt(s.Conn, resultPath)
+	for idx, o := range objects {
+		switch {
+		case changedSet[paths[idx]]:
+			result.Immediate = append(result.Immediate, o)
+		case isPrompt(promptPath):
+			result.Prompted = append(result.Prompted, o)
+		default:
+			failures[idx] = &LockFailure{Object: o, Err: ErrLockStateUnchanged}
+		}
+	}
 
-		if _, err = prompt.Prompt(); err != nil {
-			return
+	if isPrompt(promptPath) {
+		if promptResult, err = s.handlePrompt(ctx, promptPath); err != nil {
+			if !errors.Is(err, ErrPromptDismissed) {
+				return
+			}
+			err = nil
+			for idx, o := range objects {
+				if !changedSet[paths[idx]] && failures[idx] == nil {
+					failures[idx] = &LockFailure{Object: o, Err: ErrPromptDismissed}
+				}
+			}
+		} else if affected, ok = promptResult.Value().([]dbus.ObjectPath); ok {
+			affectedSet = make(map[dbus.ObjectPath]bool, len(affected))
+			for _, p := range affected {
+				affectedSet[p] = true
+			}
+		}
+	}
+
+	// Refresh the lock state of every object; this also determines the Prompt's effect if it wasn't reported.
+	states = make([]bool, len(objects))
+	errList = parallelEach(ctx, len(objects), s.parallelism(), func(idx int) (err error) {
+		states[idx], err = objects[idx].LockedContext(ctx)
+		return
+	})
+
+	for idx, o := range objects {
+		switch {
+		case failures[idx] != nil:
+		case errList[idx] != nil:
+			failures[idx] = &LockFailure{Object: o, Err: errList[idx]}
+		case changedSet[paths[idx]]:
+		case affectedSet != nil && affectedSet[paths[idx]], affectedSet == nil && states[idx] == lock:
+			result.PromptAffected = append(result.PromptAffected, o)
+		default:
+			failures[idx] = &LockFailure{Object: o, Err: ErrLockStateUnchanged}
+		}
+	}
+
+	for _, f := range failures {
+		if f != nil {
+			result.Failed = append(result.Failed, f)
+			errs.AddError(f)
 		}
 	}
+	if !errs.IsEmpty() {
+		err = errs
+	}
 
-	// TODO: use channels and goroutines here.
-	for _, o := range objects {
-		if _, err = o.Locked(); err != nil {
+	return
+}
+
+/*
+	itemsFromPaths constructs (concurrently; see Service.Parallelism) the Item objects at paths, which must be
+	in one of collections. kind is used in error messages ("locked"/"unlocked").
+	items keeps the order of paths; any errors are added to errs (in the same order).
+*/
+func (s *Service) itemsFromPaths(
+	ctx context.Context, collections map[dbus.ObjectPath]*Collection, paths []dbus.ObjectPath, kind string,
+	errs *multierr.MultiError, flags ...ItemInitFlag,
+) (items []*Item) {
+
+	var built []*Item
+	var errList []error
+	var createFailed []bool
+
+	built = make([]*Item, len(paths))
+	createFailed = make([]bool, len(paths))
+	errList = parallelEach(ctx, len(paths), s.parallelism(), func(idx int) (err error) {
+
+		var ok bool
+		var c *Collection
+
+		if c, ok = collections[dbus.ObjectPath(filepath.Dir(string(paths[idx])))]; !ok {
+			err = errors.New(fmt.Sprintf(
+				"could not find matching Collection for %v item %v", kind, string(paths[idx]),
+			))
 			return
 		}
+		if built[idx], err = NewItemContext(ctx, c, paths[idx], flags...); err != nil {
+			createFailed[idx] = true
+		}
+
+		return
+	})
+
+	items = make([]*Item, 0, len(paths))
+
+	for idx, item := range built {
+		if errList[idx] != nil {
+			if createFailed[idx] {
+				errs.AddError(errors.New(fmt.Sprintf(
+					"could not create Item for %v item %v; error follows", kind, string(paths[idx]),
+				)))
+			}
+			errs.AddError(errList[idx])
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return
+}
+
+/*
+	CurrentSession returns Service.Session (which may be replaced by reconnecting; see WithReconnect).
+	Unlike reading the field directly, it is safe to call concurrently with a reconnect.
+*/
+func (s *Service) CurrentSession() (ssn *Session) {
+
+	s.lock.RLock()
+	ssn = s.Session
+	s.lock.RUnlock()
+
+	return
+}
+
+// upsertLock returns the lock serializing Collection.Upsert for the Collection at path.
+func (s *Service) upsertLock(path dbus.ObjectPath) (lock *sync.Mutex) {
+
+	var ok bool
+
+	s.upsertLocksLock.Lock()
+	defer s.upsertLocksLock.Unlock()
+
+	if s.upsertLocks == nil {
+		s.upsertLocks = make(map[dbus.ObjectPath]*sync.Mutex)
+	}
+	if lock, ok = s.upsertLocks[path]; !ok {
+		lock = new(sync.Mutex)
+		s.upsertLocks[path] = lock
+	}
+
+	return
+}
+
+// IsLegacy returns Service.Legacy. Unlike reading the field directly, it is safe to call concurrently with Service.DetectCapabilities.
+func (s *Service) IsLegacy() (legacy bool) {
+
+	s.lock.RLock()
+	legacy = s.Legacy
+	s.lock.RUnlock()
+
+	return
+}
+
+// parallelism returns Service.Parallelism, or DefaultParallelism if it is not set.
+func (s *Service) parallelism() (n int) {
+
+	n = s.Parallelism
+	if n < 1 {
+		n = DefaultParallelism
 	}
 
 	return
@@ -1,9 +1,10 @@
 package gosecret
 
 import (
-	"fmt"
+	"context"
 
 	"github.com/godbus/dbus/v5"
+	"r00t2.io/gosecret/internal/sscrypto"
 )
 
 // I'm still not 100% certain what Sessions are used for, aside from getting Secrets from Items.
@@ -11,6 +12,9 @@ import (
 /*
 	NewSession returns a pointer to a new Session based on a Service and a dbus.ObjectPath.
 	You will almost always want to use Service.GetSession or Service.OpenSession instead.
+
+	The returned Session is assumed to be a SessionAlgoPlain Session;
+	Service.OpenSession handles setting up the key for SessionAlgoDH Sessions.
 */
 func NewSession(service *Service, path dbus.ObjectPath) (session *Session, err error) {
 
@@ -22,9 +26,10 @@ func NewSession(service *Service, path dbus.ObjectPath) (session *Session, err e
 		DbusObject: &DbusObject{
 			Conn: service.Conn,
 		},
-		service: service,
+		Algorithm: SessionAlgoPlain,
+		service:   service,
 	}
-	ssn.Dbus = ssn.Conn.Object(DbusInterfaceSession, path)
+	ssn.Dbus = ssn.Conn.Object(service.Dbus.Destination(), path)
 
 	session = &ssn
 
@@ -34,25 +39,111 @@ func NewSession(service *Service, path dbus.ObjectPath) (session *Session, err e
 // Close cleanly closes a Session.
 func (s *Session) Close() (err error) {
 
-	var call *dbus.Call
-
-	if call = s.Dbus.Call(
-		DbusSessionClose, 0,
-	); call.Err != nil {
-		/*
-			I... still haven't 100% figured out why this happens, but the session DOES seem to close...?
-			PRs or input welcome.
-			TODO: figure out why this error gets triggered.
-		*/
-		if call.Err.Error() != fmt.Sprintf("The name %v was not provided by any .service files", DbusInterfaceSession) {
-			err = call.Err
+	err = s.CloseContext(context.Background())
+
+	return
+}
+
+// CloseContext is like Session.Close but uses ctx for the Dbus call.
+func (s *Session) CloseContext(ctx context.Context) (err error) {
+
+	if err = callContext(
+		ctx, s.Dbus, DbusSessionClose,
+	).Err; err != nil {
+		return
+	}
+
+	return
+}
+
+/*
+	decodeSecret decrypts secret.Value in place (if this Session is encrypted), moves it into locked memory
+	(if the Service uses WithLockedMemory) and attaches this Session to secret.
+	secret.Parameters is left as received.
+*/
+func (s *Session) decodeSecret(secret *Secret) (err error) {
+
+	var plain []byte
+	var locked []byte
+
+	secret.session = s
+
+	if s.Algorithm == SessionAlgoDH {
+		if plain, err = s.decrypt(secret.Parameters, secret.Value); err != nil {
+			return
+		}
+		secret.Value = plain
+	}
+
+	if s.service != nil && s.service.lockedMemory {
+		if locked, err = allocLocked(len(secret.Value)); err != nil {
+			secret.Value.Wipe()
 			return
 		}
+		copy(locked, secret.Value)
+		secret.Value.Wipe()
+		secret.Value = locked
 	}
 
 	return
 }
 
+/*
+	encodeSecret returns a copy of secret suitable for sending over Dbus via this Session.
+	If this Session is encrypted, the copy's Value is encrypted with a new random IV (which is placed in Parameters).
+	secret itself is not modified.
+*/
+func (s *Session) encodeSecret(secret *Secret) (wire *Secret, err error) {
+
+	wire = &Secret{
+		Session:     s.path(),
+		Parameters:  secret.Parameters,
+		Value:       secret.Value,
+		ContentType: secret.ContentType,
+	}
+
+	if wire.Parameters == nil {
+		wire.Parameters = []byte{}
+	}
+
+	if s.Algorithm != SessionAlgoDH {
+		return
+	}
+
+	if wire.Parameters, wire.Value, err = s.encrypt(secret.Value); err != nil {
+		wire = nil
+		return
+	}
+
+	return
+}
+
+// encrypt encrypts plain using AES-128-CBC (with PKCS#7 padding) with the Session key and a random IV.
+func (s *Session) encrypt(plain []byte) (iv, ciphertext []byte, err error) {
+
+	if s.aesKey == nil {
+		err = ErrNoSessionKey
+		return
+	}
+
+	iv, ciphertext, err = sscrypto.Encrypt(s.aesKey, plain)
+
+	return
+}
+
+// decrypt decrypts ciphertext (encrypted by the SecretService) with the Session key and the IV iv.
+func (s *Session) decrypt(iv, ciphertext []byte) (plain []byte, err error) {
+
+	if s.aesKey == nil {
+		err = ErrNoSessionKey
+		return
+	}
+
+	plain, err = sscrypto.Decrypt(s.aesKey, iv, ciphertext)
+
+	return
+}
+
 // path is a *very* thin wrapper around Session.Dbus.Path().
 func (s *Session) path() (dbusPath dbus.ObjectPath) {
 
@@ -0,0 +1,112 @@
+package gosecret
+
+import (
+	`bytes`
+	`testing`
+
+	`github.com/godbus/dbus/v5`
+	`r00t2.io/gosecret/internal/sscrypto`
+)
+
+/*
+	TestSession_KeyExchange tests the following internal functions/methods via nested calls:
+
+		Session.encodeSecret
+			Session.encrypt
+		Session.decodeSecret
+			Session.decrypt
+
+	It does not require a running SecretService; both "ends" of the exchange are simulated.
+*/
+func TestSession_KeyExchange(t *testing.T) {
+
+	var err error
+	var client *sscrypto.Keypair
+	var server *sscrypto.Keypair
+	var clientKey []byte
+	var serverKey []byte
+	var clientSsn *Session
+	var serverSsn *Session
+	var secret *Secret
+	var wire *Secret
+	var wire2 *Secret
+	var received *Secret
+
+	if client, err = sscrypto.NewKeypair(); err != nil {
+		t.Fatalf("could not generate client keypair: %v", err.Error())
+	}
+	if server, err = sscrypto.NewKeypair(); err != nil {
+		t.Fatalf("could not generate server keypair: %v", err.Error())
+	}
+
+	if clientKey, err = client.DeriveKey(server.PublicKey()); err != nil {
+		t.Fatalf("could not derive client key: %v", err.Error())
+	}
+	if serverKey, err = server.DeriveKey(client.PublicKey()); err != nil {
+		t.Fatalf("could not derive server key: %v", err.Error())
+	}
+	if !bytes.Equal(clientKey, serverKey) {
+		t.Fatalf("derived keys do not match (client %x, server %x)", clientKey, serverKey)
+	}
+
+	if _, err = client.DeriveKey([]byte{0x01}); err != ErrBadPubKey {
+		t.Errorf("degenerate public key was not rejected (err: %v)", err)
+	}
+
+	clientSsn = &Session{
+		DbusObject: &DbusObject{
+			Dbus: (*dbus.Conn)(nil).Object(DbusService, dbus.ObjectPath(DbusNewSessionPath+"s1")),
+		},
+		Algorithm: SessionAlgoDH,
+		aesKey:    clientKey,
+	}
+	serverSsn = &Session{
+		DbusObject: clientSsn.DbusObject,
+		Algorithm:  SessionAlgoDH,
+		aesKey:     serverKey,
+	}
+
+	secret = NewSecret(clientSsn, nil, []byte(testSecretContent), "text/plain")
+
+	if wire, err = clientSsn.encodeSecret(secret); err != nil {
+		t.Fatalf("could not encode secret: %v", err.Error())
+	}
+	if bytes.Equal(wire.Value, secret.Value) {
+		t.Errorf("encoded secret value is not encrypted")
+	}
+	if len(wire.Parameters) != 16 {
+		t.Errorf("encoded secret parameters (IV) is %v bytes; expected 16", len(wire.Parameters))
+	}
+	if string(secret.Value) != testSecretContent {
+		t.Errorf("original secret was modified by Session.encodeSecret")
+	}
+
+	// IVs must be per-secret.
+	if wire2, err = clientSsn.encodeSecret(secret); err != nil {
+		t.Fatalf("could not encode secret: %v", err.Error())
+	}
+	if bytes.Equal(wire.Parameters, wire2.Parameters) {
+		t.Errorf("IV was reused between two encodings of the same secret")
+	}
+
+	received = &Secret{
+		Session:     wire.Session,
+		Parameters:  wire.Parameters,
+		Value:       wire.Value,
+		ContentType: wire.ContentType,
+	}
+	if err = serverSsn.decodeSecret(received); err != nil {
+		t.Fatalf("could not decode secret: %v", err.Error())
+	}
+	if string(received.Value) != testSecretContent {
+		t.Errorf("decoded secret value '%v' does not match original '%v'", string(received.Value), testSecretContent)
+	}
+
+	// Tampered padding should be caught rather than returning garbage.
+	received.Parameters = wire.Parameters
+	received.Value = append([]byte{}, wire.Value...)
+	received.Value[len(received.Value)-1] ^= 0xff
+	if err = serverSsn.decodeSecret(received); err == nil && string(received.Value) == testSecretContent {
+		t.Errorf("tampered secret decoded to the original value")
+	}
+}
@@ -0,0 +1,51 @@
+//go:build go1.21
+// +build go1.21
+
+package gosecret
+
+import (
+	`log/slog`
+)
+
+// LogValue implements slog.LogValuer; the value is always logged as RedactedSecret.
+func (s SecretValue) LogValue() (v slog.Value) {
+
+	v = slog.StringValue(RedactedSecret)
+
+	return
+}
+
+// LogValue implements slog.LogValuer; the Secret is logged as a group with its Value redacted.
+func (s Secret) LogValue() (v slog.Value) {
+
+	v = slog.GroupValue(
+		slog.String("session_path", string(s.Session)),
+		slog.String("content_type", s.ContentType),
+		slog.String("value", RedactedSecret),
+	)
+
+	return
+}
+
+// LogValue implements slog.LogValuer; the Item is logged as a group of its (cached) metadata, without its Secret.
+func (i *Item) LogValue() (v slog.Value) {
+
+	var info ItemInfo
+
+	if i == nil {
+		v = slog.AnyValue(nil)
+		return
+	}
+
+	info = i.Info()
+
+	v = slog.GroupValue(
+		slog.String("path", string(info.Path)),
+		slog.String("label", info.Label),
+		slog.String("type", info.Type),
+		slog.Bool("locked", info.Locked),
+		slog.Any("attributes", info.Attributes),
+	)
+
+	return
+}
@@ -0,0 +1,49 @@
+//go:build go1.21
+// +build go1.21
+
+package gosecret
+
+import (
+	`bytes`
+	`log/slog`
+	`strings`
+	`testing`
+)
+
+/*
+	TestLogValue tests the following internal functions/methods via nested calls:
+
+		SecretValue.LogValue
+		Secret.LogValue
+		Item.LogValue
+
+*/
+func TestLogValue(t *testing.T) {
+
+	var buf bytes.Buffer
+	var logger *slog.Logger
+	var secret *Secret
+	var item *Item
+
+	secret = &Secret{
+		Session:     "/org/freedesktop/secrets/session/1",
+		Value:       SecretValue(testSecretContent),
+		ContentType: "text/plain",
+	}
+	item = &Item{
+		Secret:    secret,
+		LabelName: testItemLabel,
+	}
+
+	for _, h := range []slog.Handler{slog.NewTextHandler(&buf, nil), slog.NewJSONHandler(&buf, nil)} {
+		buf.Reset()
+		logger = slog.New(h)
+		logger.Info("test", "value", secret.Value, "secret", secret, "secret_struct", *secret, "item", item)
+		if strings.Contains(buf.String(), testSecretContent) {
+			t.Errorf("log output contains the secret: %v", buf.String())
+		}
+		if !strings.Contains(buf.String(), RedactedSecret) || !strings.Contains(buf.String(), testItemLabel) {
+			t.Errorf("log output is missing the redacted value or item label: %v", buf.String())
+		}
+	}
+}
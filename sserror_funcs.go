@@ -1,6 +1,10 @@
 package gosecret
 
-// This currently is not used.
+import (
+	"errors"
+
+	"github.com/godbus/dbus/v5"
+)
 
 /*
 	TranslateError translates a SecretServiceErrEnum into a SecretServiceError.
@@ -22,6 +26,29 @@ func TranslateError(ssErr SecretServiceErrEnum) (ok bool, err error) {
 	return
 }
 
+/*
+	TranslateDbusError translates an error returned by a Dbus call to the SecretService into a SecretServiceError.
+	err may be a dbus.Error, a *dbus.Error, or an error wrapping one (e.g. an *OpError).
+	If a matching error was found, ok will be true and ssErr will be the matching SecretServiceError.
+*/
+func TranslateDbusError(err error) (ok bool, ssErr SecretServiceError) {
+
+	var dbusErr dbus.Error
+	var dbusErrPtr *dbus.Error
+
+	if err == nil {
+		return
+	}
+
+	if errors.As(err, &dbusErr) {
+		ssErr, ok = dbusErrMap[dbusErr.Name]
+	} else if errors.As(err, &dbusErrPtr) && dbusErrPtr != nil {
+		ssErr, ok = dbusErrMap[dbusErrPtr.Name]
+	}
+
+	return
+}
+
 // Error returns the string format of the error; this is necessary to be considered a valid error interface.
 func (e SecretServiceError) Error() (errStr string) {
 
@@ -1,6 +1,8 @@
 package gosecret
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"github.com/godbus/dbus/v5"
@@ -23,6 +25,25 @@ type SecretServiceError struct {
 	ErrDesc string `json:"desc"`
 }
 
+/*
+	OpError is returned by every function/method that performs a Dbus call if the call fails.
+	It records which Dbus method (or property) was called on which object path.
+
+	If the SecretService returned a known Dbus error (see TranslateDbusError), Err is the matching
+	SecretServiceError (so e.g. errors.Is(err, ErrSecretServiceLocked) works) and DbusErr is the original error.
+	errors.As(err, &dbusErr) (for a dbus.Error or *dbus.Error) also works if DbusErr is set.
+*/
+type OpError struct {
+	// Op is the Dbus method or property name (e.g. DbusItemGetSecret, DbusItemLabel).
+	Op string `json:"op"`
+	// Path is the Dbus path of the object the operation was performed on.
+	Path dbus.ObjectPath `json:"path"`
+	// Err is the translated SecretServiceError, or the original error if it could not be translated.
+	Err error `json:"err"`
+	// DbusErr is the original error returned by the SecretService, if it was a Dbus error.
+	DbusErr *dbus.Error `json:"-"`
+}
+
 // ConnPathCheckResult contains the result of validConnPath.
 type ConnPathCheckResult struct {
 	// ConnOK is true if the dbus.Conn is valid.
@@ -46,22 +67,127 @@ type DbusObject struct {
 */
 type Prompt struct {
 	*DbusObject
+	/*
+		WindowID is the platform-specific parent window handle passed to the SecretService when the Prompt is shown,
+		so the prompt can be made transient for (i.e. shown on top of) the calling application's window.
+		See X11WindowID and WaylandWindowID. An empty string (the default) indicates no parent window.
+	*/
+	WindowID string `json:"window_id"`
 }
 
+/*
+	Prompter handles a Prompt on behalf of a Service (see Service.Prompter).
+
+	HandlePrompt is called whenever an operation (e.g. Service.Unlock, Collection.CreateItem, Item.Delete)
+	requires a Prompt. It should return the Prompt's result (see Prompt.Prompt) once the Prompt has completed,
+	or an error (e.g. ErrPromptDismissed) if it was not completed.
+*/
+type Prompter interface {
+	HandlePrompt(ctx context.Context, prompt *Prompt) (result *dbus.Variant, err error)
+}
+
+/*
+	InteractivePrompter is a Prompter that shows the Prompt and waits for it to complete
+	(via Prompt.PromptContext). This is the default behavior.
+*/
+type InteractivePrompter struct{}
+
+/*
+	FailFastPrompter is a Prompter that never shows a Prompt.
+	Instead, a *PromptRequiredError is returned; this is useful for headless systems/CI runners
+	where no one would be around to answer a Prompt.
+*/
+type FailFastPrompter struct{}
+
+/*
+	PromptFunc is a callback-based Prompter. The function decides whether and when to drive the Prompt
+	(e.g. via Prompt.PromptContext), or may return an error (such as a *PromptRequiredError from NewPromptRequiredError)
+	to decline it.
+*/
+type PromptFunc func(ctx context.Context, prompt *Prompt) (result *dbus.Variant, err error)
+
+/*
+	PromptRequiredError is returned by FailFastPrompter when an operation requires a Prompt.
+	errors.Is(err, ErrPromptRequired) is true for it.
+*/
+type PromptRequiredError struct {
+	// Path is the Dbus path of the Prompt that was required.
+	Path dbus.ObjectPath `json:"path"`
+	// Prompt is the Prompt that was required; it may be driven later (e.g. via Prompt.Prompt) if desired.
+	Prompt *Prompt `json:"-"`
+}
+
+// LockableObject is an object that can be locked/unlocked via Service.Lock and Service.Unlock (i.e. a Collection or an Item).
 type LockableObject interface {
 	Locked() (bool, error)
+	LockedContext(ctx context.Context) (bool, error)
 	path() dbus.ObjectPath
 }
 
+/*
+	LockResult reports what a Service.Lock or Service.Unlock call did to each of the requested objects.
+	Each slice keeps the order the objects were passed in.
+*/
+type LockResult struct {
+	// Immediate are the objects whose state was changed (or already was as requested) without a Prompt.
+	Immediate []LockableObject
+	// Prompted are the objects that required a Prompt.
+	Prompted []LockableObject
+	/*
+		PromptAffected are the Prompted objects that the completed Prompt reports as changed.
+		If the SecretService does not report them, the objects' refreshed lock state is used instead.
+	*/
+	PromptAffected []LockableObject
+	// Failed are the objects whose state was not changed (or could not be determined afterwards), with the reason.
+	Failed []*LockFailure
+}
+
+// LockFailure records why an object in a LockResult was not locked/unlocked. It is also an error.
+type LockFailure struct {
+	// Object is the object that was not locked/unlocked.
+	Object LockableObject
+	/*
+		Err is the reason, e.g. ErrPromptDismissed, ErrLockStateUnchanged or the error from refreshing
+		the object's lock state.
+	*/
+	Err error
+}
+
 /*
 	Service is a general SecretService interface, sort of handler for Dbus - it's used for fetching a Session, Collections, etc.
 	https://developer-old.gnome.org/libsecret/0.18/SecretService.html
 	https://specifications.freedesktop.org/secret-service/latest/re01.html
+
+	A Service (and the Collection and Item objects obtained from it) is safe for concurrent use by multiple goroutines,
+	provided its exported fields are not modified after NewService returns. Legacy and Capabilities may be changed
+	by Service.DetectCapabilities; use Service.IsLegacy and Service.GetCapabilities to read them safely.
+	Session may be replaced when reconnecting (see WithReconnect).
 */
 type Service struct {
 	*DbusObject
-	// Session is a default Session initiated automatically.
+	/*
+		Session is a default Session initiated automatically.
+		With WithReconnect it is replaced if the SecretService restarts; Secret objects and method calls
+		using the old Session switch to the new one automatically.
+	*/
 	Session *Session `json:"-"`
+	/*
+		WindowID, if set, is used as the Prompt.WindowID for any Prompt issued by this Service
+		(or by a Collection/Item created from this Service). See X11WindowID and WaylandWindowID.
+	*/
+	WindowID string `json:"window_id"`
+	/*
+		Prompter handles any Prompt required by an operation performed via this Service
+		(or a Collection/Item created from this Service).
+		If nil, an InteractivePrompter is used.
+	*/
+	Prompter Prompter `json:"-"`
+	/*
+		Parallelism is the maximum number of objects constructed concurrently by e.g. Service.Collections,
+		Service.SearchItems and Collection.Items. If less than 1, DefaultParallelism is used.
+		Set to 1 to construct objects serially. See WithParallelism.
+	*/
+	Parallelism int `json:"-"`
 	// IsLocked indicates if the Service is locked or not. Status updated by Service.Locked.
 	IsLocked bool `json:"locked"`
 	/*
@@ -69,12 +195,13 @@ type Service struct {
 		by implementing the legacy/obsolete draft spec rather than current libsecret spec
 		for the Dbus API.
 
-		If you're using SecretService with KeePassXC, for instance, or a much older version
+		NewService sets this automatically from the detected Capabilities (see Service.DetectCapabilities).
+		If detection is not possible (e.g. the implementation has no Items yet or does not support
+		introspection) and you're using SecretService with KeePassXC, for instance, or a much older version
 		of Gnome-Keyring *before* libsecret integration(?),	or if you are getting strange errors
-		when performing a Service.SearchItems, you probably need to enable this field on the
-		Service returned by NewService. The coverage of this field may expand in the future, but
-		currently it only prevents/suppresses the (non-existent, in legacy spec) Type property
-		from being read or written on Items during e.g.:
+		when performing a Service.SearchItems, you probably need to enable this field (see WithLegacy).
+		The coverage of this field may expand in the future, but currently it only prevents/suppresses the (non-existent,
+		in legacy spec) Type property from being read or written on Items during e.g.:
 
 			Service.SearchItems
 			Collection.CreateItem
@@ -89,6 +216,124 @@ type Service struct {
 		a non-nil err to determine if this Service is (probably) interfacing with a legacy spec API.
 	*/
 	Legacy bool `json:"is_legacy"`
+	/*
+		LoadedCollections contains the Collection objects loaded by NewService
+		if FlagServiceLoadCollections was specified (see WithFlags).
+	*/
+	LoadedCollections []*Collection `json:"collections,omitempty"`
+	/*
+		Capabilities describes the SecretService implementation, as detected by NewService
+		(unless WithoutCapabilityDetection was used) or Service.DetectCapabilities.
+	*/
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+	// ownsConn is true if the Service opened (and should therefore close) DbusObject.Conn.
+	ownsConn bool
+	// reconnect is the automatic reconnect state (WithReconnect); it is nil if reconnecting is disabled.
+	reconnect *reconnector
+	// lockedMemory is true if fetched Secret values are stored in locked memory (WithLockedMemory).
+	lockedMemory bool
+	// lock guards Session, Legacy and Capabilities, which may be changed by Service.DetectCapabilities and reconnecting.
+	lock sync.RWMutex
+	// upsertLocks serialize Collection.Upsert calls for the same Collection (by path); see Service.upsertLock.
+	upsertLocks map[dbus.ObjectPath]*sync.Mutex
+	// upsertLocksLock guards upsertLocks.
+	upsertLocksLock sync.Mutex
+}
+
+/*
+	ReconnectHandler is called (in its own goroutine) after a Service has re-established its Session
+	because the SecretService restarted. See WithReconnect.
+*/
+type ReconnectHandler func(evt *ReconnectEvent)
+
+// ReconnectEvent describes a Session re-establishment. See WithReconnect.
+type ReconnectEvent struct {
+	// Owner is the unique bus name of the (new) SecretService process, if known.
+	Owner string `json:"owner"`
+	// Generation is incremented for each re-establishment; see Collection.Stale and Item.Stale.
+	Generation uint64 `json:"generation"`
+	// Session is the new Service.Session. It is nil if the Service had no Session or if Err is non-nil.
+	Session *Session `json:"-"`
+	// Err is non-nil if the Session could not be re-established; the next retried call will try again.
+	Err error `json:"-"`
+}
+
+// reconnector tracks the state for WithReconnect.
+type reconnector struct {
+	// handler is the optional ReconnectHandler.
+	handler ReconnectHandler
+	// owner is the last known unique bus name of the SecretService; only used by the watcher goroutine.
+	owner string
+	// lock serializes re-establishment.
+	lock sync.Mutex
+	// generation is incremented on every re-establishment. Accessed atomically.
+	generation uint64
+	// cancel stops the NameOwnerChanged watcher.
+	cancel context.CancelFunc
+	// done is closed when the NameOwnerChanged watcher has stopped.
+	done chan struct{}
+}
+
+/*
+	Option configures a Service created via NewService (e.g. WithConn, WithDestination).
+	Options are applied in the order given.
+*/
+type Option func(opts *serviceOpts) (err error)
+
+// serviceOpts are the options for NewService, as set by Option functions.
+type serviceOpts struct {
+	// conn is an existing connection (WithConn).
+	conn *dbus.Conn
+	// busAddress is the address of a bus to connect to (WithBusAddress).
+	busAddress string
+	// destination is the SecretService's bus name (WithDestination).
+	destination string
+	// legacy sets Service.Legacy (WithLegacy).
+	legacy bool
+	// sessionAlgo is the algorithm for the default Session (WithSessionAlgorithm); if empty, negotiated.
+	sessionAlgo string
+	// noSession disables opening the default Session (WithoutDefaultSession).
+	noSession bool
+	// flags are the ServiceInitFlag flags (WithFlags).
+	flags []ServiceInitFlag
+	// noDetect disables capability detection (WithoutCapabilityDetection).
+	noDetect bool
+	// parallelism sets Service.Parallelism (WithParallelism).
+	parallelism int
+	// waitProvider enables activating/waiting for the SecretService (WithWaitForProvider).
+	waitProvider bool
+	// waitTimeout is the timeout for waitProvider (WithWaitForProvider); 0 means only the context deadline applies.
+	waitTimeout time.Duration
+	// reconnect enables automatic reconnecting (WithReconnect).
+	reconnect bool
+	// reconnectHandler is the ReconnectHandler (WithReconnect).
+	reconnectHandler ReconnectHandler
+	// lockedMemory enables allocating Secret values in locked memory (WithLockedMemory).
+	lockedMemory bool
+}
+
+// Capabilities describes what a SecretService implementation supports. See Service.DetectCapabilities.
+type Capabilities struct {
+	// Provider is the implementation, if it could be identified.
+	Provider ProviderID `json:"provider"`
+	// ProcessName is the name of the process owning the SecretService bus name, if it could be determined.
+	ProcessName string `json:"process_name,omitempty"`
+	// Legacy is true if the implementation follows the legacy draft spec (i.e. Items have no Type property).
+	Legacy bool `json:"legacy"`
+	// ItemType is true if Items have the Type property.
+	ItemType bool `json:"item_type"`
+	// Aliases is true if the Service supports ReadAlias/SetAlias.
+	Aliases bool `json:"aliases"`
+	/*
+		MasterPassword is true if the Service offers gnome-keyring's DbusInterfaceGnomeKeyring (see
+		Collection.UnlockWithPassword, Collection.ChangePassword and Service.CreateCollectionWithPassword).
+	*/
+	MasterPassword bool `json:"master_password"`
+	/*
+		Partial is true if detection was incomplete; e.g. the implementation does not support introspection
+		or there were no Items to introspect. In that case Legacy/ItemType are assumptions (current spec).
+	*/
+	Partial bool `json:"partial"`
 }
 
 /*
@@ -98,13 +343,45 @@ type Service struct {
 */
 type Session struct {
 	*DbusObject
+	// Algorithm is the transport algorithm negotiated for this Session (SessionAlgoPlain or SessionAlgoDH).
+	Algorithm string `json:"algorithm"`
 	// service tracks the Service this Session was created from.
 	service *Service
+	// aesKey is the key derived from the DH exchange (if Algorithm is SessionAlgoDH); it is nil for plain Sessions.
+	aesKey []byte
+	// invalid is set (atomically) when the Session has been replaced after the SecretService restarted (see WithReconnect).
+	invalid uint32
+}
+
+/*
+	WatchEvent is a change notification from the SecretService, as delivered by Service.Watch and Collection.Watch.
+	https://specifications.freedesktop.org/secret-service/latest/re01.html (Signals)
+	https://specifications.freedesktop.org/secret-service/latest/re02.html (Signals)
+*/
+type WatchEvent struct {
+	// Type is the type of change.
+	Type EventType `json:"type"`
+	// Path is the Dbus path of the Collection or Item that changed.
+	Path dbus.ObjectPath `json:"path"`
+	/*
+		Collection is the Collection that changed (for EventCollection* events) or the Collection
+		containing the Item that changed (for EventItem* events).
+		It is nil for EventCollectionDeleted or if it could not be resolved (see Err).
+	*/
+	Collection *Collection `json:"-"`
+	// Item is the Item that changed. It is nil for EventCollection* events, EventItemDeleted, or if it could not be resolved (see Err).
+	Item *Item `json:"-"`
+	// Err is non-nil if the Collection and/or Item could not be resolved.
+	Err error `json:"-"`
 }
 
 /*
 	Collection is an accessor for libsecret collections, which contain multiple Secret Item items.
 	Do not change any of these values directly; use the associated methods instead.
+
+	A Collection is safe for concurrent use by multiple goroutines. The exported fields are updated
+	by its methods (e.g. Collection.Label) while holding an internal lock, so reading them directly
+	is NOT safe if the Collection is shared between goroutines; use Collection.Info instead.
 	Reference:
 	https://developer-old.gnome.org/libsecret/0.18/SecretCollection.html
 	https://specifications.freedesktop.org/secret-service/latest/ch03.html
@@ -121,20 +398,51 @@ type Collection struct {
 	LastModified time.Time `json:"modified"`
 	// Alias is the Collection's alias (as handled by Service.ReadAlias and Service.SetAlias).
 	Alias string `json:"alias"`
+	/*
+		LoadedItems contains the Item objects loaded by NewCollection
+		if FlagCollectionLoadItems was specified.
+	*/
+	LoadedItems []*Item `json:"items,omitempty"`
 	// lastModifiedSet is unexported; it's only used to determine if this is a first-initialization of the modification time or not.
 	lastModifiedSet bool
 	// service tracks the Service this Collection was created from.
 	service *Service
+	// generation is the Service reconnect generation the cached fields were last loaded in (see Collection.Stale).
+	generation uint64
+	// lock guards the cached (exported) fields.
+	lock sync.RWMutex
+}
+
+// CollectionInfo is a copy of a Collection's cached fields at a point in time. See Collection.Info.
+type CollectionInfo struct {
+	// Path is the Collection's Dbus path.
+	Path dbus.ObjectPath `json:"path"`
+	// Label is Collection.LabelName.
+	Label string `json:"label"`
+	// Alias is Collection.Alias.
+	Alias string `json:"alias"`
+	// Locked is Collection.IsLocked.
+	Locked bool `json:"locked"`
+	// Created is Collection.CreatedAt.
+	Created time.Time `json:"created"`
+	// Modified is Collection.LastModified.
+	Modified time.Time `json:"modified"`
 }
 
 /*
 	Item is an entry in a Collection that contains a Secret. Do not change any of these values directly; use the associated methods instead.
+
+	An Item is safe for concurrent use by multiple goroutines. As with Collection, reading the exported
+	fields directly is NOT safe if the Item is shared between goroutines; use Item.Info instead.
 	https://developer-old.gnome.org/libsecret/0.18/SecretItem.html
 	https://specifications.freedesktop.org/secret-service/latest/re03.html
 */
 type Item struct {
 	*DbusObject
-	// Secret is the corresponding Secret object.
+	/*
+		Secret is the corresponding Secret object.
+		It is nil until Item.GetSecret is called unless the Item was loaded with FlagItemLoadSecret (see NewItem).
+	*/
 	Secret *Secret `json:"secret"`
 	// IsLocked indicates if the Item is locked or not. Status updated by Item.Locked.
 	IsLocked bool
@@ -158,6 +466,84 @@ type Item struct {
 	idx int
 	// collection tracks the Collection this Item is in.
 	collection *Collection
+	// generation is the Service reconnect generation the cached fields were last loaded in (see Item.Stale).
+	generation uint64
+	// lock guards the cached (exported) fields.
+	lock sync.RWMutex
+	// casLock serializes Item.SetSecretIfUnmodified and Item.ReplaceAttributesIfUnmodified.
+	casLock sync.Mutex
+}
+
+/*
+	ItemInfo is a copy of an Item's cached fields at a point in time. See Item.Info.
+	Attributes and Secret are deep copies; modifying them does not affect the Item.
+	Secret never holds the Secret's Value; see Item.Info.
+*/
+type ItemInfo struct {
+	// Path is the Item's Dbus path.
+	Path dbus.ObjectPath `json:"path"`
+	// Label is Item.LabelName.
+	Label string `json:"label"`
+	// Type is Item.SecretType.
+	Type string `json:"type"`
+	// Attributes is Item.Attrs.
+	Attributes map[string]string `json:"attributes"`
+	// Secret is Item.Secret without its Value; it is nil if the Secret has not been fetched.
+	Secret *Secret `json:"secret,omitempty"`
+	// Locked is Item.IsLocked.
+	Locked bool `json:"locked"`
+	// Created is Item.CreatedAt.
+	Created time.Time `json:"created"`
+	// Modified is Item.LastModified.
+	Modified time.Time `json:"modified"`
+}
+
+/*
+	ItemSnapshot records the state of an Item at a point in time (see Item.Snapshot) for use with
+	Item.SetSecretIfUnmodified and Item.ReplaceAttributesIfUnmodified.
+*/
+type ItemSnapshot struct {
+	// Modified is the Item's modification time as reported by the SecretService.
+	Modified time.Time `json:"modified"`
+	/*
+		Hash is a SHA-256 hash of the Item's label, type, attributes and Secret.
+		It is nil unless requested; it catches changes that Modified can't (SecretService timestamps
+		only have a resolution of one second).
+	*/
+	Hash []byte `json:"hash,omitempty"`
+}
+
+/*
+	MarshalOptions controls JSON marshaling of Secret values (see MarshalOptions.Marshal).
+	By default (e.g. via json.Marshal), SecretValue is marshaled as RedactedSecret.
+*/
+type MarshalOptions struct {
+	// Reveal includes the actual Secret values in the output, e.g. for exporting Items.
+	Reveal bool
+	// Prefix and Indent are as in json.MarshalIndent. If both are empty, the output is compact.
+	Prefix string
+	Indent string
+}
+
+/*
+	Schema describes the attributes of a kind of password (see Service.PasswordStore), mirroring libsecret's SecretSchema.
+	It is usually defined once, as a package-level variable:
+
+		var exampleSchema *gosecret.Schema = &gosecret.Schema{
+			Name: "org.example.Password",
+			Attributes: map[string]gosecret.SchemaAttrType{
+				"user": gosecret.SchemaAttrString,
+				"port": gosecret.SchemaAttrInteger,
+			},
+		}
+*/
+type Schema struct {
+	// Name is stored in every Item's SchemaNameAttr attribute. It is conventionally a reverse-DNS name.
+	Name string `json:"name"`
+	// Flags change how the Schema is matched; see FlagSchemaDontMatchName.
+	Flags SchemaFlag `json:"flags"`
+	// Attributes are the attribute names the Schema allows, and the type of their values.
+	Attributes map[string]SchemaAttrType `json:"attributes"`
 }
 
 /*
@@ -170,11 +556,13 @@ type Secret struct {
 	// Session is a Dbus object path for the associated Session (the actual Session is stored in an unexported field).
 	Session dbus.ObjectPath `json:"session_path"`
 	/*
-		Parameters are "algorithm dependent parameters for secret value encoding" - likely this will just be an empty byteslice.
+		Parameters are "algorithm dependent parameters for secret value encoding".
+		For a Session using SessionAlgoPlain this is an empty byteslice;
+		for SessionAlgoDH it is the AES IV used to encrypt Value in transit.
 		Refer to Session for more information.
 	*/
 	Parameters []byte `json:"params"`
-	// Value is the secret's content in []byte format.
+	// Value is the secret's (decrypted) content in []byte format.
 	Value SecretValue `json:"value"`
 	// ContentType is the MIME type of Value.
 	ContentType string `json:"content_type"`
@@ -184,5 +572,39 @@ type Secret struct {
 	session *Session
 }
 
-// SecretValue is a custom type that handles JSON encoding a little more easily.
+// revealedValue is a SecretValue that is marshaled to JSON as-is (see MarshalOptions).
+type revealedValue SecretValue
+
+// revealedSecret shadows Secret.Value with a revealedValue (see MarshalOptions).
+type revealedSecret struct {
+	*Secret
+	Value revealedValue `json:"value"`
+	// Encoding is ValueEncodingBase64 if Value is not valid UTF-8 (and is therefore marshaled as base64).
+	Encoding string `json:"value_encoding,omitempty"`
+}
+
+// revealedItem marshals an Item with its Secret value revealed (see MarshalOptions).
+type revealedItem struct {
+	item *Item
+}
+
+// revealedItemFields shadows Item.Secret with a revealedSecret (see revealedItem.MarshalJSON).
+type revealedItemFields struct {
+	*Item
+	Secret *revealedSecret `json:"secret"`
+}
+
+// redactedSecret mirrors the exported fields of Secret for Secret.Format (without its methods, to avoid recursing).
+type redactedSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       SecretValue
+	ContentType string
+}
+
+/*
+	SecretValue is a custom type that handles JSON encoding a little more easily.
+	It is redacted (see RedactedSecret) when formatted or marshaled to JSON; use MarshalOptions to export it.
+	Use SecretValue.Wipe to zero it once it is no longer needed.
+*/
 type SecretValue []byte